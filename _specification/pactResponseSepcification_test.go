@@ -71,7 +71,7 @@ func testResponseCase(t *testing.T, fileName string) {
 		t.FailNow()
 	}
 
-	diffs, err := comparers.MatchResponse(tc.Expected, tc.Actual)
+	diffs, err := comparers.MatchResponse(tc.Expected, tc.Actual, nil)
 	match := (len(diffs) == 0)
 	if err != nil {
 		t.Error(err)
@@ -0,0 +1,65 @@
+package pact
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+var (
+	noteChangesSinceMsg      = "Verified %d of the %d interactions with %s, the others are unchanged since consumer version %s."
+	noteNoPreviousVersionMsg = "No pact with %s was verified at consumer version %s, all the interactions were verified."
+)
+
+//VerifyChangesSince verifies only the interactions which differ from the pact of the previous consumer version on the
+//pact broker, typically the version last verified by the deployed provider. The pacts of the consumers without a pact
+//at the previous version are verified in full, which is noted in the result
+func (v *pactFileVerfier) VerifyChangesSince(brokerURL, previousVersion string) error {
+	v.PactBroker(brokerURL, v.brokerAuth)
+
+	v.changesSince = previousVersion
+	defer func() { v.changesSince = "" }()
+	return v.Verify()
+}
+
+//filterUnchanged removes the interactions of the pacts which are identical in the pact of the previous consumer version
+func (v *pactFileVerfier) filterUnchanged(pacts []*loadedPact) ([]string, error) {
+	var notes []string
+	for _, p := range pacts {
+		if p.origin != PactOriginBroker {
+			continue
+		}
+		name := p.file.Consumer.Name
+		uri := io.BrokerPactUri(v.brokerURL, v.brokerProvider(p.file), name, v.changesSince)
+		previous, err := io.NewPactWebReaderWithOptions(uri, v.brokerOptions()).Read()
+		if _, ok := err.(*io.NotFoundError); ok {
+			notes = append(notes, fmt.Sprintf(noteNoPreviousVersionMsg, name, v.changesSince))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		unchanged := make(map[string]bool)
+		for _, i := range previous.Interactions {
+			unchanged[interactionKey(i)] = true
+		}
+		total := len(p.file.Interactions)
+		var changed []*consumer.Interaction
+		for _, i := range p.file.Interactions {
+			if !unchanged[interactionKey(i)] {
+				changed = append(changed, i)
+			}
+		}
+		p.file.Interactions = changed
+		notes = append(notes, fmt.Sprintf(noteChangesSinceMsg, len(changed), total, name, v.changesSince))
+	}
+	return notes, nil
+}
+
+//interactionKey identifies the interaction by its recorded content
+func interactionKey(i *consumer.Interaction) string {
+	b, _ := json.Marshal(i)
+	return string(b)
+}
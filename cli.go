@@ -0,0 +1,146 @@
+package pact
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	goio "io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	//cliErrorExitCode is returned when the verification could not be run, e.g. for invalid flags
+	cliErrorExitCode = 255
+	//cliMaxFailuresExitCode caps the number of failing interactions returned as the exit code
+	cliMaxFailuresExitCode = 254
+	//cliStdinPact is the -pact uri reading the pact from stdin
+	cliStdinPact = "-"
+)
+
+var (
+	errCLINoProviderURL    = errors.New("The provider url is required, please provide it using the -provider-url flag.")
+	errCLIUnknownReportMsg = "Unknown report format '%s', expected json or junit."
+)
+
+//uriList is a flag which can be repeated to collect multiple uris
+type uriList []string
+
+func (l *uriList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *uriList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+//RunCLI verifies the pacts configured by the command line arguments and returns the process exit code, which is
+//the number of failing interactions, or 1 when -fail-exit-code-one is set. Run with -h for the available flags
+func RunCLI(args []string) int {
+	return runCLI(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+func runCLI(args []string, stdin goio.Reader, stdout, stderr goio.Writer) int {
+	var pacts uriList
+	fs := flag.NewFlagSet("pact-verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Var(&pacts, "pact", "uri of a pact file to verify, can be repeated, - reads the pact from stdin")
+	pactDir := fs.String("pact-dir", "", "directory whose pact files are all verified")
+	providerName := fs.String("provider", "", "name of the provider")
+	providerURL := fs.String("provider-url", "", "base url of the provider, a unix:// url dials a unix socket")
+	consumerName := fs.String("consumer", "", "name of the consumer when verifying the pact from the broker")
+	brokerURL := fs.String("broker", "", "base url of the pact broker")
+	brokerUser := fs.String("broker-username", "", "username of the pact broker")
+	brokerPassword := fs.String("broker-password", "", "password of the pact broker")
+	brokerToken := fs.String("broker-token", "", "bearer token of the pact broker, when there is no username and password")
+	consumerVersion := fs.String("consumer-version", "", "version of the consumer pact on the broker")
+	environment := fs.String("environment", "", "verify the consumers deployed to the broker environment")
+	publishVersion := fs.String("publish-results", "", "provider version the verification results are published to the broker for")
+	stateChangeURL := fs.String("state-change-url", "", "url receiving the provider state setups and teardowns")
+	report := fs.String("report", "", "report written to stdout after the summary, json or junit")
+	smoke := fs.Bool("smoke", false, "verify only the status and headers of the responses, not their bodies")
+	exitOne := fs.Bool("fail-exit-code-one", false, "exit with 1 instead of the number of failing interactions")
+	if err := fs.Parse(args); err != nil {
+		return cliErrorExitCode
+	}
+
+	fail := func(err error) int {
+		fmt.Fprintln(stderr, err)
+		return cliErrorExitCode
+	}
+	if *providerURL == "" {
+		return fail(errCLINoProviderURL)
+	}
+	u, err := url.Parse(*providerURL)
+	if err != nil {
+		return fail(err)
+	}
+	if *report != "" && *report != "json" && *report != "junit" {
+		return fail(fmt.Errorf(errCLIUnknownReportMsg, *report))
+	}
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: DefaultLogger}).
+		ServiceProvider(*providerName, &http.Client{}, u).
+		SummaryWriter(stdout)
+	if *consumerName != "" {
+		v.HonoursPactWith(*consumerName)
+	}
+	for _, p := range pacts {
+		if p == cliStdinPact {
+			v.PactReader(stdin)
+		} else {
+			v.AddPact(p, nil)
+		}
+	}
+	if *pactDir != "" {
+		v.PactDir(*pactDir)
+	}
+	if *brokerURL != "" {
+		v.PactBroker(*brokerURL, &BrokerAuth{Username: *brokerUser, Password: *brokerPassword, Token: *brokerToken})
+	}
+	if *consumerVersion != "" {
+		v.ConsumerVersion(*consumerVersion)
+	}
+	if *environment != "" {
+		v.ForEnvironment(*environment)
+	}
+	if *publishVersion != "" {
+		v.PublishResults(*publishVersion)
+	}
+	if *stateChangeURL != "" {
+		su, err := url.Parse(*stateChangeURL)
+		if err != nil {
+			return fail(err)
+		}
+		v.StateChangeURL(su)
+	}
+	if *smoke {
+		v.SmokeMode(true)
+	}
+
+	if err := v.Verify(); err != nil && !errors.Is(err, errVerficationFailed) {
+		return fail(err)
+	}
+
+	r := v.Result()
+	switch *report {
+	case "json":
+		err = r.WriteJSONReport(stdout)
+	case "junit":
+		err = r.WriteJUnitReport(stdout)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	failures := len(r.Failures())
+	if failures > 0 && *exitOne {
+		return 1
+	} else if failures > cliMaxFailuresExitCode {
+		return cliMaxFailuresExitCode
+	}
+	return failures
+}
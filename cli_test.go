@@ -0,0 +1,92 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_CLI_ReturnsZeroWhenVerified(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/_pact/state", func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-pact", "./pact_examples/chrome_browser-go_api.json", "-provider", "go api",
+		"-provider-url", server.URL, "-state-change-url", server.URL + "/_pact/state"}
+	if code := runCLI(args, nil, &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "2 interactions, 2 passed, 0 failed") {
+		t.Errorf("expected the summary, got %q", stdout.String())
+	}
+}
+
+func Test_CLI_ReturnsNumberOfFailingInteractions(t *testing.T) {
+	path, cleanup := writeCommentedPact(t)
+	defer cleanup()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-pact", path, "-pact", path, "-provider", "go api", "-provider-url", server.URL, "-report", "json"}
+	if code := runCLI(args, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2, got %d: %s", code, stderr.String())
+	}
+
+	report := stdout.String()[strings.Index(stdout.String(), "{"):]
+	var r jsonReport
+	if err := json.Unmarshal([]byte(report), &r); err != nil {
+		t.Fatal(err)
+	} else if r.Failed != 2 {
+		t.Errorf("expected the report to list 2 failures, got %d", r.Failed)
+	}
+
+	stdout.Reset()
+	if code := runCLI(append(args, "-fail-exit-code-one"), nil, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+}
+
+func Test_CLI_ReadsPactFromStdin(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/_pact/state", func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	pact, err := ioutil.ReadFile("./pact_examples/chrome_browser-go_api.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	args := []string{"-pact", "-", "-provider", "go api", "-provider-url", server.URL, "-state-change-url", server.URL + "/_pact/state"}
+	if code := runCLI(args, bytes.NewReader(pact), &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "2 interactions, 2 passed, 0 failed") {
+		t.Errorf("expected the summary, got %q", stdout.String())
+	}
+}
+
+func Test_CLI_ReturnsErrorExitCode(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"-pact", "./pact_examples/chrome_browser-go_api.json"}, nil, &stdout, &stderr); code != cliErrorExitCode {
+		t.Errorf("expected exit code %d, got %d", cliErrorExitCode, code)
+	}
+	if !strings.Contains(stderr.String(), errCLINoProviderURL.Error()) {
+		t.Errorf("expected the missing provider url error, got %q", stderr.String())
+	}
+
+	if code := runCLI([]string{"-unknown"}, nil, &stdout, &stderr); code != cliErrorExitCode {
+		t.Errorf("expected exit code %d for an unknown flag, got %d", cliErrorExitCode, code)
+	}
+}
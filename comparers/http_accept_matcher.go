@@ -0,0 +1,59 @@
+package comparers
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/diff"
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
+
+//MatchAccept checks the content type of the actual response is acceptable to the accept header of the request,
+//a request without an accept header or a response without a content type always match
+func MatchAccept(request *provider.Request, actual *provider.Response) diff.Differences {
+	accept := headerValue(request.Headers, "Accept")
+	contentType := headerValue(actual.Headers, "Content-Type")
+	if accept == "" || contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	for _, r := range strings.Split(accept, ",") {
+		if acceptsMediaType(r, mediaType) {
+			return nil
+		}
+	}
+	return diff.Differences{diff.NotAcceptableMismatch(accept, contentType)}
+}
+
+//acceptsMediaType reports whether the media range of an accept header, e.g. text/*;q=0.5, accepts the media type
+func acceptsMediaType(mediaRange, mediaType string) bool {
+	r, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+	if err != nil {
+		return false
+	}
+	if q, ok := params["q"]; ok {
+		if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+			return false
+		}
+	}
+	if r == "*/*" || r == mediaType {
+		return true
+	}
+	prefix := strings.TrimSuffix(r, "*")
+	return strings.HasSuffix(r, "/*") && strings.HasPrefix(mediaType, prefix)
+}
+
+//headerValue returns the comma joined values of the header, the name is matched case-insensitively
+func headerValue(headers map[string][]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return strings.Join(v, ", ")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package comparers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
+
+func Test_AcceptMatcher_MatchesMediaRanges(t *testing.T) {
+	tests := []struct {
+		accept, contentType string
+		matches             bool
+	}{
+		{"", "application/xml", true},
+		{"application/json", "", true},
+		{"application/json", "application/json; charset=utf-8", true},
+		{"application/xml, application/json;q=0.5", "application/json", true},
+		{"text/*", "text/xml", true},
+		{"*/*", "image/png", true},
+		{"application/json", "application/xml", false},
+		{"text/*", "application/xml", false},
+		{"application/xml;q=0", "application/xml", false},
+	}
+
+	for _, test := range tests {
+		req := provider.NewRequest("GET", "/", "", nil)
+		if test.accept != "" {
+			req.Headers = http.Header{"accept": {test.accept}}
+		}
+		resp := provider.NewResponse(200, nil)
+		if test.contentType != "" {
+			resp.Headers = http.Header{"Content-Type": {test.contentType}}
+		}
+		if d := MatchAccept(req, resp); (len(d) == 0) != test.matches {
+			t.Errorf("expected accept %q matching %q to be %v, got %v", test.accept, test.contentType, test.matches, d)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package comparers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+
+	"github.com/SEEK-Jobs/pact-go/diff"
+	"github.com/SEEK-Jobs/pact-go/matchers"
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
+
+//binaryBodyRules returns the rule set matching the whole body as binary, nil when the body is not binary
+func binaryBodyRules(expected *provider.Response) *matchers.RuleSet {
+	if rs := expected.MatchingRules[matchers.BodyCategory].Resolve(nil); rs != nil && rs.IsBinary() {
+		return rs
+	}
+	return nil
+}
+
+//binaryExampleRules returns the rule set matching the body against the binary body recorded by the consumer by
+//its hash, nil when the expected body is not binary
+func binaryExampleRules(expected *provider.Response) *matchers.RuleSet {
+	data, ok := expected.GetBody().([]byte)
+	if !ok {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	return &matchers.RuleSet{Matchers: []*matchers.Rule{{Match: "binary", Sha256: hex.EncodeToString(sum[:])}}}
+}
+
+//binaryBodyMatches checks the content type and the hash or non-emptiness of a binary body, the body recorded
+//by the consumer is not compared
+func binaryBodyMatches(rs *matchers.RuleSet, actual *provider.Response) (bool, diff.Differences) {
+	for _, m := range rs.Matchers {
+		if m.ContentType == "" {
+			continue
+		}
+		mediaType, _, _ := mime.ParseMediaType(actual.Headers.Get("Content-Type"))
+		if res, d := diff.DeepDiff(m.ContentType, mediaType, &diff.DiffConfig{RootPath: "[\"header\"][\"content-type\"]"}); !res {
+			return res, d
+		}
+	}
+	return diff.MatchRule(nil, actual.GetBody(), rs, "[\"body\"]")
+}
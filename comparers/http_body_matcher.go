@@ -1,14 +1,34 @@
 package comparers
 
-import "github.com/SEEK-Jobs/pact-go/diff"
+import (
+	"github.com/SEEK-Jobs/pact-go/diff"
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
 
-func bodyMatches(expected, actual interface{}, allowUnexpectedKeys bool, expectedBody bool) (bool, diff.Differences, error) {
+func bodyMatches(expected, actual interface{}, expectedBody bool, conf diff.DiffConfig) (bool, diff.Differences, error) {
 	if expected == nil && !expectedBody {
 		return true, nil, nil
 	}
-	if result, diffs := diff.DeepDiff(expected, actual, &diff.DiffConfig{AllowUnexpectedKeys: allowUnexpectedKeys, RootPath: "[\"body\"]"}); result {
+	//an empty expected body only matches an empty actual body
+	if isEmptyBody(expected) && isEmptyBody(actual) {
+		return true, nil, nil
+	}
+	conf.RootPath = "[\"body\"]"
+	if result, diffs := diff.DeepDiff(expected, actual, &conf); result {
 		return result, nil, nil
 	} else {
 		return result, diffs, nil
 	}
 }
+
+//noBodyMatches checks the actual response has no body when none is expected and extra bodies are not allowed
+func noBodyMatches(expected, actual *provider.Response, conf *MatchConfig) (bool, diff.Differences) {
+	if !conf.NoExtraBody || expected.BodyHasToBeSerialized() || isEmptyBody(actual.GetBody()) {
+		return true, nil
+	}
+	return diff.DeepDiff(nil, actual.GetBody(), &diff.DiffConfig{RootPath: "[\"body\"]"})
+}
+
+func isEmptyBody(body interface{}) bool {
+	return body == nil || body == ""
+}
@@ -1,27 +1,73 @@
 package comparers
 
 import (
+	"fmt"
+	"mime"
 	"strings"
 
 	"github.com/SEEK-Jobs/pact-go/diff"
 )
 
-func headerMatches(expected, actual map[string][]string) (bool, diff.Differences) {
+//headerMatches compares the headers case-insensitively, the ignored headers are not compared
+func headerMatches(expected, actual map[string][]string, ignored []string) (bool, diff.Differences) {
 	if expected == nil {
 		return true, nil
 	}
 
+	ignore := make(map[string]bool, len(ignored))
+	for _, h := range ignored {
+		ignore[strings.ToLower(h)] = true
+	}
+
 	normalisedExpected := make(map[string][]string, len(expected))
 	for key, val := range expected {
-		normalisedExpected[strings.ToLower(key)] = val
+		if k := strings.ToLower(key); !ignore[k] {
+			normalisedExpected[k] = val
+		}
 	}
 	var normalisedActual map[string][]string
 	if actual != nil {
 		normalisedActual = make(map[string][]string)
 		for key, val := range actual {
-			normalisedActual[strings.ToLower(key)] = val
+			if k := strings.ToLower(key); !ignore[k] {
+				normalisedActual[k] = val
+			}
+		}
+	}
+
+	//the boundaries of multipart bodies are generated, so they are not compared
+	if ct, ok := normalisedExpected["content-type"]; ok && normalisedActual != nil {
+		normalisedExpected["content-type"] = withoutBoundary(ct)
+		if act, ok := normalisedActual["content-type"]; ok {
+			normalisedActual["content-type"] = withoutBoundary(act)
 		}
 	}
 
 	return diff.DeepDiff(normalisedExpected, normalisedActual, &diff.DiffConfig{AllowUnexpectedKeys: true, RootPath: "[\"header\"]"})
 }
+
+//forbiddenHeaderMatches fails for every forbidden header, case-insensitive, present in the actual headers
+func forbiddenHeaderMatches(forbidden []string, actual map[string][]string) (bool, diff.Differences) {
+	var diffs diff.Differences
+	for _, h := range forbidden {
+		for key, val := range actual {
+			if strings.EqualFold(key, h) {
+				diffs = append(diffs, diff.ForbiddenHeaderMismatch(fmt.Sprintf("[\"header\"][\"%s\"]", strings.ToLower(h)), key, val))
+			}
+		}
+	}
+	return len(diffs) == 0, diffs
+}
+
+//withoutBoundary removes the boundary parameter of multipart content types
+func withoutBoundary(contentTypes []string) []string {
+	normalised := make([]string, len(contentTypes))
+	for n, ct := range contentTypes {
+		normalised[n] = ct
+		if mediaType, params, err := mime.ParseMediaType(ct); err == nil && strings.HasPrefix(mediaType, "multipart/") {
+			delete(params, "boundary")
+			normalised[n] = mime.FormatMediaType(mediaType, params)
+		}
+	}
+	return normalised
+}
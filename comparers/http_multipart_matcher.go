@@ -0,0 +1,128 @@
+package comparers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/diff"
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
+
+const contentIDHeader = "Content-Id"
+
+//bodyPart is a part of a multipart body
+type bodyPart struct {
+	header http.Header
+	body   []byte
+}
+
+//isMultipart returns true when the expected response has a multipart/mixed body
+func isMultipart(expected *provider.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(headerValue(expected.Headers, "Content-Type"))
+	return err == nil && mediaType == "multipart/mixed" && expected.BodyHasToBeSerialized()
+}
+
+//multipartBodyMatches parses the multipart bodies by their own boundaries and matches every expected part against
+//the actual part with the same Content-ID, or the part in the same position when the expected parts have no
+//Content-ID. The part headers are matched like the response headers and json part bodies like json bodies
+func multipartBodyMatches(expected, actual *provider.Response, conf diff.DiffConfig) (bool, diff.Differences) {
+	expectedParts, err := parseParts(expected)
+	if err != nil {
+		return false, diff.Differences{diff.MultipartMismatch("[\"body\"]", fmt.Errorf("expected body: %s", err))}
+	}
+	actualParts, err := parseParts(actual)
+	if err != nil {
+		return false, diff.Differences{diff.MultipartMismatch("[\"body\"]", err)}
+	}
+
+	byID := make(map[string]*bodyPart)
+	for _, p := range actualParts {
+		if id := p.header.Get(contentIDHeader); id != "" {
+			byID[id] = p
+		}
+	}
+
+	var diffs diff.Differences
+	for n, e := range expectedParts {
+		path := fmt.Sprintf("[\"body\"][%d]", n)
+		id := e.header.Get(contentIDHeader)
+		var a *bodyPart
+		if id != "" {
+			if a = byID[id]; a == nil {
+				diffs = append(diffs, diff.PartNotFoundMismatch(path, id))
+				continue
+			}
+		} else if n < len(actualParts) {
+			a = actualParts[n]
+		} else {
+			_, d := diff.DeepDiff(len(expectedParts), len(actualParts), &diff.DiffConfig{RootPath: "[\"body\"][\"parts\"]"})
+			return false, append(diffs, d...)
+		}
+		diffs = append(diffs, partMatches(path, e, a, conf)...)
+	}
+	return len(diffs) == 0, diffs
+}
+
+func partMatches(path string, expected, actual *bodyPart, conf diff.DiffConfig) diff.Differences {
+	var diffs diff.Differences
+	for key, vals := range expected.header {
+		if _, d := diff.DeepDiff(vals, actual.header[key], &diff.DiffConfig{RootPath: fmt.Sprintf("%s[\"header\"][%q]", path, strings.ToLower(key))}); len(d) > 0 {
+			diffs = append(diffs, d...)
+		}
+	}
+
+	var e, a interface{} = string(expected.body), string(actual.body)
+	if isJSONPart(expected) {
+		if err := provider.DecodeJSON(expected.body, &e); err != nil {
+			return append(diffs, diff.MultipartMismatch(path, fmt.Errorf("expected body: %s", err)))
+		}
+		if err := provider.DecodeJSON(actual.body, &a); err != nil {
+			return append(diffs, diff.MultipartMismatch(path, err))
+		}
+	}
+	conf.RootPath = path + "[\"body\"]"
+	conf.Rules = nil
+	_, d := diff.DeepDiff(e, a, &conf)
+	return append(diffs, d...)
+}
+
+func isJSONPart(p *bodyPart) bool {
+	mediaType, _, err := mime.ParseMediaType(p.header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+//parseParts reads the parts of the multipart body by the boundary of its content type
+func parseParts(r *provider.Response) ([]*bodyPart, error) {
+	_, params, err := mime.ParseMediaType(headerValue(r.Headers, "Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	body, _ := r.GetBody().(string)
+	//recorded bodies often use bare new lines rather than the crlf the format requires
+	if !strings.Contains(body, "\r\n") {
+		body = strings.Replace(body, "\n", "\r\n", -1)
+	}
+
+	mr := multipart.NewReader(bytes.NewReader([]byte(body)), params["boundary"])
+	var parts []*bodyPart
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				return parts, nil
+			}
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(p)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, &bodyPart{header: http.Header(p.Header), body: b})
+	}
+}
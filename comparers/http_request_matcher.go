@@ -3,10 +3,13 @@ package comparers
 import (
 	"net/url"
 
+	"github.com/SEEK-Jobs/pact-go/diff"
+	"github.com/SEEK-Jobs/pact-go/matchers"
 	"github.com/SEEK-Jobs/pact-go/provider"
 )
 
-// MatchRequest compares the request and provides the match outcome
+// MatchRequest compares the request and provides the match outcome. The body is matched by the body matching rules
+// of the expected request the same way a response body is, the values without a rule must be equal
 func MatchRequest(expected, actual *provider.Request) (bool, error) {
 	expectedQuery, err := url.ParseQuery(expected.Query)
 	if err != nil {
@@ -23,9 +26,11 @@ func MatchRequest(expected, actual *provider.Request) (bool, error) {
 		return false, nil
 	} else if res, _ := queryMatches(expectedQuery, actualQuery); !res {
 		return false, nil
-	} else if res, _ := headerMatches(expected.Headers, actual.Headers); !res {
+	} else if res, _ := headerMatches(expected.Headers, actual.Headers, nil); !res {
 		return false, nil
-	} else if res, _, err := bodyMatches(expected.GetBody(), actual.GetBody(), false, expected.BodyHasToBeSerialized()); err != nil || !res {
+	} else if res, _, err := bodyMatches(expected.GetBody(), actual.GetBody(), expected.BodyHasToBeSerialized(), diff.DiffConfig{
+		Rules: expected.MatchingRules[matchers.BodyCategory],
+	}); err != nil || !res {
 		return false, err
 	}
 	return true, nil
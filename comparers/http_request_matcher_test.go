@@ -1,7 +1,7 @@
 package comparers
 
 import (
-	_ "encoding/json"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -135,3 +135,59 @@ func Test_AllHeadersFound_WillMatch(t *testing.T) {
 		t.Error("The request should match")
 	}
 }
+
+func Test_BodyKeysInDifferentOrder_WillMatch(t *testing.T) {
+	a := provider.NewJSONRequest("POST", "/test", "", nil)
+	a.SetBody(`{"name": "John", "age": 12, "address": {"street": "Collins", "city": "Melbourne"}}`)
+	b := provider.NewJSONRequest("POST", "/test", "", nil)
+	b.SetBody(`{"address": {"city": "Melbourne", "street": "Collins"}, "age": 12, "name": "John"}`)
+
+	result, err := MatchRequest(a, b)
+
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if !result {
+		t.Error("The request should match")
+	}
+}
+
+func Test_BodyArrayInDifferentOrder_WillNotMatch(t *testing.T) {
+	a := provider.NewJSONRequest("POST", "/test", "", nil)
+	a.SetBody(`{"ids": [1, 2, 3]}`)
+	b := provider.NewJSONRequest("POST", "/test", "", nil)
+	b.SetBody(`{"ids": [3, 2, 1]}`)
+
+	result, err := MatchRequest(a, b)
+
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if result {
+		t.Error("The request should not match")
+	}
+}
+
+func Test_BodyMatchesByMatchingRules_WillMatch(t *testing.T) {
+	var a provider.Request
+	if err := json.Unmarshal([]byte(`{"method": "POST", "path": "/orders", "body": {"id": 1, "item": "book"},
+		"matchingRules": {"body": {"$.id": {"matchers": [{"match": "integer"}]}}}}`), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	b := provider.NewJSONRequest("POST", "/orders", "", nil)
+	b.SetBody(map[string]interface{}{"id": json.Number("42"), "item": "book"})
+	if result, err := MatchRequest(&a, b); err != nil || !result {
+		t.Errorf("expected the request to match by the integer rule, got %v: %v", result, err)
+	}
+
+	c := provider.NewJSONRequest("POST", "/orders", "", nil)
+	c.SetBody(map[string]interface{}{"id": json.Number("42"), "item": "pen"})
+	if result, err := MatchRequest(&a, c); err != nil || result {
+		t.Errorf("expected the value without a rule to be matched exactly, got %v: %v", result, err)
+	}
+}
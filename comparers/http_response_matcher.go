@@ -1,23 +1,128 @@
 package comparers
 
 import (
+	"encoding/json"
+
 	"github.com/SEEK-Jobs/pact-go/diff"
+	"github.com/SEEK-Jobs/pact-go/matchers"
 	"github.com/SEEK-Jobs/pact-go/provider"
 )
 
-func MatchResponse(expected, actual *provider.Response) (diff.Differences, error) {
+//MatchConfig configures how the actual response is matched against the expected response
+type MatchConfig struct {
+	//NoExtraBody fails the match when the provider returns a body but none is expected
+	NoExtraBody bool
+	//AllowExtraArrayElements lets arrays in the actual body have more elements than the expected example,
+	//the expected elements are still matched positionally. By default the array lengths must match
+	AllowExtraArrayElements bool
+	//StrictEmptyObjects requires an expected empty object in the body to be matched by an empty object, by default
+	//it matches any object as the expected objects with keys match objects with more keys
+	StrictEmptyObjects bool
+	//IgnoreHeaders are the response headers, case-insensitive, excluded from the comparison
+	IgnoreHeaders []string
+	//ForbiddenHeaders are the response headers, case-insensitive, the provider must not return
+	ForbiddenHeaders []string
+	//ServerErrorBodyLimit caps the bytes of the body of an unexpected 5xx response reported with its mismatch, zero
+	//reports DefaultServerErrorBodyLimit bytes and a negative limit reports no body
+	ServerErrorBodyLimit int
+	//SkipBody matches only the status and headers of the response, leaving the body unverified
+	SkipBody bool
+	//OnRuleMatch is called for every body value which matches by a matching rule rather than by equality
+	OnRuleMatch func(m *diff.RuleMatch)
+}
+
+var (
+	DefaultMatchConfig = &MatchConfig{}
+	//DefaultIgnoredResponseHeaders are volatile headers added by providers and proxies which are not part of a contract
+	DefaultIgnoredResponseHeaders = []string{"Date", "Server", "X-Request-Id", "X-Correlation-Id", "Via", "Age"}
+	//DefaultServerErrorBodyLimit is how many bytes of the body of an unexpected 5xx response are reported
+	DefaultServerErrorBodyLimit = 512
+)
+
+func MatchResponse(expected, actual *provider.Response, conf *MatchConfig) (diff.Differences, error) {
+	if conf == nil {
+		conf = DefaultMatchConfig
+	}
 	diffs := make(diff.Differences, 0)
 
-	if res, sDiff := diff.DeepDiff(expected.Status, actual.Status,
-		&diff.DiffConfig{AllowUnexpectedKeys: true, RootPath: "[\"status\"]"}); !res {
+	if res, sDiff := statusMatches(expected, actual); !res {
+		if body, ok := UnexpectedServerError(expected, actual, conf.ServerErrorBodyLimit); ok {
+			sDiff = diff.Differences{diff.ServerErrorMismatch(expected.Status, actual.Status, body)}
+		}
 		diffs = append(diffs, sDiff...)
-	} else if res, hDiff := headerMatches(expected.Headers, actual.Headers); !res {
+	} else if res, hDiff := headerMatches(expected.Headers, actual.Headers, conf.IgnoreHeaders); !res {
 		diffs = append(diffs, hDiff...)
-	} else if res, bDiff, err := bodyMatches(expected.GetBody(), actual.GetBody(), true, expected.BodyHasToBeSerialized()); err != nil {
+	} else if conf.SkipBody {
+		//the body is not verified
+	} else if res, bDiff := noBodyMatches(expected, actual, conf); !res {
+		diffs = append(diffs, bDiff...)
+	} else if rs := binaryBodyRules(expected); rs != nil {
+		if res, bDiff := binaryBodyMatches(rs, actual); !res {
+			diffs = append(diffs, bDiff...)
+		}
+	} else if isMultipart(expected) {
+		if res, bDiff := multipartBodyMatches(expected, actual, diff.DiffConfig{
+			AllowUnexpectedKeys:     true,
+			AllowUnexpectedElements: conf.AllowExtraArrayElements,
+			StrictEmptyObjects:      conf.StrictEmptyObjects,
+		}); !res {
+			diffs = append(diffs, bDiff...)
+		}
+	} else if rs := binaryExampleRules(expected); rs != nil {
+		if res, bDiff := binaryBodyMatches(rs, actual); !res {
+			diffs = append(diffs, bDiff...)
+		}
+	} else if res, bDiff, err := bodyMatches(expected.GetBody(), actual.GetBody(), expected.BodyHasToBeSerialized(), diff.DiffConfig{
+		AllowUnexpectedKeys:     true,
+		AllowUnexpectedElements: conf.AllowExtraArrayElements,
+		StrictEmptyObjects:      conf.StrictEmptyObjects,
+		Rules:                   expected.MatchingRules[matchers.BodyCategory],
+		OnRuleMatch:             conf.OnRuleMatch,
+	}); err != nil {
 		return nil, err
 	} else if !res {
 		diffs = append(diffs, bDiff...)
 	}
 
+	if res, fDiff := forbiddenHeaderMatches(conf.ForbiddenHeaders, actual.Headers); !res {
+		diffs = append(diffs, fDiff...)
+	}
 	return diffs, nil
 }
+
+//UnexpectedServerError returns the body, truncated to the limit, of an actual 5xx response when a response below
+//500 is expected, false otherwise. The limit works as the ServerErrorBodyLimit of the MatchConfig
+func UnexpectedServerError(expected, actual *provider.Response, limit int) (string, bool) {
+	if actual.Status < 500 || expected.Status >= 500 {
+		return "", false
+	}
+	if limit == 0 {
+		limit = DefaultServerErrorBodyLimit
+	} else if limit < 0 {
+		return "", true
+	}
+
+	var body string
+	switch b := actual.GetBody().(type) {
+	case nil:
+	case string:
+		body = b
+	case []byte:
+		body = string(b)
+	default:
+		j, _ := json.Marshal(b)
+		body = string(j)
+	}
+	if len(body) > limit {
+		body = body[:limit] + "..."
+	}
+	return body, true
+}
+
+//statusMatches compares the status by its matching rule when the response has one, otherwise exactly
+func statusMatches(expected, actual *provider.Response) (bool, diff.Differences) {
+	if rs := expected.MatchingRules[matchers.StatusCategory].Resolve(nil); rs != nil {
+		return diff.MatchRule(expected.Status, actual.Status, rs, "[\"status\"]")
+	}
+	return diff.DeepDiff(expected.Status, actual.Status, &diff.DiffConfig{AllowUnexpectedKeys: true, RootPath: "[\"status\"]"})
+}
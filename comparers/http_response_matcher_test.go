@@ -2,6 +2,13 @@ package comparers
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -48,7 +55,7 @@ func Test_MatchResponse_Scenarios(t *testing.T) {
 			t.Error(err)
 			t.FailNow()
 		}
-		diff, err := MatchResponse(test.exp, providerResponse)
+		diff, err := MatchResponse(test.exp, providerResponse, nil)
 		if err != nil {
 			t.Error(err)
 		}
@@ -59,3 +66,488 @@ func Test_MatchResponse_Scenarios(t *testing.T) {
 		}
 	}
 }
+
+func Test_MatchResponse_NoContent_WillMatch(t *testing.T) {
+	exp := provider.NewResponse(204, nil)
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(204, nil, ""))
+
+	for _, conf := range []*MatchConfig{DefaultMatchConfig, &MatchConfig{NoExtraBody: true}} {
+		if diffs, err := MatchResponse(exp, act, conf); err != nil {
+			t.Error(err)
+		} else if len(diffs) > 0 {
+			t.Errorf("expected no diffs, got %s", diffs.Error())
+		}
+	}
+}
+
+func Test_MatchResponse_EmptyExpectedBody_WillMatchEmptyBody(t *testing.T) {
+	exp := provider.NewJSONResponse(200, nil)
+	exp.SetBody("")
+	plainExp := provider.NewResponse(200, nil)
+	plainExp.SetBody("")
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, ""))
+
+	for _, e := range []*provider.Response{exp, plainExp} {
+		if diffs, err := MatchResponse(e, act, nil); err != nil {
+			t.Error(err)
+		} else if len(diffs) > 0 {
+			t.Errorf("expected no diffs, got %s", diffs.Error())
+		}
+	}
+}
+
+func Test_MatchResponse_EmptyExpectedBody_WillNotMatchBody(t *testing.T) {
+	exp := provider.NewResponse(200, nil)
+	exp.SetBody("")
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `{"name":"John Doe"}`))
+
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected 1 diff, got %d", len(diffs))
+	}
+}
+
+func Test_MatchResponse_UnexpectedBody_FailsWithNoExtraBody(t *testing.T) {
+	exp := provider.NewResponse(200, nil)
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `{"name":"John Doe"}`))
+
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected no diffs without NoExtraBody, got %s", diffs.Error())
+	}
+
+	if diffs, err := MatchResponse(exp, act, &MatchConfig{NoExtraBody: true}); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected 1 diff, got %d", len(diffs))
+	} else if !strings.Contains(diffs.Error(), "[\"body\"]") {
+		t.Errorf("expected diff at [\"body\"], got %s", diffs.Error())
+	}
+}
+
+func unmarshalTestProviderResponse(t *testing.T, pactResponse string) *provider.Response {
+	var r provider.Response
+	if err := json.Unmarshal([]byte(pactResponse), &r); err != nil {
+		t.Fatal(err)
+	}
+	return &r
+}
+
+func Test_MatchResponse_AppliesWildcardMatchingRules(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"body": {"items": [{"name": "apple", "price": 1.25}, {"name": "pear", "price": 2.5}, {"name": "plum", "price": 0.75}]},
+		"matchingRules": {"body": {"$.items[*].price": {"matchers": [{"match": "decimal"}]}}}
+	}`)
+
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil,
+		`{"items": [{"name": "apple", "price": 3.99}, {"name": "pear", "price": 0.5}, {"name": "plum", "price": 10.01}]}`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected no diffs, got %s", diffs.Error())
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil,
+		`{"items": [{"name": "apple", "price": 3.99}, {"name": "pear", "price": 5}, {"name": "plum", "price": 10.01}]}`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected 1 diff, got %d", len(diffs))
+	} else if !strings.Contains(diffs.Error(), `["body"]["items"][1]["price"]`) {
+		t.Errorf("expected diff at the second price, got %s", diffs.Error())
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil,
+		`{"items": [{"name": "apple", "price": 3.99}, {"name": "banana", "price": 0.5}, {"name": "plum", "price": 10.01}]}`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected fields without rules to match exactly, got %d diffs", len(diffs))
+	}
+}
+
+func Test_MatchResponse_AppliesEachKeyAndEachValueMatchingRules(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"body": {"users": {"5e8d4a0c-7d3b-4e5f-9a1b-2c3d4e5f6a7b": {"name": "John", "age": 30}}},
+		"matchingRules": {"body": {"$.users": {"matchers": [
+			{"match": "eachKey", "rules": [{"match": "regex", "regex": "[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}"}]},
+			{"match": "eachValue", "rules": [{"match": "type"}]}
+		]}}}
+	}`)
+
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `{"users": {
+		"0b1c2d3e-4f5a-6b7c-8d9e-0f1a2b3c4d5e": {"name": "Jane", "age": 41},
+		"a1b2c3d4-e5f6-a7b8-c9d0-e1f2a3b4c5d6": {"name": "Bob", "age": 25}
+	}}`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected no diffs, got %s", diffs.Error())
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `{"users": {
+		"0b1c2d3e-4f5a-6b7c-8d9e-0f1a2b3c4d5e": {"name": "Jane", "age": 41},
+		"bob": {"name": "Bob", "age": 25}
+	}}`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), "key bob: expected a value matching regex") {
+		t.Errorf("expected a diff for the key which is not a uuid, got %v", diffs)
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `{"users": {
+		"0b1c2d3e-4f5a-6b7c-8d9e-0f1a2b3c4d5e": {"name": "Jane", "age": "41"}
+	}}`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), `["body"]["users"]["0b1c2d3e-4f5a-6b7c-8d9e-0f1a2b3c4d5e"]["age"]`) {
+		t.Errorf("expected a diff for the value of the wrong type, got %v", diffs)
+	}
+}
+
+func Test_MatchResponse_ReportsUnexpectedServerError(t *testing.T) {
+	exp := buildTestProviderResponse(200, nil, "")
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(500, nil, `{"error": "database unavailable"}`))
+
+	diffs, err := MatchResponse(exp, act, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `provider returned the server error 500, expected status 200, the response body was: {"error":"database unavailable"}`
+	if len(diffs) != 1 || !strings.Contains(diffs.Error(), expected) {
+		t.Errorf("expected a server error diff, got %v", diffs)
+	}
+
+	if body, ok := UnexpectedServerError(exp, act, 8); !ok || body != `{"error"...` {
+		t.Errorf("expected the body to be truncated, got %q", body)
+	}
+	if _, ok := UnexpectedServerError(provider.NewResponse(503, nil), act, 0); ok {
+		t.Error("expected an expected server error not to be reported")
+	}
+}
+
+func Test_MatchResponse_AppliesRootMatchingRule(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"body": [{"id": 1, "name": "apple"}],
+		"matchingRules": {"body": {"$": {"matchers": [{"match": "type"}]}}}
+	}`)
+
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil,
+		`[{"id": 7, "name": "pear"}, {"id": 8, "name": "plum"}, {"id": 9, "name": "fig"}]`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected the root rule to match the elements by type, got %s", diffs.Error())
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `[{"id": "7", "name": "pear"}]`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), `["body"][0]["id"]`) {
+		t.Errorf("expected a type diff at the id of the first element, got %v", diffs)
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `{"id": 7, "name": "pear"}`))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), `mismatch at ["body"]: matching rule failed`) {
+		t.Errorf("expected a type diff at the root of the body, got %v", diffs)
+	}
+}
+
+func Test_MatchResponse_AppliesStatusMatchingRule(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"matchingRules": {"status": {"matchers": [{"match": "statusCode", "status": [200, 201]}]}}
+	}`)
+
+	for status, diffCount := range map[int]int{200: 0, 201: 0, 204: 1, 404: 1} {
+		act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(status, nil, ""))
+		if diffs, err := MatchResponse(exp, act, nil); err != nil {
+			t.Error(err)
+		} else if len(diffs) != diffCount {
+			t.Errorf("expected %d diffs for status %d, got %d", diffCount, status, len(diffs))
+		} else if diffCount > 0 && !strings.Contains(diffs.Error(), `["status"]`) {
+			t.Errorf("expected diff at the status, got %s", diffs.Error())
+		}
+	}
+
+	strict := unmarshalTestProviderResponse(t, `{"status": 200}`)
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(201, nil, ""))
+	if diffs, _ := MatchResponse(strict, act, nil); len(diffs) != 1 {
+		t.Errorf("expected the status without a rule to match exactly, got %d diffs", len(diffs))
+	}
+}
+
+func Test_MatchResponse_OptionalFields(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"body": {"id": 1, "nickname": "johnny"},
+		"matchingRules": {"body": {"$.nickname": {"matchers": [{"match": "regex", "regex": "[a-z]+"}], "optional": true}}}
+	}`)
+
+	for body, matches := range map[string]bool{
+		`{"id": 1}`:                       true,
+		`{"id": 1, "nickname": "jo"}`:     true,
+		`{"id": 1, "nickname": "Jo 2"}`:   false,
+		`{"id": 1, "nickname": 7}`:        false,
+		`{"nickname": "jo"}`:              false,
+		`{"id": 1, "name": "John Smith"}`: true,
+	} {
+		act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, body))
+		if diffs, err := MatchResponse(exp, act, nil); err != nil {
+			t.Error(err)
+		} else if len(diffs) == 0 != matches {
+			t.Errorf("expected %s to match %v, got %v", body, matches, diffs)
+		}
+	}
+}
+
+func Test_MatchResponse_ExtraArrayElements(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{"status": 200, "body": {"items": [{"id": 1}, {"id": 2}]}}`)
+	extra, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil,
+		`{"items": [{"id": 1}, {"id": 2}, {"id": 3}]}`))
+
+	//strict by default
+	if diffs, err := MatchResponse(exp, extra, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected 1 diff when array lengths differ, got %d", len(diffs))
+	}
+
+	conf := &MatchConfig{AllowExtraArrayElements: true}
+	if diffs, err := MatchResponse(exp, extra, conf); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected extra elements to be allowed, got %s", diffs.Error())
+	}
+
+	//the examples are still matched positionally
+	reordered, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil,
+		`{"items": [{"id": 2}, {"id": 1}, {"id": 3}]}`))
+	if diffs, err := MatchResponse(exp, reordered, conf); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected 1 diff when examples are out of position, got %d", len(diffs))
+	}
+
+	//fewer elements than the example are always a mismatch
+	fewer, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, `{"items": [{"id": 1}]}`))
+	if diffs, err := MatchResponse(exp, fewer, conf); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected 1 diff when elements are missing, got %d", len(diffs))
+	}
+}
+
+func Test_MatchResponse_EmptyContainers(t *testing.T) {
+	strict := &MatchConfig{StrictEmptyObjects: true}
+	lenient := &MatchConfig{AllowExtraArrayElements: true}
+	cases := []struct {
+		expected, actual string
+		conf             *MatchConfig
+		matches          bool
+	}{
+		{`{}`, `{}`, nil, true},
+		{`{}`, `{"id": 1}`, nil, true},
+		{`{}`, `[]`, nil, false},
+		{`{}`, `{}`, strict, true},
+		{`{}`, `{"id": 1}`, strict, false},
+		{`{"user": {}}`, `{"user": {"id": 1}}`, nil, true},
+		{`{"user": {}}`, `{"user": {"id": 1}}`, strict, false},
+		{`{"user": {"id": 1}}`, `{"user": {"id": 1, "name": "John"}}`, strict, true},
+		{`[]`, `[]`, nil, true},
+		{`[]`, `[1]`, nil, false},
+		{`[]`, `{}`, nil, false},
+		{`[]`, `[1]`, lenient, true},
+		{`{"items": []}`, `{"items": [1]}`, nil, false},
+		{`{"items": []}`, `{"items": [1]}`, lenient, true},
+	}
+	for _, c := range cases {
+		exp := unmarshalTestProviderResponse(t, fmt.Sprintf(`{"status": 200, "body": %s}`, c.expected))
+		act, err := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, nil, c.actual))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diffs, err := MatchResponse(exp, act, c.conf); err != nil {
+			t.Error(err)
+		} else if matches := len(diffs) == 0; matches != c.matches {
+			t.Errorf("expected %s matching %s with %+v to be %v, got %v", c.expected, c.actual, c.conf, c.matches, diffs)
+		}
+	}
+}
+
+func testPNG(t *testing.T, c color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for x := 0; x < 2; x++ {
+		for y := 0; y < 2; y++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_MatchResponse_BinaryBody(t *testing.T) {
+	b := testPNG(t, color.Black)
+	sum := sha256.Sum256(b)
+	pngResponse := func(b []byte) *provider.Response {
+		resp, err := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, http.Header{"Content-Type": {"image/png"}}, string(b)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	byHash := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"matchingRules": {"body": {"$": {"matchers": [{"match": "binary", "sha256": "`+hex.EncodeToString(sum[:])+`"}]}}}
+	}`)
+	if diffs, err := MatchResponse(byHash, pngResponse(b), nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected no diffs, got %s", diffs.Error())
+	}
+	if diffs, err := MatchResponse(byHash, pngResponse(testPNG(t, color.White)), nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), "sha256") {
+		t.Errorf("expected a sha256 diff, got %v", diffs)
+	}
+
+	byType := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"matchingRules": {"body": {"$": {"matchers": [{"match": "binary", "contentType": "image/png"}]}}}
+	}`)
+	if diffs, err := MatchResponse(byType, pngResponse(testPNG(t, color.White)), nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected no diffs, got %s", diffs.Error())
+	}
+	if diffs, err := MatchResponse(byType, pngResponse(nil), nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected an empty body diff, got %v", diffs)
+	}
+
+	pdf, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200, http.Header{"Content-Type": {"application/pdf"}}, "%PDF-1.4"))
+	if diffs, err := MatchResponse(byType, pdf, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), `["header"]["content-type"]`) {
+		t.Errorf("expected a content type diff, got %v", diffs)
+	}
+}
+
+func Test_MatchResponse_IgnoresHeaders(t *testing.T) {
+	exp := buildTestProviderResponse(200, http.Header{"Date": {"Wed, 21 Oct 2015 07:28:00 GMT"}, "Content-Type": {"application/json"}}, "")
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200,
+		http.Header{"Date": {"Thu, 22 Oct 2015 09:00:00 GMT"}, "Content-Type": {"application/json"}}, ""))
+
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected a date header diff, got %d diffs", len(diffs))
+	}
+
+	if diffs, err := MatchResponse(exp, act, &MatchConfig{IgnoreHeaders: []string{"date"}}); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected the ignored date header not to be compared, got %s", diffs.Error())
+	}
+
+	act.Headers.Set("Content-Type", "text/plain")
+	if diffs, err := MatchResponse(exp, act, &MatchConfig{IgnoreHeaders: []string{"date"}}); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected the other headers to be compared, got %d diffs", len(diffs))
+	}
+}
+
+func Test_MatchResponse_ForbiddenHeaders(t *testing.T) {
+	exp := buildTestProviderResponse(200, http.Header{"Content-Type": {"application/json"}}, "")
+	act, _ := provider.CreateResponseFromHTTPResponse(buildTestHttpResponse(200,
+		http.Header{"Content-Type": {"application/json"}, "X-Powered-By": {"go1.4"}}, ""))
+
+	if diffs, err := MatchResponse(exp, act, &MatchConfig{ForbiddenHeaders: []string{"x-debug"}}); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected an absent forbidden header to match, got %s", diffs.Error())
+	}
+
+	diffs, err := MatchResponse(exp, act, &MatchConfig{ForbiddenHeaders: []string{"x-powered-by"}})
+	if err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), "forbidden header X-Powered-By is present") {
+		t.Errorf("expected a forbidden header diff, got %v", diffs)
+	}
+}
+
+func multipartTestResponse(boundary string, parts ...string) *http.Response {
+	body := ""
+	for _, p := range parts {
+		body += "--" + boundary + "\r\n" + p + "\r\n"
+	}
+	body += "--" + boundary + "--\r\n"
+	return buildTestHttpResponse(200, http.Header{"Content-Type": {"multipart/mixed; boundary=" + boundary}}, body)
+}
+
+func Test_MatchResponse_MultipartMixedMatchesPartsInOrder(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"headers": {"Content-Type": "multipart/mixed; boundary=batch"},
+		"body": "--batch\nContent-Type: application/json\n\n{\"id\": 1, \"name\": \"John\"}\n--batch\nContent-Type: text/plain\n\ncreated\n--batch--\n"
+	}`)
+
+	act, err := provider.CreateResponseFromHTTPResponse(multipartTestResponse("b0undary",
+		"Content-Type: application/json\r\n\r\n{\"name\":\"John\",\"id\":1,\"extra\":true}",
+		"Content-Type: text/plain\r\n\r\ncreated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected the parts to match regardless of the boundary, got %s", diffs.Error())
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(multipartTestResponse("b0undary",
+		"Content-Type: application/json\r\n\r\n{\"name\":\"John\",\"id\":1}",
+		"Content-Type: text/plain\r\n\r\nrejected"))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 {
+		t.Errorf("expected 1 diff, got %d", len(diffs))
+	} else if !strings.Contains(diffs.Error(), `["body"][1]["body"]`) {
+		t.Errorf("expected diff at the body of the second part, got %s", diffs.Error())
+	}
+}
+
+func Test_MatchResponse_MultipartMixedMatchesPartsByContentID(t *testing.T) {
+	exp := unmarshalTestProviderResponse(t, `{
+		"status": 200,
+		"headers": {"Content-Type": "multipart/mixed; boundary=batch"},
+		"body": "--batch\nContent-ID: <1>\n\nfirst\n--batch\nContent-ID: <2>\n\nsecond\n--batch--\n"
+	}`)
+
+	act, _ := provider.CreateResponseFromHTTPResponse(multipartTestResponse("other",
+		"Content-ID: <2>\r\n\r\nsecond", "Content-ID: <1>\r\n\r\nfirst"))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) > 0 {
+		t.Errorf("expected the parts to match by content id, got %s", diffs.Error())
+	}
+
+	act, _ = provider.CreateResponseFromHTTPResponse(multipartTestResponse("other", "Content-ID: <1>\r\n\r\nfirst"))
+	if diffs, err := MatchResponse(exp, act, nil); err != nil {
+		t.Error(err)
+	} else if len(diffs) != 1 || !strings.Contains(diffs.Error(), "part with content id <2> not found") {
+		t.Errorf("expected the missing part, got %v", diffs)
+	}
+}
@@ -3,6 +3,7 @@ package pact
 import (
 	"github.com/SEEK-Jobs/pact-go/util"
 	"log"
+	"net/url"
 	"os"
 )
 
@@ -27,4 +28,32 @@ type VerfierConfig struct {
 type PactUriConfig struct {
 	Username string
 	Password string
+	//ProviderURL overrides the provider url the pact is verified against, the ServiceProvider url is used when nil
+	ProviderURL *url.URL
+}
+
+//BrokerAuth credentials used to authenticate with the pact broker
+type BrokerAuth struct {
+	Username string
+	Password string
+	//Token is sent as a bearer token when there is no username and password
+	Token string
+}
+
+//ConfigError is a misconfiguration of the verifier, use errors.As to find which setting is missing or invalid
+type ConfigError struct {
+	//Field is the setting which is missing or invalid, e.g. consumer, provider or providerURL
+	Field string
+	//Consumer and Provider are the names configured so far
+	Consumer string
+	Provider string
+	Err      error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
 }
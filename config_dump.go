@@ -0,0 +1,266 @@
+package pact
+
+import (
+	"encoding/json"
+	goio "io"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/util"
+)
+
+//redactedSecret replaces the secrets of the dumped configuration, the same way url.URL.Redacted replaces passwords
+const redactedSecret = "xxxxx"
+
+var trailingSlashNames = map[TrailingSlashPolicy]string{
+	TrailingSlashPreserve: "preserve",
+	TrailingSlashStrip:    "strip",
+	TrailingSlashAdd:      "add",
+}
+
+type configDump struct {
+	Consumer        string   `json:"consumer"`
+	Provider        string   `json:"provider"`
+	ProviderAliases []string `json:"providerAliases,omitempty"`
+	ProviderURL     string   `json:"providerUrl,omitempty"`
+	//ProviderURLFunc is set when the provider url is resolved by ServiceProviderFunc when the verification starts
+	ProviderURLFunc  bool              `json:"providerUrlFunc,omitempty"`
+	Pacts            []*pactSourceDump `json:"pacts,omitempty"`
+	PactDir          string            `json:"pactDir,omitempty"`
+	PactReader       bool              `json:"pactReader,omitempty"`
+	Broker           *brokerDump       `json:"broker,omitempty"`
+	States           []string          `json:"states"`
+	StateChangeURL   string            `json:"stateChangeUrl,omitempty"`
+	ProviderAuth     *providerAuthDump `json:"providerAuth,omitempty"`
+	Retry            *retryDump        `json:"retry,omitempty"`
+	BrokerRetry      *retryDump        `json:"brokerRetry,omitempty"`
+	MaxLatency       string            `json:"maxLatency,omitempty"`
+	RequestDelay     string            `json:"requestDelay,omitempty"`
+	Transport        *transportDump    `json:"transport,omitempty"`
+	TLSServerName    string            `json:"tlsServerName,omitempty"`
+	UserAgent        string            `json:"userAgent"`
+	TrailingSlash    string            `json:"trailingSlash"`
+	HealthCheck      *healthCheckDump  `json:"healthCheck,omitempty"`
+	Matching         *matchingDump     `json:"matching"`
+	Results          *resultsFileDump  `json:"results,omitempty"`
+	AllowEmptyPact   bool              `json:"allowEmptyPact"`
+	MinInteractions  int               `json:"minInteractions,omitempty"`
+	Concurrency      int               `json:"concurrency,omitempty"`
+	WarningsAsErrors bool              `json:"warningsAsErrors"`
+}
+
+type pactSourceDump struct {
+	URI         string `json:"uri"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	ProviderURL string `json:"providerUrl,omitempty"`
+}
+
+type brokerDump struct {
+	URL             string `json:"url"`
+	Username        string `json:"username,omitempty"`
+	Password        string `json:"password,omitempty"`
+	Token           string `json:"token,omitempty"`
+	ConsumerVersion string `json:"consumerVersion,omitempty"`
+	Environment     string `json:"environment,omitempty"`
+	PublishResults  string `json:"publishResults,omitempty"`
+}
+
+type providerAuthDump struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	//Hook is set when the headers are obtained by a ProviderAuthHook
+	Hook bool   `json:"hook"`
+	TTL  string `json:"ttl,omitempty"`
+}
+
+type retryDump struct {
+	Attempts      int    `json:"attempts"`
+	Backoff       string `json:"backoff"`
+	MaxBackoff    string `json:"maxBackoff,omitempty"`
+	Jitter        bool   `json:"jitter"`
+	MaxRetryAfter string `json:"maxRetryAfter,omitempty"`
+}
+
+type transportDump struct {
+	IdleConnTimeout       string `json:"idleConnTimeout,omitempty"`
+	TLSHandshakeTimeout   string `json:"tlsHandshakeTimeout,omitempty"`
+	ExpectContinueTimeout string `json:"expectContinueTimeout,omitempty"`
+	DisableKeepAlives     bool   `json:"disableKeepAlives"`
+	HTTP2                 bool   `json:"http2"`
+	MaxConnsPerHost       int    `json:"maxConnsPerHost,omitempty"`
+}
+
+type healthCheckDump struct {
+	Path     string `json:"path"`
+	Timeout  string `json:"timeout"`
+	Interval string `json:"interval"`
+}
+
+type matchingDump struct {
+	ExpectedFailures         []string `json:"expectedFailures,omitempty"`
+	IgnoreResponseHeaders    []string `json:"ignoreResponseHeaders"`
+	ForbiddenResponseHeaders []string `json:"forbiddenResponseHeaders,omitempty"`
+	NoExtraBody              bool     `json:"noExtraBody"`
+	StrictArrayLength        bool     `json:"strictArrayLength"`
+	StrictEmptyObjects       bool     `json:"strictEmptyObjects"`
+	SmokeMode                bool     `json:"smokeMode"`
+}
+
+type resultsFileDump struct {
+	Path            string   `json:"path"`
+	ProviderVersion string   `json:"providerVersion"`
+	BuildURL        string   `json:"buildUrl,omitempty"`
+	Branch          string   `json:"branch,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+}
+
+//DumpConfig writes the configuration of the verifier as json, with its defaults resolved, to attach to bug
+//reports. The passwords of the pacts, the pact broker and the provider are redacted, the headers of the provider
+//auth hook are not obtained
+func (v *pactFileVerfier) DumpConfig(w goio.Writer) error {
+	o := v.options
+	d := &configDump{
+		Consumer:         v.consumer,
+		Provider:         v.provider,
+		ProviderAliases:  v.aliases,
+		ProviderURL:      redactURL(v.validator.ProviderURL()),
+		PactDir:          v.pactDir,
+		PactReader:       v.pactReader != nil,
+		States:           make([]string, 0, len(v.stateActions)),
+		StateChangeURL:   redactURL(o.stateChangeURL),
+		Retry:            dumpRetry(o.retry),
+		BrokerRetry:      dumpRetry(v.brokerRetry),
+		MaxLatency:       dumpDuration(o.maxLatency),
+		TLSServerName:    o.tlsServerName,
+		UserAgent:        util.UserAgent(o.userAgent),
+		TrailingSlash:    trailingSlashNames[o.trailingSlash],
+		AllowEmptyPact:   v.allowEmpty,
+		MinInteractions:  v.minVerified,
+		Concurrency:      o.concurrency,
+		WarningsAsErrors: v.strict,
+	}
+	d.ProviderURLFunc = d.ProviderURL == "" && v.validator.CanValidate() == nil
+
+	if v.pactUri != "" {
+		d.Pacts = append(d.Pacts, dumpPactSource(&pactSource{uri: v.pactUri, config: v.pactUriConfig}))
+	}
+	for _, s := range v.pacts {
+		d.Pacts = append(d.Pacts, dumpPactSource(s))
+	}
+	if v.brokerURL != "" {
+		d.Broker = &brokerDump{URL: redactURLString(v.brokerURL), ConsumerVersion: v.consumerVer, Environment: v.environment,
+			PublishResults: v.publishVer}
+		if v.brokerAuth != nil {
+			d.Broker.Username, d.Broker.Password = v.brokerAuth.Username, redact(v.brokerAuth.Password)
+			d.Broker.Token = redact(v.brokerAuth.Token)
+		}
+	}
+
+	for state := range v.stateActions {
+		d.States = append(d.States, state)
+	}
+	sort.Strings(d.States)
+	if o.basicAuth != nil || o.auth != nil {
+		d.ProviderAuth = &providerAuthDump{Hook: o.auth != nil, TTL: dumpDuration(o.authTTL)}
+		if o.basicAuth != nil {
+			password, _ := o.basicAuth.Password()
+			d.ProviderAuth.Username, d.ProviderAuth.Password = o.basicAuth.Username(), redact(password)
+		}
+	}
+
+	if o.limiter != nil {
+		d.RequestDelay = dumpDuration(o.limiter.interval)
+	}
+	if o.transport != nil {
+		d.Transport = &transportDump{
+			IdleConnTimeout:       dumpDuration(o.transport.IdleConnTimeout),
+			TLSHandshakeTimeout:   dumpDuration(o.transport.TLSHandshakeTimeout),
+			ExpectContinueTimeout: dumpDuration(o.transport.ExpectContinueTimeout),
+			DisableKeepAlives:     o.transport.DisableKeepAlives,
+			HTTP2:                 o.transport.HTTP2,
+			MaxConnsPerHost:       o.transport.MaxConnsPerHost,
+		}
+	}
+	if hc := o.healthCheck; hc != nil {
+		d.HealthCheck = &healthCheckDump{Path: hc.path, Timeout: hc.timeout.String(), Interval: hc.interval.String()}
+	}
+
+	mc := o.matchConfig()
+	d.Matching = &matchingDump{
+		IgnoreResponseHeaders:    mc.IgnoreHeaders,
+		ForbiddenResponseHeaders: mc.ForbiddenHeaders,
+		NoExtraBody:              mc.NoExtraBody,
+		StrictArrayLength:        !mc.AllowExtraArrayElements,
+		StrictEmptyObjects:       mc.StrictEmptyObjects,
+		SmokeMode:                mc.SkipBody,
+	}
+	for description := range o.expectedFailures {
+		d.Matching.ExpectedFailures = append(d.Matching.ExpectedFailures, description)
+	}
+	sort.Strings(d.Matching.ExpectedFailures)
+
+	if f := v.resultsFile; f != nil {
+		d.Results = &resultsFileDump{Path: f.path, ProviderVersion: f.providerVersion, BuildURL: redactURLString(f.buildURL),
+			Branch: v.branch, Tags: v.versionTags}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(d)
+}
+
+func dumpPactSource(s *pactSource) *pactSourceDump {
+	d := &pactSourceDump{URI: redactURLString(s.uri)}
+	if s.config != nil {
+		d.Username, d.Password = s.config.Username, redact(s.config.Password)
+		d.ProviderURL = redactURL(s.config.ProviderURL)
+	}
+	return d
+}
+
+func dumpRetry(p *util.RetryPolicy) *retryDump {
+	if p == nil {
+		return nil
+	}
+	return &retryDump{
+		Attempts:      p.Attempts,
+		Backoff:       p.Backoff.String(),
+		MaxBackoff:    dumpDuration(p.MaxBackoff),
+		Jitter:        p.Jitter,
+		MaxRetryAfter: dumpDuration(p.MaxRetryAfter),
+	}
+}
+
+//dumpDuration formats the duration, a zero duration is left out
+func dumpDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+//redact replaces the secret, an empty secret is left out
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedSecret
+}
+
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.Redacted()
+}
+
+//redactURLString redacts the password of the url, a file path or an invalid url is returned as it is
+func redactURLString(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
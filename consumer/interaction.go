@@ -3,6 +3,7 @@ package consumer
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -11,17 +12,74 @@ import (
 	"github.com/SEEK-Jobs/pact-go/provider"
 )
 
+//MetadataRejectsUnexpectedFields is the metadata flag of an interaction whose provider must reject
+//the request when its json body has an unexpected field
+const MetadataRejectsUnexpectedFields = "rejectsUnexpectedFields"
+
+//MetadataPending and MetadataSkip are the metadata flags of an interaction which the provider does not implement yet,
+//the interaction is skipped by the verification
+const (
+	MetadataPending = "pending"
+	MetadataSkip    = "skip"
+)
+
+//MetadataScenario is the metadata field naming the scenario, e.g. a checkout flow, which groups related interactions
+const MetadataScenario = "scenario"
+
 type Interaction struct {
-	State       string             `json:"provider_state,omitempty"`
-	Description string             `json:"description"`
-	Request     *provider.Request  `json:"request"`
-	Response    *provider.Response `json:"response"`
+	State string `json:"provider_state,omitempty"`
+	//StateParams are the parameters of the provider state of a v3 or v4 pact, e.g. {"id": 23}
+	StateParams map[string]interface{} `json:"provider_state_params,omitempty"`
+	Description string                 `json:"description"`
+	Request     *provider.Request      `json:"request"`
+	Response    *provider.Response     `json:"response"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Comments    *Comments              `json:"comments,omitempty"`
+	//Location is where the interaction is in the pact it was read from, nil when it was not read from a pact
+	Location *Location `json:"-"`
+}
+
+//Comments are the notes recorded with an interaction, the testname is the consumer test which generated it
+type Comments struct {
+	Text     []string `json:"text,omitempty"`
+	TestName string   `json:"testname,omitempty"`
+}
+
+//States returns the provider states of the interaction in the order they are set up, empty when it has none
+func (i *Interaction) States() []string {
+	if i.State == "" {
+		return nil
+	}
+	return []string{i.State}
+}
+
+//TestName returns the consumer test which generated the interaction, empty when it is not recorded
+func (i *Interaction) TestName() string {
+	if i.Comments == nil {
+		return ""
+	}
+	return i.Comments.TestName
+}
+
+//Location is the position of an interaction in the source document of a pact
+type Location struct {
+	//Index is the index of the interaction in the interactions array
+	Index int
+	//Offset is the byte offset of the interaction in the document
+	Offset int64
+	//Line is the line of the document the interaction starts on
+	Line int
+}
+
+func (l *Location) String() string {
+	return fmt.Sprintf("interaction #%d at interactions[%d], line %d", l.Index+1, l.Index, l.Line)
 }
 
 var (
 	errEmptyDescription = errors.New("Cannot register interaction with empty description.")
 	errNilRequest       = errors.New("Cannot register interaction with nil request")
 	errNilResponse      = errors.New("Cannot register interaction with nil response")
+	errNoJSONObjectBody = errors.New("Cannot add an unexpected field to a request without a json object body.")
 )
 
 func NewInteraction(description string, state string, request *provider.Request,
@@ -64,12 +122,56 @@ func (i *Interaction) ToHTTPRequest(baseURL string) (*http.Request, error) {
 	}
 
 	for header, val := range i.Request.Headers {
-		req.Header.Add(header, val[0])
+		req.Header.Set(header, strings.Join(val, ", "))
+	}
+	if ct := i.Request.DefaultContentType(); len(body) > 0 && ct != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", ct)
 	}
 
 	return req, nil
 }
 
+//RejectsUnexpectedFields returns true when the interaction is flagged to check the provider rejects unexpected request fields
+func (i *Interaction) RejectsUnexpectedFields() bool {
+	flag, _ := i.Metadata[MetadataRejectsUnexpectedFields].(bool)
+	return flag
+}
+
+//Skipped returns true when the interaction is flagged as pending or skip, it is not verified
+func (i *Interaction) Skipped() bool {
+	pending, _ := i.Metadata[MetadataPending].(bool)
+	skip, _ := i.Metadata[MetadataSkip].(bool)
+	return pending || skip
+}
+
+//Scenario returns the scenario the interaction belongs to, empty when it is not tagged with one
+func (i *Interaction) Scenario() string {
+	s, _ := i.Metadata[MetadataScenario].(string)
+	return s
+}
+
+//WithUnexpectedField returns a copy of the interaction whose json request body has the additional field
+func (i *Interaction) WithUnexpectedField(field string, value interface{}) (*Interaction, error) {
+	body, ok := i.Request.GetBody().(map[string]interface{})
+	if !ok {
+		return nil, errNoJSONObjectBody
+	}
+
+	extended := make(map[string]interface{}, len(body)+1)
+	for k, v := range body {
+		extended[k] = v
+	}
+	extended[field] = value
+
+	req := provider.NewJSONRequest(i.Request.Method, i.Request.Path, i.Request.Query, i.Request.Headers)
+	if err := req.SetBody(extended); err != nil {
+		return nil, err
+	}
+	c := *i
+	c.Request = req
+	return &c, nil
+}
+
 func (i *Interaction) IsSimilar(to *Interaction) bool {
 	return strings.EqualFold(i.Description, to.Description) &&
 		strings.EqualFold(i.State, to.State)
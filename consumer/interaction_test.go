@@ -3,6 +3,7 @@ package consumer
 import (
 	"bytes"
 	"encoding/json"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
@@ -87,3 +88,162 @@ func getJsonObj(jsonText string) (interface{}, error) {
 	}
 	return obj, nil
 }
+
+func Test_Interaction_WithUnexpectedField(t *testing.T) {
+	request := provider.NewJSONRequest("POST", "/users", "a=1", nil)
+	request.SetBody(`{"name":"John Doe"}`)
+	interaction, _ := NewInteraction("description", "state", request, provider.NewJSONResponse(201, nil))
+
+	extended, err := interaction.WithUnexpectedField("extra", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := extended.Request.GetBody().(map[string]interface{})
+	if body["name"] != "John Doe" || body["extra"] != "value" {
+		t.Errorf("expected the recorded body with the extra field, got %v", body)
+	}
+	if _, ok := interaction.Request.GetBody().(map[string]interface{})["extra"]; ok {
+		t.Error("expected the original interaction to be left unchanged")
+	}
+	if extended.Request.Path != "/users" || extended.Request.Query != "a=1" {
+		t.Error("expected the request path and query to be preserved")
+	}
+}
+
+func Test_Interaction_WithUnexpectedField_RequiresJSONObjectBody(t *testing.T) {
+	interaction, _ := NewInteraction("description", "state", provider.NewJSONRequest("GET", "/users", "", nil), provider.NewJSONResponse(200, nil))
+
+	if _, err := interaction.WithUnexpectedField("extra", "value"); err != errNoJSONObjectBody {
+		t.Errorf("expected %s, got %v", errNoJSONObjectBody, err)
+	}
+}
+
+func Test_Interaction_RejectsUnexpectedFields_OffByDefault(t *testing.T) {
+	interaction := getFakeInteraction()
+	if interaction.RejectsUnexpectedFields() {
+		t.Error("expected strict request validation to be off by default")
+	}
+
+	interaction.Metadata = map[string]interface{}{MetadataRejectsUnexpectedFields: true}
+	if !interaction.RejectsUnexpectedFields() {
+		t.Error("expected strict request validation when flagged")
+	}
+}
+
+func Test_Interaction_SendsEveryHeaderValue(t *testing.T) {
+	header := http.Header{"Accept": {"application/xml", "text/xml;q=0.9"}}
+	interaction, _ := NewInteraction("description", "", provider.NewRequest("GET", "/user", "", header), provider.NewResponse(200, nil))
+
+	req, err := interaction.ToHTTPRequest("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept := req.Header.Get("Accept"); accept != "application/xml, text/xml;q=0.9" {
+		t.Errorf("expected every accept value to be sent, got %q", accept)
+	}
+}
+
+func Test_Interaction_ReadsComments(t *testing.T) {
+	var i Interaction
+	b := []byte(`{"description": "get user", "comments": {"testname": "TestGetUser", "text": ["uses the v2 api"]}}`)
+	if err := json.Unmarshal(b, &i); err != nil {
+		t.Fatal(err)
+	}
+	if i.TestName() != "TestGetUser" || len(i.Comments.Text) != 1 || i.Comments.Text[0] != "uses the v2 api" {
+		t.Errorf("unexpected comments %#v", i.Comments)
+	}
+}
+
+func Test_Interaction_TestName_EmptyWithoutComments(t *testing.T) {
+	i := &Interaction{Description: "get user"}
+	if i.TestName() != "" {
+		t.Errorf("expected no test name, got %q", i.TestName())
+	}
+}
+
+func Test_Interaction_Skipped(t *testing.T) {
+	i := &Interaction{Description: "get user"}
+	if i.Skipped() {
+		t.Error("expected an interaction without metadata not to be skipped")
+	}
+	for _, flag := range []string{MetadataPending, MetadataSkip} {
+		i.Metadata = map[string]interface{}{flag: true}
+		if !i.Skipped() {
+			t.Errorf("expected an interaction flagged %s to be skipped", flag)
+		}
+	}
+}
+
+func Test_Interaction_MapsStructuredQueryToHttpRequest(t *testing.T) {
+	var i Interaction
+	b := []byte(`{"description": "search", "request": {"method": "GET", "path": "/users", "query": {"name": ["John", "Jane"], "sort": "surname"}}}`)
+	if err := json.Unmarshal(b, &i); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := i.ToHTTPRequest("http://localhost:52343/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Path != "/users" || req.URL.RawQuery != "name=John&name=Jane&sort=surname" {
+		t.Errorf("expected the structured query in the url, got %s", req.URL)
+	}
+}
+
+func Test_Interaction_MapsInlineQueryToHttpRequest(t *testing.T) {
+	var i Interaction
+	b := []byte(`{"description": "search", "request": {"method": "GET", "path": "/users?name=John", "query": "sort=surname"}}`)
+	if err := json.Unmarshal(b, &i); err != nil {
+		t.Fatal(err)
+	}
+
+	if i.Request.Path != "/users" || i.Request.Query != "name=John&sort=surname" {
+		t.Errorf("expected the inline query to be moved to the query, got %s and %s", i.Request.Path, i.Request.Query)
+	}
+	req, err := i.ToHTTPRequest("http://localhost:52343/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Path != "/users" || req.URL.RawQuery != "name=John&sort=surname" {
+		t.Errorf("expected the inline query in the url, got %s", req.URL)
+	}
+}
+
+func Test_Interaction_ThrowsError_InvalidStructuredQuery(t *testing.T) {
+	var i Interaction
+	b := []byte(`{"description": "search", "request": {"method": "GET", "path": "/users", "query": {"page": 2}}}`)
+	if err := json.Unmarshal(b, &i); err == nil {
+		t.Error("expected a query parameter which is not a string to be rejected")
+	}
+}
+
+func Test_Interaction_SendsTheRecordedBody(t *testing.T) {
+	cases := []struct {
+		request, contentType, body string
+	}{
+		{`{"method": "POST", "path": "/users", "body": {"name": "John", "tags": ["a"]}}`,
+			"application/json", `{"name":"John","tags":["a"]}`},
+		{`{"method": "PUT", "path": "/users/1", "headers": {"Content-Type": "application/json; charset=utf-8"}, "body": {"name": "John"}}`,
+			"application/json; charset=utf-8", `{"name":"John"}`},
+		{`{"method": "POST", "path": "/login", "headers": {"Content-Type": "application/x-www-form-urlencoded"}, "body": "user=john&note=two+words"}`,
+			"application/x-www-form-urlencoded", "user=john&note=two+words"},
+		{`{"method": "POST", "path": "/orders", "headers": {"Content-Type": "application/xml"}, "body": "<order id=\"1\"/>"}`,
+			"application/xml", `<order id="1"/>`},
+	}
+	for _, c := range cases {
+		var i Interaction
+		if err := json.Unmarshal([]byte(`{"description": "write", "request": `+c.request+`, "response": {"status": 200}}`), &i); err != nil {
+			t.Fatal(err)
+		}
+		req, err := i.ToHTTPRequest("http://localhost")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body bytes.Buffer
+		body.ReadFrom(req.Body)
+		if req.Method != i.Request.Method || body.String() != c.body || req.Header.Get("Content-Type") != c.contentType {
+			t.Errorf("expected %s %s with %s, got %s %s with %s", i.Request.Method, c.body, c.contentType, req.Method,
+				body.String(), req.Header.Get("Content-Type"))
+		}
+	}
+}
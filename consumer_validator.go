@@ -1,133 +1,870 @@
 package pact
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/SEEK-Jobs/pact-go/comparers"
 	"github.com/SEEK-Jobs/pact-go/consumer"
 	"github.com/SEEK-Jobs/pact-go/diff"
 	"github.com/SEEK-Jobs/pact-go/io"
+	"github.com/SEEK-Jobs/pact-go/matchers"
 	"github.com/SEEK-Jobs/pact-go/util"
 
 	"net/http"
 	"net/url"
 
 	"github.com/SEEK-Jobs/pact-go/provider"
+	"github.com/SEEK-Jobs/pact-go/schema"
 )
 
 type consumerValidator interface {
 	ProviderService(c *http.Client, u *url.URL)
+	ProviderServiceFunc(c *http.Client, resolve func() (*url.URL, error))
+	OverrideProviderURL(u *url.URL)
+	ProviderURL() *url.URL
+	SetOptions(o *validationOptions)
 	CanValidate() error
 	Validate(f *io.PactFile, states map[string]*stateAction) (bool, error)
+	ValidateContext(ctx context.Context, f *io.PactFile, states map[string]*stateAction) (bool, error)
+	PreviewRequest(i *consumer.Interaction) (*http.Request, error)
+	Explore(ctx context.Context, endpoints []EndpointSpec) []*ExplorationResult
+	Result() *VerificationResult
 }
 
 var (
 	errNilProviderClient        = errors.New("Provider http client cannot be nil, please provide a valid value using ServiceProvider function.")
 	errNilProviderURL           = errors.New("Provider url cannot be nil, please provide a valid value using ServiceProvider function.")
+	unexpectedField             = "pactUnexpectedField"
+	stageVerification           = "verification"
+	stageTeardown               = "teardown"
+	errResolveProviderURLMsg    = "Failed to resolve the provider url: %s"
+	errRouteInteractionMsg      = "Failed to route the interaction '%s' to a provider url: %s"
+	errRequestSignerMsg         = "Failed to sign the request to the provider: %s"
 	errNotFoundProviderStateMsg = "providerState '%s' was defined by a consumer, however could not be found. Please supply this provider state."
+	errUnexpectedPassMsg        = "The interactions %s were expected to fail but passed, please remove them from the expected failures."
+	mismatchHeadingMsg          = "The response for state '%s' did not match%s, the differences are below:"
+	expectedFailureHeadingMsg   = "The response for state '%s' did not match%s, however '%s' is an expected failure:"
+	locationMsg                 = " (%s)"
+	gzipEncoding                = "gzip"
 )
 
+//validationOptions holds the optional behaviours configured on the verifier
+type validationOptions struct {
+	expectedFailures map[string]bool
+	tracer           Tracer
+	propagateTrace   bool
+	noExtraBody      bool
+	lenientArrays    bool
+	strictObjects    bool
+	ignoreGenerators bool
+	retry            *util.RetryPolicy
+	maxLatency       time.Duration
+	ignoreHeaders    []string
+	//forbiddenHeaders are the response headers the provider must not return
+	forbiddenHeaders []string
+	//serverErrorBodyLimit caps the body reported for an unexpected 5xx response
+	serverErrorBodyLimit int
+	//smoke verifies only the status and headers of the responses
+	smoke bool
+	//sniffContentType reads the response bodies without a specific content type as json when json is expected
+	sniffContentType bool
+	//faultInjector forces the errors of the stages in tests
+	faultInjector FaultInjector
+	//stateChangeURL receives the setup and teardown of the states without actions
+	stateChangeURL       *url.URL
+	stateChangeSetupOnly bool
+	bodyEncoder          BodyEncoder
+	//responseSchemas are the json schemas the response bodies of the interactions, by description, must validate against
+	responseSchemas map[string]*schema.Schema
+	//ruleOverrides are the matching rules merged into the response rules of the interactions, by description
+	ruleOverrides map[string]matchers.MatchingRules
+	auth          ProviderAuth
+	authTTL       time.Duration
+	signer        RequestSigner
+	//basicAuth are the credentials of the requests without a recorded or hook authorization
+	basicAuth *url.Userinfo
+	//defaultState is set up before and torn down after the provider state of every interaction
+	defaultState *defaultState
+	//stateNameMapper maps the recorded provider states to the keys of their handlers and state changes
+	stateNameMapper func(description string) string
+	//afterInteraction runs after the state teardowns of every interaction, before the default teardown
+	afterInteraction func(states []string) error
+	//propagatePanics lets a panic of the verification crash, rather than fail the interaction
+	propagatePanics bool
+	//detailedTimings records the duration of every stage of the interaction verifications
+	detailedTimings bool
+	//explainMatches records the matching rules the body values matched by
+	explainMatches bool
+	//transport tunes the connections of the provider client
+	transport *TransportConfig
+	//tlsServerName is the server name presented in the TLS handshakes with the provider
+	tlsServerName string
+	//healthCheck is polled until the provider is ready before the interactions are verified
+	healthCheck *healthCheck
+	//middlewares wrap the requests to the provider, in the order they were registered
+	middlewares []RequestMiddleware
+	//dialer dials the connections to the provider instead of the transport of the provider client
+	dialer *dialer
+	//limiter spaces the requests to the provider by the request delay
+	limiter *requestLimiter
+	//requestFilter changes the requests built from the interactions before they are signed and sent
+	requestFilter func(req *http.Request)
+	//concurrency is the number of interactions verified at the same time
+	concurrency int
+	//sequential verifies the interactions one at a time whatever the concurrency, e.g. the steps of a scenario
+	sequential bool
+	//router selects the base url of the interactions by their path, the provider url when it returns nil
+	router func(path string) (*url.URL, error)
+	//trailingSlash is how the trailing slash of the interaction paths is handled
+	trailingSlash TrailingSlashPolicy
+	//correlation attaches a correlation id to the requests of every interaction
+	correlation *correlation
+	//userAgent is sent with the requests which do not specify one, util.DefaultUserAgent when empty
+	userAgent string
+	//authCache holds the auth headers of the current verification
+	authCache *authCache
+}
+
+//stateName returns the key of the provider state handler and the state change of the recorded state
+func (o *validationOptions) stateName(state string) string {
+	if o.stateNameMapper == nil {
+		return state
+	}
+	return o.stateNameMapper(state)
+}
+
+func (o *validationOptions) matchConfig() *comparers.MatchConfig {
+	ignore := o.ignoreHeaders
+	if ignore == nil {
+		ignore = comparers.DefaultIgnoredResponseHeaders
+	}
+	return &comparers.MatchConfig{NoExtraBody: o.noExtraBody, AllowExtraArrayElements: o.lenientArrays, IgnoreHeaders: ignore,
+		StrictEmptyObjects: o.strictObjects, ForbiddenHeaders: o.forbiddenHeaders, ServerErrorBodyLimit: o.serverErrorBodyLimit,
+		SkipBody: o.smoke}
+}
+
+//expectedResponse returns the response of the interaction with its matching rules overridden
+func (o *validationOptions) expectedResponse(i *consumer.Interaction) *provider.Response {
+	rules, ok := o.ruleOverrides[i.Description]
+	if !ok {
+		return i.Response
+	}
+	r := *i.Response
+	r.MatchingRules = i.Response.MatchingRules.Merge(rules)
+	return &r
+}
+
+func (o *validationOptions) getTracer() Tracer {
+	if o.tracer == nil {
+		return noopTracer{}
+	}
+	return o.tracer
+}
+
 type pactValidator struct {
-	c        *http.Client
-	u        *url.URL
-	setup    Action
-	teardown Action
-	l        util.Logger
+	//c and u are the client and url the requests are sent with
+	c *http.Client
+	u *url.URL
+	//rc and ru are the client and url the provider was configured with
+	rc       *http.Client
+	ru       *url.URL
+	resolve  func() (*url.URL, error)
+	override *url.URL
+	//providerURL is the url the current pact is verified against
+	providerURL string
+	//tuned is the configured client tuned by the transport config, kept so its connections are reused
+	tuned     *http.Client
+	tunedFrom *http.Client
+	tunedWith *TransportConfig
+	tunedName string
+	tunedDial *dialer
+	setup     Action
+	teardown  Action
+	l         util.Logger
+	opts      *validationOptions
+	result    *VerificationResult
 }
 
 func newConsumerValidator(setup, teardown Action, l util.Logger) consumerValidator {
-	return &pactValidator{setup: setup, teardown: teardown, l: l}
+	return &pactValidator{setup: setup, teardown: teardown, l: l, opts: &validationOptions{}}
 }
 
 func (v *pactValidator) CanValidate() error {
 	if v.c == nil {
 		return errNilProviderClient
-	} else if v.u == nil {
+	} else if v.u == nil && v.resolve == nil {
 		return errNilProviderURL
 	}
 	return nil
 }
 
 func (v *pactValidator) ProviderService(c *http.Client, u *url.URL) {
-	v.c = c
-	v.u = u
+	v.rc, v.ru = c, u
+	v.c, v.u = targetProvider(c, u)
+	v.resolve = nil
+}
+
+func (v *pactValidator) ProviderServiceFunc(c *http.Client, resolve func() (*url.URL, error)) {
+	v.rc, v.ru = c, nil
+	v.c, v.u = c, nil
+	v.resolve = resolve
+}
+
+//ProviderURL returns the url the provider was configured with, nil when it is resolved by a function
+func (v *pactValidator) ProviderURL() *url.URL {
+	return v.ru
+}
+
+//OverrideProviderURL sets the url the next pacts are verified against instead of the provider url, nil clears it
+func (v *pactValidator) OverrideProviderURL(u *url.URL) {
+	v.override = u
+}
+
+//resolveURL resolves the url the pact is verified against, the override takes precedence over
+//the resolver and the provider url
+func (v *pactValidator) resolveURL() error {
+	u := v.ru
+	if v.override != nil {
+		u = v.override
+	} else if v.resolve != nil {
+		var err error
+		if u, err = v.resolve(); err != nil {
+			return fmt.Errorf(errResolveProviderURLMsg, err)
+		} else if u == nil {
+			return errNilProviderURL
+		}
+	}
+	if u == nil {
+		return errNilProviderURL
+	}
+	v.c, v.u = targetProvider(v.tunedClient(), u)
+	v.c = withMiddlewares(v.c, v.opts.middlewares)
+	v.providerURL = u.String()
+	return nil
+}
+
+//tunedClient returns the configured client with its transport tuned, its TLS server name and its dialer set, then
+//HTTP/2 set up on the resulting transport
+func (v *pactValidator) tunedClient() *http.Client {
+	if v.opts.transport == nil && v.opts.tlsServerName == "" && v.opts.dialer == nil {
+		return v.rc
+	}
+	if v.tuned == nil || v.tunedFrom != v.rc || v.tunedWith != v.opts.transport || v.tunedName != v.opts.tlsServerName ||
+		v.tunedDial != v.opts.dialer {
+		tuned := withDialer(withServerName(v.opts.transport.apply(v.rc), v.opts.tlsServerName), v.opts.dialer)
+		v.tuned = v.opts.transport.withHTTP2(tuned)
+		v.tunedFrom, v.tunedWith, v.tunedName, v.tunedDial = v.rc, v.opts.transport, v.opts.tlsServerName, v.opts.dialer
+	}
+	return v.tuned
+}
+
+func (v *pactValidator) SetOptions(o *validationOptions) {
+	v.opts = o
+}
+
+//Result returns the outcome of the interactions verified by the last validation
+func (v *pactValidator) Result() *VerificationResult {
+	return v.result
 }
 
 func (v *pactValidator) Validate(p *io.PactFile, s map[string]*stateAction) (bool, error) {
+	return v.ValidateContext(context.Background(), p, s)
+}
+
+func (v *pactValidator) ValidateContext(ctx context.Context, p *io.PactFile, s map[string]*stateAction) (bool, error) {
 	isValid := true
+	var unexpectedPasses []string
+	v.result = &VerificationResult{}
 
-	for _, i := range p.Interactions {
-		//default setup
-		if err := v.executeAction(v.setup); err != nil {
+	if err := v.resolveURL(); err != nil {
+		return false, err
+	}
+	if hc := v.opts.healthCheck; hc != nil {
+		if err := v.waitForProvider(ctx, hc); err != nil {
 			return false, err
 		}
+	}
 
-		//state setup
-		var sa *stateAction
+	for n, o := range v.verifyInteractions(ctx, p.Interactions, s) {
+		if o == nil {
+			break
+		}
+		i, r := p.Interactions[n], o.r
+		if r != nil {
+			v.result.Interactions = append(v.result.Interactions, r)
+		}
+		if o.err != nil {
+			return false, o.err
+		} else if r.Skipped {
+			continue
+		}
 
-		if i.State != "" {
-			if sa = s[i.State]; sa == nil {
-				return false, fmt.Errorf(errNotFoundProviderStateMsg, i.State)
-			} else if err := v.executeAction(sa.setup); err != nil {
-				return false, err
+		if diffs := r.Differences[:o.mismatched]; len(diffs) > 0 {
+			if r.ExpectedFailure {
+				diff.FormatDiff(diffs, v.l, fmt.Sprintf(expectedFailureHeadingMsg, i.State, location(i), i.Description))
+			} else {
+				diff.FormatDiff(diffs, v.l, fmt.Sprintf(mismatchHeadingMsg, i.State, location(i)))
+				isValid = false
 			}
+		} else if r.ExpectedFailure {
+			unexpectedPasses = append(unexpectedPasses, fmt.Sprintf("'%s'", i.Description))
 		}
 
-		//interaction validation
-		if ok, err := v.validateInteraction(i); err != nil {
-			return false, err
-		} else if !ok {
+		if o.teardownErr != nil {
+			return false, o.teardownErr
+		}
+		if len(r.Differences) > o.mismatched {
+			diff.FormatDiff(r.Differences[o.mismatched:], v.l, fmt.Sprintf(mismatchHeadingMsg, i.State, location(i)))
 			isValid = false
 		}
+	}
+
+	if len(unexpectedPasses) > 0 {
+		v.l.Printf(errUnexpectedPassMsg, strings.Join(unexpectedPasses, ", "))
+		isValid = false
+	}
+	return isValid, nil
+}
+
+//interactionOutcome is the outcome of the verification of an interaction, its differences after the mismatched
+//ones were found by the teardown
+type interactionOutcome struct {
+	r          *InteractionResult
+	mismatched int
+	//err stopped the verification before the interaction was torn down, teardownErr while it was torn down
+	err         error
+	teardownErr error
+}
 
+func (o *interactionOutcome) failed() bool {
+	return o.err != nil || o.teardownErr != nil
+}
+
+//verifyInteractions verifies the interactions by as many workers as the concurrency, the outcomes are in the order
+//of the interactions. The interactions after an interaction which failed with an error are not verified, their
+//outcomes are nil, while the ones before it always are so the first error is the same whatever the completion order.
+//A single worker, as the sequence of a scenario always has, verifies them on the calling goroutine, and a panic a
+//worker propagates is raised again on it
+func (v *pactValidator) verifyInteractions(ctx context.Context, interactions []*consumer.Interaction, s map[string]*stateAction) []*interactionOutcome {
+	outcomes := make([]*interactionOutcome, len(interactions))
+	workers := v.opts.concurrency
+	if workers <= 1 || v.opts.sequential {
+		for n, i := range interactions {
+			outcomes[n] = v.verifyInteraction(ctx, i, s)
+			if outcomes[n].failed() {
+				break
+			}
+		}
+		return outcomes
+	}
+	//the auth headers are cached before the workers share the cache
+	if v.opts.auth != nil && v.opts.authCache == nil {
+		v.opts.authCache = &authCache{}
+	}
+
+	var mu sync.Mutex
+	failedAt := len(interactions)
+	var panicked interface{}
+	stopped := func(n int) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return n > failedAt || panicked != nil
+	}
+	fail := func(n int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if n < failedAt {
+			failedAt = n
+		}
+	}
+	verify := func(n int) {
+		defer func() {
+			if p := recover(); p != nil {
+				mu.Lock()
+				if panicked == nil {
+					panicked = p
+				}
+				mu.Unlock()
+			}
+		}()
+		o := v.verifyInteraction(ctx, interactions[n], s)
+		outcomes[n] = o
+		if o.failed() {
+			fail(n)
+		}
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range next {
+				if !stopped(n) {
+					verify(n)
+				}
+			}
+		}()
+	}
+	for n := 0; n < len(interactions) && !stopped(n); n++ {
+		next <- n
+	}
+	close(next)
+	wg.Wait()
+	if panicked != nil {
+		panic(panicked)
+	}
+	return outcomes
+}
+
+//verifyInteraction sets up the states of the interaction, validates the response of the provider and tears the
+//states down, in order
+func (v *pactValidator) verifyInteraction(ctx context.Context, i *consumer.Interaction, s map[string]*stateAction) *interactionOutcome {
+	if err := ctx.Err(); err != nil {
+		return &interactionOutcome{err: err}
+	}
+	if i.Skipped() {
+		return &interactionOutcome{r: &InteractionResult{Description: i.Description, State: i.State, Location: i.Location,
+			TestName: i.TestName(), ProviderURL: v.providerURL, Skipped: true}}
+	}
+
+	var r *InteractionResult
+	var sa *stateAction
+	var values map[string]interface{}
+	err := v.recoverPanic(i, &r, stageVerification, func() (err error) {
+		r, sa, values, err = v.setupAndValidate(ctx, i, s)
+		return err
+	})
+	if r != nil {
+		r.ProviderURL = v.interactionURL(i)
+	}
+	if err != nil {
+		return &interactionOutcome{r: r, err: err}
+	}
+
+	r.ExpectedFailure = v.opts.expectedFailures[i.Description]
+	r.Reason = mismatchReason(r.Differences)
+	o := &interactionOutcome{r: r, mismatched: len(r.Differences)}
+	teardownStart := time.Now()
+	o.teardownErr = v.recoverPanic(i, &r, stageTeardown, func() error {
 		//state teardown
-		if sa != nil {
-			if err := v.executeAction(sa.teardown); err != nil {
-				return false, err
+		if sa != nil && sa.teardown != nil {
+			if err := sa.teardown(withStateParams(ctx, i.StateParams), values); err != nil {
+				return err
+			}
+		}
+
+		//default state teardown
+		if ds := v.opts.defaultState; ds != nil && ds.action.teardown != nil {
+			if err := ds.action.teardown(ctx, nil); err != nil {
+				return err
+			}
+		}
+
+		//interaction teardown
+		if v.opts.afterInteraction != nil {
+			if err := v.opts.afterInteraction(i.States()); err != nil {
+				return err
 			}
 		}
 
 		//default teardown
-		if err := v.executeAction(v.teardown); err != nil {
-			return false, err
+		return v.executeAction(ctx, withContext(v.teardown))
+	})
+	if o.teardownErr != nil {
+		return o
+	}
+	if r.Timings != nil {
+		r.Timings.Teardown = time.Since(teardownStart)
+	}
+	if len(r.Differences) > o.mismatched {
+		r.Reason = mismatchReason(r.Differences)
+	}
+	return o
+}
+
+//recoverPanic runs the stage of the interaction verification, a panic fails the interaction with the panic
+//and its stack trace unless panics are propagated. The result is created when the stage panics before it
+func (v *pactValidator) recoverPanic(i *consumer.Interaction, r **InteractionResult, stage string, f func() error) (err error) {
+	defer func() {
+		p := recover()
+		if p == nil {
+			return
+		} else if v.opts.propagatePanics {
+			panic(p)
 		}
+		if *r == nil {
+			*r = failedResult(i, NoFailure, nil)
+		}
+		(*r).Differences = append((*r).Differences, diff.PanicMismatch(stage, p, debug.Stack()))
+		err = nil
+	}()
+	return f()
+}
 
+//location describes where the interaction is in its pact, empty when it is unknown
+func location(i *consumer.Interaction) string {
+	if i.Location == nil {
+		return ""
 	}
-	return isValid, nil
+	return fmt.Sprintf(locationMsg, i.Location)
 }
 
-func (v *pactValidator) validateInteraction(i *consumer.Interaction) (bool, error) {
-	req, err := i.ToHTTPRequest(v.u.String())
+//setupAndValidate executes the default and state setups before validating the interaction, the values
+//returned by the state setup are returned for its teardown
+func (v *pactValidator) setupAndValidate(ctx context.Context, i *consumer.Interaction, s map[string]*stateAction) (r *InteractionResult, sa *stateAction, values map[string]interface{}, err error) {
+	ctx, span := v.opts.getTracer().Start(ctx, spanInteraction)
+	span.SetAttribute(attrDescription, i.Description)
+	span.SetAttribute(attrProviderState, i.State)
+	defer func() {
+		span.SetAttribute(attrOutcome, outcomeOf(r != nil && r.Matched(), err))
+		span.End()
+	}()
+
+	//default setup
+	setupStart := time.Now()
+	if err := v.opts.injectFault(FaultStageSetup); err != nil {
+		return failedResult(i, StateSetupError, err), nil, nil, err
+	}
+	if err := v.executeAction(ctx, withContext(v.setup)); err != nil {
+		return failedResult(i, StateSetupError, err), nil, nil, err
+	}
+
+	//default state setup
+	if ds := v.opts.defaultState; ds != nil {
+		if _, err := v.setupState(ctx, ds.name, ds.action); err != nil {
+			return failedResult(i, StateSetupError, err), nil, nil, err
+		}
+	}
+
+	//state setup
+	if i.State != "" {
+		state := v.opts.stateName(i.State)
+		if sa = s[state]; sa == nil && v.opts.stateChangeURL != nil {
+			sa = v.stateChangeAction(state)
+		} else if sa == nil {
+			err = fmt.Errorf(errNotFoundProviderStateMsg, state)
+			return failedResult(i, StateSetupError, err), nil, nil, err
+		}
+		if values, err = v.setupState(withStateParams(ctx, i.StateParams), i.State, sa); err != nil {
+			return failedResult(i, StateSetupError, err), nil, nil, err
+		}
+	}
+
+	//interaction validation
+	r = failedResult(i, NoFailure, nil)
+	if c := v.opts.correlation; c != nil {
+		r.CorrelationID = c.generate()
+		ctx = withCorrelationID(ctx, r.CorrelationID)
+	}
+	if i.Comments != nil {
+		r.Comments = i.Comments.Text
+	}
+	if v.opts.detailedTimings {
+		r.Timings = &InteractionTimings{Setup: time.Since(setupStart)}
+	}
+	if len(i.Request.Generators) > 0 && !v.opts.ignoreGenerators {
+		req, err := i.Request.Generate(values)
+		if err != nil {
+			r.Reason, r.Error = RequestError, err
+			return r, nil, nil, err
+		}
+		generated := *i
+		generated.Request = req
+		i = &generated
+	}
+	if r.Differences, r.Latency, err = v.validateInteraction(ctx, span, i, r); err != nil {
+		r.Reason, r.Error = requestReason(err), err
+		return r, nil, nil, err
+	}
+
+	//strict request validation
+	if i.RejectsUnexpectedFields() {
+		d, err := v.validateRejectsUnexpectedField(ctx, i)
+		if err != nil {
+			r.Reason, r.Error = requestReason(err), err
+			return r, nil, nil, err
+		}
+		r.Differences = append(r.Differences, d...)
+	}
+	return r, sa, values, nil
+}
+
+//failedResult is the result of the interaction, failed by the reason and error when they are set
+func failedResult(i *consumer.Interaction, reason FailureReason, err error) *InteractionResult {
+	return &InteractionResult{Description: i.Description, State: i.State, Location: i.Location, TestName: i.TestName(),
+		Reason: reason, Error: err}
+}
+
+//validateInteraction matches the provider response to the interaction request, the rule matches and timings are
+//recorded in the result when enabled
+func (v *pactValidator) validateInteraction(ctx context.Context, span Span, i *consumer.Interaction, r *InteractionResult) (diff.Differences, time.Duration, error) {
+	start := time.Now()
+	providerResponse, err := v.sendRequest(ctx, i)
+	latency := time.Since(start)
 	if err != nil {
-		return false, err
+		return nil, latency, err
+	}
+	span.SetAttribute(attrStatus, providerResponse.Status)
+
+	matchStart := time.Now()
+	if r.Timings != nil {
+		r.Timings.Request = latency
+		defer func() { r.Timings.Matching = time.Since(matchStart) }()
+	}
+	conf := v.opts.matchConfig()
+	if v.opts.explainMatches {
+		conf.OnRuleMatch = func(m *diff.RuleMatch) {
+			r.RuleMatches = append(r.RuleMatches, m)
+		}
 	}
-	resp, err := v.c.Do(req)
+	expected := v.opts.expectedResponse(i)
+	diffs, err := comparers.MatchResponse(expected, providerResponse, conf)
+	if body, ok := comparers.UnexpectedServerError(expected, providerResponse, conf.ServerErrorBodyLimit); ok {
+		r.ServerError = body
+	}
+	if err == nil {
+		diffs = append(diffs, comparers.MatchAccept(i.Request, providerResponse)...)
+	}
+	if err == nil && v.opts.maxLatency > 0 && latency > v.opts.maxLatency {
+		diffs = append(diffs, diff.LatencyMismatch(v.opts.maxLatency, latency))
+	}
+	if s := v.opts.responseSchemas[i.Description]; s != nil && err == nil && !v.opts.smoke {
+		for _, violation := range s.Validate(providerResponse.GetBody(), "[\"body\"]") {
+			diffs = append(diffs, diff.SchemaMismatch(violation.Path, violation.Message, violation.Value))
+		}
+	}
+	return diffs, latency, err
+}
+
+//validateRejectsUnexpectedField sends the interaction request with an additional field, which the provider must reject
+func (v *pactValidator) validateRejectsUnexpectedField(ctx context.Context, i *consumer.Interaction) (diff.Differences, error) {
+	extended, err := i.WithUnexpectedField(unexpectedField, "unexpected")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.sendRequest(ctx, extended)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status < 400 || resp.Status >= 500 {
+		return diff.Differences{diff.UnexpectedFieldAcceptedMismatch(unexpectedField, resp.Status)}, nil
+	}
+	return nil, nil
+}
+
+//sendRequest sends the interaction request to the provider and reads the response
+func (v *pactValidator) sendRequest(ctx context.Context, i *consumer.Interaction) (*provider.Response, error) {
+	if err := v.opts.injectFault(FaultStageRequest); err != nil {
+		return nil, err
+	}
+	auth, err := v.authHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.opts.retry.Do(v.c, func() (*http.Request, error) {
+		if err := v.opts.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		return v.newRequest(ctx, i, auth)
+	})
 	if resp != nil && resp.Body != nil {
 		defer resp.Body.Close()
 	}
 
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	if isEventStream(i.Response.Headers) && isEventStream(resp.Header) {
+		return firstEventResponse(resp, i.Response)
+	} else if v.opts.sniffContentType && sniffsJSON(i.Response, resp.Header) {
+		return sniffedResponse(resp)
 	}
+	return provider.CreateResponseFromHTTPResponse(resp)
+}
 
-	providerResponse, err := provider.CreateResponseFromHTTPResponse(resp)
+//newRequest builds the request of the interaction as it is sent to the provider
+func (v *pactValidator) newRequest(ctx context.Context, i *consumer.Interaction, auth http.Header) (*http.Request, error) {
+	base, err := v.route(i)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	req, err := i.ToHTTPRequest(base.String())
+	if err != nil {
+		return nil, err
+	}
+	v.opts.trailingSlash.apply(req.URL)
+	for k, vals := range auth {
+		req.Header[http.CanonicalHeaderKey(k)] = vals
+	}
+	if u := v.opts.basicAuth; u != nil && req.Header.Get("Authorization") == "" {
+		password, _ := u.Password()
+		req.SetBasicAuth(u.Username(), password)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", util.UserAgent(v.opts.userAgent))
+	}
+	if v.opts.bodyEncoder != nil {
+		if err := encodeBody(req, i, v.opts.bodyEncoder); err != nil {
+			return nil, err
+		}
 	}
+	if err := compressBody(req); err != nil {
+		return nil, err
+	}
+	if v.opts.propagateTrace {
+		v.opts.getTracer().Inject(ctx, req.Header)
+	}
+	v.opts.correlation.apply(ctx, req.Header)
+	if v.opts.requestFilter != nil {
+		v.opts.requestFilter(req)
+	}
+	if v.opts.signer != nil {
+		if err := signRequest(req, v.opts.signer); err != nil {
+			return nil, err
+		}
+	}
+	return req.WithContext(ctx), nil
+}
 
-	if diffs, err := comparers.MatchResponse(i.Response, providerResponse); err != nil {
-		return false, err
-	} else if len(diffs) > 0 {
-		diff.FormatDiff(diffs, v.l, fmt.Sprintf("The response for state '%s' did not match, the differences are below:", i.State))
-		return false, nil
+//route returns the base url the interaction is sent to, selected by the router when one is configured
+func (v *pactValidator) route(i *consumer.Interaction) (*url.URL, error) {
+	if v.opts.router == nil || i.Request == nil {
+		return v.u, nil
+	}
+	u, err := v.opts.router(i.Request.Path)
+	if err != nil {
+		return nil, fmt.Errorf(errRouteInteractionMsg, i.Description, err)
+	} else if u == nil {
+		return v.u, nil
+	}
+	return u, nil
+}
+
+//interactionURL returns the provider url the interaction is reported as verified against
+func (v *pactValidator) interactionURL(i *consumer.Interaction) string {
+	if u, err := v.route(i); err == nil && u != v.u {
+		return u.String()
+	}
+	return v.providerURL
+}
+
+//PreviewRequest builds the request of the interaction the validation would send to the provider, without sending it
+func (v *pactValidator) PreviewRequest(i *consumer.Interaction) (*http.Request, error) {
+	if err := v.resolveURL(); err != nil {
+		return nil, err
+	}
+	auth, err := v.authHeader()
+	if err != nil {
+		return nil, err
+	}
+	return v.newRequest(context.Background(), i, auth)
+}
+
+//encodeBody replaces the body of the request with the one built by the encoder
+func encodeBody(req *http.Request, i *consumer.Interaction, e BodyEncoder) error {
+	r, contentType, err := e(*i)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	if r != nil {
+		if b, err = ioutil.ReadAll(r); err != nil {
+			return err
+		}
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	req.ContentLength = int64(len(b))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return nil
+}
+
+//compressBody gzips the body of a request which declares the gzip content encoding, the body is
+//recorded decompressed in the pact
+func compressBody(req *http.Request) error {
+	if req.Body == nil || !strings.EqualFold(strings.TrimSpace(req.Header.Get("Content-Encoding")), gzipEncoding) {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(buf.Bytes()))
+	req.ContentLength = int64(buf.Len())
+	return nil
+}
+
+//signRequest passes the final body of the request to the signer, the body is restored to be sent
+func signRequest(req *http.Request, s RequestSigner) error {
+	var b []byte
+	if req.Body != nil {
+		var err error
+		if b, err = ioutil.ReadAll(req.Body); err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+	if err := s(req, b); err != nil {
+		return fmt.Errorf(errRequestSignerMsg, err)
+	}
+	return nil
+}
+
+//setupState runs the setup of the provider state in its own span, returning the values of the setup
+func (v *pactValidator) setupState(ctx context.Context, state string, sa *stateAction) (map[string]interface{}, error) {
+	_, span := v.opts.getTracer().Start(ctx, spanStateSetup)
+	defer span.End()
+	span.SetAttribute(attrProviderState, state)
+	values, err := executeSetup(ctx, sa.setup)
+	span.SetAttribute(attrOutcome, outcomeOf(true, err))
+	return values, err
+}
+
+//executeSetup executes the state setup, the values are empty when the setup returns none
+func executeSetup(ctx context.Context, s StateSetup) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if s != nil {
+		var err error
+		if values, err = s(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if values == nil {
+		values = make(map[string]interface{})
 	}
-	return true, nil
+	return values, nil
 }
 
-func (v *pactValidator) executeAction(a Action) error {
+func (v *pactValidator) executeAction(ctx context.Context, a ContextAction) error {
 	if a != nil {
-		if err := a(); err != nil {
+		if err := a(ctx); err != nil {
 			return err
 		}
 	}
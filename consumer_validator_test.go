@@ -1,12 +1,16 @@
 package pact
 
 import (
+	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/SEEK-Jobs/pact-go/consumer"
 	"github.com/SEEK-Jobs/pact-go/io"
@@ -25,6 +29,22 @@ func Test_Validator_IsInAStateToValidate(t *testing.T) {
 	if err := v.CanValidate(); err == nil || err != errNilProviderURL {
 		t.Errorf("expected %s", errNilProviderURL)
 	}
+
+	v.ProviderServiceFunc(&http.Client{}, func() (*url.URL, error) { return nil, nil })
+
+	if err := v.CanValidate(); err != nil {
+		t.Errorf("expected no error with a url resolver, got %s", err)
+	}
+}
+
+func Test_Validator_ReturnsErrorWhenResolvedURLIsNil(t *testing.T) {
+	v := newConsumerValidator(nil, nil, DefaultLogger)
+	f := io.NewPactFile("consumer", "provider", nil)
+
+	v.ProviderServiceFunc(&http.Client{}, func() (*url.URL, error) { return nil, nil })
+	if _, err := v.Validate(f, nil); err != errNilProviderURL {
+		t.Errorf("expected %s, got %v", errNilProviderURL, err)
+	}
 }
 
 func Test_Validator_ReturnsErrorWhenProvierStateIsMissing(t *testing.T) {
@@ -103,21 +123,21 @@ func Test_Validator_ExecutesSetupsAndTeardowns(t *testing.T) {
 		return nil
 	}, DefaultLogger)
 
-	sa := &stateAction{setup: func() error {
+	sa := newStateAction(func() error {
 		if i != 2 {
 			t.Errorf("Expected this action to be called at %d position but is at %d", 2, i)
 		} else {
 			i++
 		}
 		return nil
-	}, teardown: func() error {
+	}, func() error {
 		if i != 3 {
 			t.Errorf("Expected this action to be called at %d position but is at %d", 3, i)
 		} else {
 			i++
 		}
 		return nil
-	}}
+	})
 
 	interaction, _ := consumer.NewInteraction("description", "state", provider.NewJSONRequest("Get", "/", "", nil), provider.NewJSONResponse(200, nil))
 	f := io.NewPactFile("consumer", "provider", []*consumer.Interaction{interaction})
@@ -175,7 +195,7 @@ func Test_Validator_ThrowsErrorFromSetupsAndTeardowns(t *testing.T) {
 	}
 
 	//test setup action for specific interaction
-	sa = &stateAction{setup: fn, teardown: nil}
+	sa = newStateAction(fn, nil)
 	v = newConsumerValidator(nil, fn, DefaultLogger)
 	v.ProviderService(&http.Client{}, u)
 	if _, err := v.Validate(f, map[string]*stateAction{"state": sa}); err == nil {
@@ -185,7 +205,7 @@ func Test_Validator_ThrowsErrorFromSetupsAndTeardowns(t *testing.T) {
 	}
 
 	//test teardown action for every interaction
-	sa = &stateAction{setup: nil, teardown: fn}
+	sa = newStateAction(nil, fn)
 	v = newConsumerValidator(nil, fn, DefaultLogger)
 	v.ProviderService(&http.Client{}, u)
 	if _, err := v.Validate(f, map[string]*stateAction{"state": sa}); err == nil {
@@ -194,3 +214,123 @@ func Test_Validator_ThrowsErrorFromSetupsAndTeardowns(t *testing.T) {
 		t.Errorf("expected %s, got %s", testErr, err)
 	}
 }
+
+func Test_Validator_VerifiesUpgradeHandshake(t *testing.T) {
+	v := newConsumerValidator(nil, nil, DefaultLogger)
+	reqHeader := http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"},
+		"Sec-Websocket-Key": {"dGhlIHNhbXBsZSBub25jZQ=="}, "Sec-Websocket-Version": {"13"}}
+	respHeader := http.Header{"Upgrade": {"websocket"}, "Sec-Websocket-Accept": {"s3pPLMBiTxaQ9kYGzzhZRbK+xOo="}}
+	interaction, _ := consumer.NewInteraction("websocket handshake", "", provider.NewRequest("GET", "/ws", "", reqHeader), provider.NewResponse(http.StatusSwitchingProtocols, respHeader))
+	f := io.NewPactFile("consumer", "provider", []*consumer.Interaction{interaction})
+
+	//the upgraded connection stays open until the client closes it, as a websocket provider would
+	released := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: s3pPLMBiTxaQ9kYGzzhZRbK+xOo=\r\n\r\n")
+		rw.Flush()
+		<-released
+	}))
+	defer s.Close()
+	defer close(released)
+	u, _ := url.Parse(s.URL)
+
+	v.ProviderService(&http.Client{}, u)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if res, err := v.Validate(f, nil); err != nil {
+			t.Error(err)
+		} else if !res {
+			t.Error("Validation Failed")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the validation not to wait for the upgraded connection to close")
+	}
+}
+
+func Test_Validator_VerifiesProviderRejectsUnexpectedFields(t *testing.T) {
+	request := provider.NewJSONRequest("POST", "/users", "", nil)
+	request.SetBody(`{"name":"John Doe"}`)
+	interaction, _ := consumer.NewInteraction("description", "", request, provider.NewJSONResponse(201, nil))
+	interaction.Metadata = map[string]interface{}{consumer.MetadataRejectsUnexpectedFields: true}
+	f := io.NewPactFile("consumer", "provider", []*consumer.Interaction{interaction})
+
+	strict := true
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body[unexpectedField]; ok && strict {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := newConsumerValidator(nil, nil, DefaultLogger)
+	v.ProviderService(&http.Client{}, u)
+	if res, err := v.Validate(f, nil); err != nil {
+		t.Error(err)
+	} else if !res {
+		t.Error("expected a provider rejecting unexpected fields to pass")
+	}
+
+	strict = false
+	if res, err := v.Validate(f, nil); err != nil {
+		t.Error(err)
+	} else if res {
+		t.Error("expected a provider accepting unexpected fields to fail")
+	} else if d := v.Result().Interactions[0].Differences; len(d) != 1 || !strings.Contains(d.Error(), "expected a 4xx status") {
+		t.Errorf("expected an accepted unexpected field mismatch, got %v", d)
+	}
+
+	//not flagged interactions are not checked
+	interaction.Metadata = nil
+	if res, err := v.Validate(f, nil); err != nil {
+		t.Error(err)
+	} else if !res {
+		t.Error("expected an interaction which is not flagged to pass")
+	}
+}
+
+func Test_Validator_SendsGzipRequestBody(t *testing.T) {
+	request := provider.NewJSONRequest("POST", "/users", "", http.Header{"Content-Encoding": {"gzip"}})
+	request.SetBody(`{"name":"John Doe"}`)
+	interaction, _ := consumer.NewInteraction("create user", "", request, provider.NewJSONResponse(201, nil))
+	f := io.NewPactFile("consumer", "provider", []*consumer.Interaction{interaction})
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(gr).Decode(&body); err != nil || body["name"] != "John Doe" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := newConsumerValidator(nil, nil, DefaultLogger)
+	v.ProviderService(&http.Client{}, u)
+	if res, err := v.Validate(f, nil); err != nil {
+		t.Error(err)
+	} else if !res {
+		t.Errorf("expected the gzipped body to be accepted, got %v", v.Result().Interactions[0].Differences)
+	}
+}
@@ -0,0 +1,45 @@
+package pact
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
+
+//sniffsJSON returns true when the expected body is json and the provider response has no content type, or the
+//generic application/octet-stream, so its body is read as json whatever its content type
+func sniffsJSON(expected *provider.Response, actual http.Header) bool {
+	switch expected.GetBody().(type) {
+	case nil, string, []byte:
+		return false
+	}
+	ct := actual.Get("Content-Type")
+	if ct == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	return err == nil && mediaType == "application/octet-stream"
+}
+
+//sniffedResponse reads the body of the provider response as json, a body which is not valid json is read by its
+//content type as usual
+func sniffedResponse(resp *http.Response) (*provider.Response, error) {
+	if resp.Body == nil {
+		return provider.CreateResponseFromHTTPResponse(resp)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var body interface{}
+	if len(data) == 0 || provider.DecodeJSON(data, &body) != nil {
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+		return provider.CreateResponseFromHTTPResponse(resp)
+	}
+	r := provider.NewResponse(resp.StatusCode, resp.Header)
+	return r, r.SetBody(body)
+}
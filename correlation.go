@@ -0,0 +1,43 @@
+package pact
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+//DefaultCorrelationIDHeader is the header the correlation ids are sent with when CorrelationID is given no header
+const DefaultCorrelationIDHeader = "X-Correlation-Id"
+
+//correlation attaches a generated id to the requests of every interaction
+type correlation struct {
+	header   string
+	generate func() string
+}
+
+type correlationIDKey struct{}
+
+//withCorrelationID returns the context of an interaction whose requests are sent with the correlation id
+func withCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+//apply sets the correlation id of the interaction of the context on the request headers
+func (c *correlation) apply(ctx context.Context, h http.Header) {
+	if c == nil {
+		return
+	}
+	if id, ok := ctx.Value(correlationIDKey{}).(string); ok {
+		h.Set(c.header, id)
+	}
+}
+
+//newCorrelationID generates a random version 4 uuid
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
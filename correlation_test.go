@@ -0,0 +1,91 @@
+package pact
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_Verifier_CorrelationID_SendsAndRecordsTheIds(t *testing.T) {
+	received := make(map[string]string)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received[r.URL.RawQuery] = r.Header.Get("X-Request-Id")
+		userHandlerWithMismatchedData(w, r)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	n := 0
+	var summary bytes.Buffer
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		CorrelationID("X-Request-Id", func() string {
+			n++
+			return fmt.Sprintf("verification-%d", n)
+		}).
+		SummaryWriter(&summary)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected the verification to fail, got %v", err)
+	}
+
+	for _, r := range v.Result().Interactions {
+		found := false
+		for _, id := range received {
+			found = found || id == r.CorrelationID
+		}
+		if r.CorrelationID == "" || !found {
+			t.Errorf("expected the correlation id %q of %s to be sent, got %v", r.CorrelationID, r.Description, received)
+		}
+		if r.Failed() && !strings.Contains(summary.String(), fmt.Sprintf("[correlation id: %s]", r.CorrelationID)) {
+			t.Errorf("expected the summary to report the correlation id of %s, got %s", r.Description, summary.String())
+		}
+	}
+	var report bytes.Buffer
+	if err := v.Result().WriteJSONReport(&report); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(report.String(), `"correlationId": "verification-1"`) {
+		t.Errorf("expected the report to record the correlation id, got %s", report.String())
+	}
+}
+
+func Test_Verifier_CorrelationID_DefaultsToRandomUUIDs(t *testing.T) {
+	var ids []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(DefaultCorrelationIDHeader))
+		userHandlerWithValidData(w, r)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		CorrelationID("", nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if len(ids) != 2 || ids[0] == ids[1] {
+		t.Fatalf("expected a distinct id per interaction, got %v", ids)
+	}
+	for n, id := range ids {
+		if !uuid.MatchString(id) || v.Result().Interactions[n].CorrelationID != id {
+			t.Errorf("expected the recorded uuid %s, got %s", id, v.Result().Interactions[n].CorrelationID)
+		}
+	}
+}
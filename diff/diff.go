@@ -1,10 +1,15 @@
 package diff
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
+	"sort"
 	"strings"
 	"unsafe"
+
+	"github.com/SEEK-Jobs/pact-go/matchers"
 )
 
 var (
@@ -24,7 +29,52 @@ type visit struct {
 
 type DiffConfig struct {
 	AllowUnexpectedKeys bool
-	RootPath            string
+	//AllowUnexpectedElements allows slices to have more elements than expected, the expected elements are compared positionally
+	AllowUnexpectedElements bool
+	RootPath                string
+	//Rules are the matching rules applied to the values instead of equality, paths are relative to the root
+	Rules matchers.Rules
+	//OnRuleMatch is called for every value which matches by a matching rule rather than by equality
+	OnRuleMatch func(m *RuleMatch)
+	//StrictEmptyObjects requires an expected empty object to be matched by an empty object, even when
+	//AllowUnexpectedKeys lets the expected objects with keys match objects with more keys
+	StrictEmptyObjects bool
+}
+
+//RuleMatch is a value which matched by a matching rule
+type RuleMatch struct {
+	//Path is the json path of the value e.g. $.items[0].id
+	Path string
+	//Rule describes the matchers of the rule e.g. type
+	Rule string
+}
+
+func (m *RuleMatch) String() string {
+	return fmt.Sprintf("%s matched by %s", m.Path, m.Rule)
+}
+
+//jsonPath formats the path segments as a json path expression
+func jsonPath(segs []interface{}) string {
+	p := "$"
+	for _, s := range segs {
+		switch seg := s.(type) {
+		case int:
+			p += fmt.Sprintf("[%d]", seg)
+		default:
+			p += fmt.Sprintf(".%v", seg)
+		}
+	}
+	return p
+}
+
+//numberType is the type of the json numbers decoded with UseNumber
+var numberType = reflect.TypeOf(json.Number(""))
+
+//numbersEqual compares json numbers by their exact value, so 1.0 equals 1 and large integers keep their precision
+func numbersEqual(n1, n2 string) bool {
+	r1, ok1 := new(big.Rat).SetString(n1)
+	r2, ok2 := new(big.Rat).SetString(n2)
+	return ok1 && ok2 && r1.Cmp(r2) == 0
 }
 
 type Differences []*Mismatch
@@ -48,7 +98,7 @@ func (d Differences) Error() string {
 // Tests for deep equality using reflected types. The map argument tracks
 // comparisons that have already been seen, which allows short circuiting on
 // recursive types.
-func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, depth int, d *Differences, conf *DiffConfig) (ok bool) {
+func deepValueEqual(path string, segs []interface{}, v1, v2 reflect.Value, visited map[visit]bool, depth int, d *Differences, conf *DiffConfig) (ok bool) {
 	mismatchf := func(typ mismatchType, a ...interface{}) {
 		d.Append(newMismatch(v1, v2, path, typ, a...))
 	}
@@ -94,6 +144,12 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 	}
 
 	v1Kind := v1.Kind()
+	if v1Kind != reflect.Interface && v2.Kind() != reflect.Interface && len(conf.Rules) > 0 {
+		if rs := conf.Rules.Resolve(segs); rs != nil {
+			return ruleValueEqual(path, segs, v1, v2, rs, visited, depth, d, conf)
+		}
+	}
+
 	//Do type check only when object is not a structure, so we can get deep diff
 	if v1Kind != reflect.Struct {
 		if v1.Type() != v2.Type() {
@@ -111,7 +167,7 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 		}
 		for i := 0; i < v1.Len(); i++ {
 			if ok := deepValueEqual(
-				fmt.Sprintf("%s[%d]", path, i),
+				fmt.Sprintf("%s[%d]", path, i), appendSeg(segs, i),
 				v1.Index(i), v2.Index(i), visited, depth+1, d, conf); !ok {
 				return false
 			}
@@ -119,7 +175,7 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 		return true
 	case reflect.Slice:
 		// We treat a nil slice the same as an empty slice.
-		if v1.Len() != v2.Len() {
+		if v1.Len() != v2.Len() && !(conf.AllowUnexpectedElements && v1.Len() < v2.Len()) {
 			mismatchf(mLen, v1.Len(), v2.Len())
 			return false
 		}
@@ -128,7 +184,7 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 		}
 		for i := 0; i < v1.Len(); i++ {
 			if ok := deepValueEqual(
-				fmt.Sprintf("%s[%d]", path, i),
+				fmt.Sprintf("%s[%d]", path, i), appendSeg(segs, i),
 				v1.Index(i), v2.Index(i), visited, depth+1, d, conf); !ok {
 				return false
 			}
@@ -142,9 +198,9 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 			}
 			return true
 		}
-		return deepValueEqual(path, v1.Elem(), v2.Elem(), visited, depth+1, d, conf)
+		return deepValueEqual(path, segs, v1.Elem(), v2.Elem(), visited, depth+1, d, conf)
 	case reflect.Ptr:
-		return deepValueEqual("(*"+path+")", v1.Elem(), v2.Elem(), visited, depth+1, d, conf)
+		return deepValueEqual("(*"+path+")", segs, v1.Elem(), v2.Elem(), visited, depth+1, d, conf)
 	case reflect.Struct:
 		if v1.NumField() > v2.NumField() {
 			mismatchf(mLen, v1.NumField(), v2.NumField())
@@ -169,7 +225,7 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 			if fieldNotFound {
 				mismatchf(mFieldNotFound, path)
 				result = false
-			} else if ok := deepValueEqual(path, v1.Field(i), v2.Field(i), visited, depth+1, d, conf); !ok {
+			} else if ok := deepValueEqual(path, appendSeg(segs, fieldName), v1.Field(i), v2.Field(i), visited, depth+1, d, conf); !ok {
 				result = false
 			}
 		}
@@ -179,9 +235,9 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 			mismatchf(mNilVsNonNil)
 			return false
 		}
-		if v1.Len() > v2.Len() {
+		if v1.Len()-absentOptionalKeys(segs, v1, v2, conf) > v2.Len() {
 			mismatchf(mLen, v1.Len(), v2.Len())
-		} else if v2.Len() > v1.Len() && conf.AllowUnexpectedKeys == false {
+		} else if v2.Len() > v1.Len() && (conf.AllowUnexpectedKeys == false || v1.Len() == 0 && conf.StrictEmptyObjects) {
 			mismatchf(mLen, v1.Len(), v2.Len())
 			return false
 		}
@@ -208,10 +264,12 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 				}
 			}
 
-			if keyFound == nil {
+			if keyFound == nil && conf.Rules.IsOptional(appendSeg(segs, interfaceOf(v1k))) {
+				//the optional value is absent
+			} else if keyFound == nil {
 				mismatchf(mKeyNotFound, p)
 				result = false
-			} else if ok := deepValueEqual(p, v1.MapIndex(v1k), v2.MapIndex(v1k), visited, depth+1, d, conf); !ok {
+			} else if ok := deepValueEqual(p, appendSeg(segs, keyFound), v1.MapIndex(v1k), v2.MapIndex(v1k), visited, depth+1, d, conf); !ok {
 				result = false
 			}
 		}
@@ -254,7 +312,7 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 		}
 		return true
 	case reflect.String:
-		if v1.String() != v2.String() {
+		if v1.String() != v2.String() && !(v1.Type() == numberType && numbersEqual(v1.String(), v2.String())) {
 			mismatchf(mUnequal)
 			return false
 		}
@@ -300,7 +358,153 @@ func DeepDiff(a1, a2 interface{}, conf *DiffConfig) (bool, Differences) {
 	v1 := reflect.ValueOf(a1)
 	v2 := reflect.ValueOf(a2)
 
-	return deepValueEqual(conf.RootPath, v1, v2, make(map[visit]bool), 0, &d, conf), d
+	return deepValueEqual(conf.RootPath, nil, v1, v2, make(map[visit]bool), 0, &d, conf), d
+}
+
+//MatchRule checks the actual value against the rule set without traversing its children
+func MatchRule(expected, actual interface{}, rs *matchers.RuleSet, rootPath string) (bool, Differences) {
+	if ok, how := rs.Matches(expected, actual); !ok {
+		return false, Differences{newMismatch(reflect.ValueOf(expected), reflect.ValueOf(actual), rootPath, mRule, how)}
+	}
+	return true, nil
+}
+
+//ruleValueEqual compares the values using the matching rule set resolved for their path. Collections are
+//traversed so the rules cascade to their children, when the rules compare by example every actual element
+//of an array is compared to the first expected element.
+func ruleValueEqual(path string, segs []interface{}, v1, v2 reflect.Value, rs *matchers.RuleSet, visited map[visit]bool, depth int, d *Differences, conf *DiffConfig) bool {
+	mismatchf := func(typ mismatchType, a ...interface{}) {
+		d.Append(newMismatch(v1, v2, path, typ, a...))
+	}
+
+	if ok, how := rs.Matches(interfaceOf(v1), interfaceOf(v2)); !ok {
+		mismatchf(mRule, how)
+		return false
+	} else if conf.OnRuleMatch != nil {
+		conf.OnRuleMatch(&RuleMatch{Path: jsonPath(segs), Rule: rs.String()})
+	}
+
+	switch v1.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v2.Kind() != reflect.Slice && v2.Kind() != reflect.Array {
+			mismatchf(mType, v1.Type(), v2.Type())
+			return false
+		}
+		if variants := rs.ArrayVariants(); len(variants) > 0 {
+			return containsVariants(path, v1, v2, variants, d, conf)
+		}
+		if !rs.ComparesByExample() && v1.Len() != v2.Len() {
+			mismatchf(mLen, v1.Len(), v2.Len())
+			return false
+		}
+		result := true
+		for i := 0; i < v2.Len() && v1.Len() > 0; i++ {
+			example := v1.Index(0)
+			if !rs.ComparesByExample() {
+				example = v1.Index(i)
+			}
+			if ok := deepValueEqual(fmt.Sprintf("%s[%d]", path, i), appendSeg(segs, i),
+				example, v2.Index(i), visited, depth+1, d, conf); !ok {
+				result = false
+			}
+		}
+		return result
+	case reflect.Map:
+		if v2.Kind() != reflect.Map {
+			mismatchf(mType, v1.Type(), v2.Type())
+			return false
+		}
+		if rs.MatchesEach() {
+			return eachValueEqual(path, segs, v1, v2, visited, depth, d, conf)
+		}
+		result := true
+		for _, k := range v1.MapKeys() {
+			p := path + "[" + fmt.Sprintf("%#v", interfaceOf(k)) + "]"
+			if av := v2.MapIndex(k); !av.IsValid() && conf.Rules.IsOptional(appendSeg(segs, interfaceOf(k))) {
+				//the optional value is absent
+			} else if !av.IsValid() {
+				mismatchf(mKeyNotFound, p)
+				result = false
+			} else if ok := deepValueEqual(p, appendSeg(segs, interfaceOf(k)), v1.MapIndex(k), av, visited, depth+1, d, conf); !ok {
+				result = false
+			}
+		}
+		return result
+	}
+	return true
+}
+
+//containsVariants checks every variant of an arrayContains matcher is matched by an element of the actual array,
+//whatever its position. The elements are matched by the rules of the variant rather than the rules of the array
+func containsVariants(path string, v1, v2 reflect.Value, variants []*matchers.Variant, d *Differences, conf *DiffConfig) bool {
+	result := true
+	for _, variant := range variants {
+		if variant.Index < 0 || variant.Index >= v1.Len() {
+			d.Append(newMismatch(v1, v2, path, mVariantNotFound, variant.Index))
+			result = false
+			continue
+		}
+		vc := *conf
+		vc.Rules, vc.OnRuleMatch = variant.Rules, nil
+		example, found := v1.Index(variant.Index), false
+		for i := 0; i < v2.Len() && !found; i++ {
+			var ignored Differences
+			found = deepValueEqual(fmt.Sprintf("%s[%d]", path, i), nil, example, v2.Index(i), make(map[visit]bool), 0, &ignored, &vc)
+		}
+		if !found {
+			d.Append(newMismatch(v1, v2, path, mVariantNotFound, variant.Index))
+			result = false
+		}
+	}
+	return result
+}
+
+//absentOptionalKeys counts the keys of the expected object which are absent from the actual object and optional
+func absentOptionalKeys(segs []interface{}, v1, v2 reflect.Value, conf *DiffConfig) int {
+	if len(conf.Rules) == 0 {
+		return 0
+	}
+	n := 0
+	for _, k := range v1.MapKeys() {
+		if !v2.MapIndex(k).IsValid() && conf.Rules.IsOptional(appendSeg(segs, interfaceOf(k))) {
+			n++
+		}
+	}
+	return n
+}
+
+//eachValueEqual compares every actual value of the object to the example of its first expected key, as the keys
+//of the object are not known in advance
+func eachValueEqual(path string, segs []interface{}, v1, v2 reflect.Value, visited map[visit]bool, depth int, d *Differences, conf *DiffConfig) bool {
+	expectedKeys := sortedKeys(v1)
+	if len(expectedKeys) == 0 {
+		return true
+	}
+	example := v1.MapIndex(expectedKeys[0])
+
+	result := true
+	for _, k := range sortedKeys(v2) {
+		p := path + "[" + fmt.Sprintf("%#v", interfaceOf(k)) + "]"
+		if ok := deepValueEqual(p, appendSeg(segs, interfaceOf(k)), example, v2.MapIndex(k), visited, depth+1, d, conf); !ok {
+			result = false
+		}
+	}
+	return result
+}
+
+//sortedKeys returns the keys of the map in the order of their formatted values
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(a, b int) bool {
+		return fmt.Sprint(interfaceOf(keys[a])) < fmt.Sprint(interfaceOf(keys[b]))
+	})
+	return keys
+}
+
+func appendSeg(segs []interface{}, seg interface{}) []interface{} {
+	s := make([]interface{}, len(segs), len(segs)+1)
+	copy(s, segs)
+	return append(s, seg)
 }
 
 // interfaceOf returns v.Interface() even if v.CanInterface() == false.
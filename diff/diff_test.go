@@ -1,8 +1,13 @@
 package diff
 
 import (
+	"encoding/json"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/SEEK-Jobs/pact-go/matchers"
 )
 
 type Basic struct {
@@ -50,6 +55,8 @@ var equalTests = []DiffEqualTest{
 	{map[int]string{1: "one", 2: "two"}, map[int]string{2: "two", 1: "one"}, true, ""},
 	{map[int]string{1: "one", 2: "two"}, map[int]string{2: "two", 1: "one", 3: "three"}, true, ""},
 	{fn1, fn2, true, ""},
+	{json.Number("1.0"), json.Number("1"), true, ""},
+	{json.Number("1e3"), json.Number("1000"), true, ""},
 
 	// Nil vs empty: they're the same (difference from normal DeepDiff)
 	{[]int{}, []int(nil), true, ""},
@@ -75,6 +82,7 @@ var inequalTests = []*DiffInequalTest{
 	newInequalTest(fn1, fn3, fn1, fn3, rootPath, mNonNilFunc),
 	newInequalTest([]interface{}{nil}, []interface{}{"a"}, nil, "a", rootPath+"[0]", mNilVsNonNil),
 	newInequalTest(1, 1.0, 1, 1.0, rootPath, mType, reflect.TypeOf(1), reflect.TypeOf(1.0)),
+	newInequalTest(json.Number("9007199254740993"), json.Number("9007199254740992"), json.Number("9007199254740993"), json.Number("9007199254740992"), rootPath, mUnequal),
 	newInequalTest([]int{1, 2, 3}, [3]int{1, 2, 3}, []int{1, 2, 3}, [3]int{1, 2, 3}, rootPath, mType, "[]int", "[3]int"),
 }
 
@@ -180,3 +188,106 @@ func TestDeepDiffUnexportedMap(t *testing.T) {
 		t.Error("DeepDiff(x1, y1) = true, want false")
 	}
 }
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestDeepDiffStrictObjectsIgnoreKeyOrder(t *testing.T) {
+	a := decodeJSON(t, `{"id": 1, "name": "John", "address": {"street": "Collins", "city": "Melbourne"}, "tags": ["a", "b"]}`)
+	b := decodeJSON(t, `{"tags": ["a", "b"], "address": {"city": "Melbourne", "street": "Collins"}, "name": "John", "id": 1}`)
+	if ok, diffs := DeepDiff(a, b, &DiffConfig{AllowUnexpectedKeys: false, RootPath: rootPath}); !ok {
+		t.Errorf("DeepDiff(reordered keys) = false, want true: %s", diffs)
+	}
+}
+
+func TestDeepDiffStrictArraysAreOrdered(t *testing.T) {
+	a := decodeJSON(t, `{"tags": ["a", "b"]}`)
+	b := decodeJSON(t, `{"tags": ["b", "a"]}`)
+	if ok, _ := DeepDiff(a, b, &DiffConfig{AllowUnexpectedKeys: false, RootPath: rootPath}); ok {
+		t.Error("DeepDiff(reordered array) = true, want false")
+	}
+}
+
+func TestDeepDiffAppliesMatchingRules(t *testing.T) {
+	a := decodeJSON(t, `{"id": 1, "users": [{"name": "John"}]}`)
+	b := decodeJSON(t, `{"id": 2, "users": [{"name": "Jane"}, {"name": "Joe"}]}`)
+	rules := matchers.Rules{
+		"$.id":    &matchers.RuleSet{Matchers: []*matchers.Rule{&matchers.Rule{Match: "type"}}},
+		"$.users": &matchers.RuleSet{Matchers: []*matchers.Rule{&matchers.Rule{Match: "type"}}},
+	}
+	if ok, diffs := DeepDiff(a, b, &DiffConfig{RootPath: rootPath, Rules: rules}); !ok {
+		t.Errorf("DeepDiff(type rules) = false, want true: %s", diffs)
+	}
+
+	c := decodeJSON(t, `{"id": "2", "users": [{"name": "Jane"}, {"name": 3}]}`)
+	if ok, diffs := DeepDiff(a, c, &DiffConfig{RootPath: rootPath, Rules: rules}); ok {
+		t.Error("DeepDiff(type rules) = true, want false")
+	} else if len(diffs) != 2 {
+		t.Errorf("expected 2 diffs, got %s", diffs)
+	}
+}
+
+func TestDeepDiffUnexpectedElements(t *testing.T) {
+	a := decodeJSON(t, `{"tags": ["a", "b"]}`)
+	b := decodeJSON(t, `{"tags": ["a", "b", "c"]}`)
+	if ok, _ := DeepDiff(a, b, &DiffConfig{RootPath: rootPath}); ok {
+		t.Error("DeepDiff(extra elements) = true, want false")
+	}
+	if ok, diffs := DeepDiff(a, b, &DiffConfig{AllowUnexpectedElements: true, RootPath: rootPath}); !ok {
+		t.Errorf("DeepDiff(extra elements, allowed) = false, want true: %s", diffs)
+	}
+	if ok, _ := DeepDiff(b, a, &DiffConfig{AllowUnexpectedElements: true, RootPath: rootPath}); ok {
+		t.Error("DeepDiff(missing elements, allowed) = true, want false")
+	}
+}
+
+func TestDeepDiffReportsRuleMatches(t *testing.T) {
+	a := decodeJSON(t, `{"id": 1, "name": "John", "tags": ["a"]}`)
+	b := decodeJSON(t, `{"id": 2, "name": "John", "tags": ["b"]}`)
+	rules := matchers.Rules{
+		"$.id":      &matchers.RuleSet{Matchers: []*matchers.Rule{&matchers.Rule{Match: "type"}}},
+		"$.tags[*]": &matchers.RuleSet{Matchers: []*matchers.Rule{&matchers.Rule{Match: "regex", Regex: "^[a-z]$"}}},
+	}
+	var matches []string
+	conf := &DiffConfig{RootPath: rootPath, Rules: rules, OnRuleMatch: func(m *RuleMatch) {
+		matches = append(matches, m.String())
+	}}
+	if ok, diffs := DeepDiff(a, b, conf); !ok {
+		t.Fatalf("DeepDiff(rules) = false, want true: %s", diffs)
+	}
+
+	sort.Strings(matches)
+	expected := []string{"$.id matched by type", "$.tags[0] matched by regex ^[a-z]$"}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("expected the rule matches %v, got %v", expected, matches)
+	}
+}
+
+func TestDeepDiffArrayContainsMatchesVariantsInAnyPosition(t *testing.T) {
+	var rules matchers.Rules
+	err := json.Unmarshal([]byte(`{"$.events": {"matchers": [{"match": "arrayContains", "variants": [
+		{"index": 0, "rules": {"$.id": {"matchers": [{"match": "type"}]}}},
+		{"index": 1, "rules": {"$.at": {"matchers": [{"match": "regex", "regex": "^\\d{4}$"}]}}}
+	]}]}}`), &rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := decodeJSON(t, `{"events": [{"type": "created", "id": 1}, {"type": "deleted", "at": "2020"}]}`)
+
+	b := decodeJSON(t, `{"events": [{"type": "viewed"}, {"type": "deleted", "at": "1999"}, {"type": "created", "id": 7}]}`)
+	if ok, diffs := DeepDiff(a, b, &DiffConfig{AllowUnexpectedKeys: true, RootPath: rootPath, Rules: rules}); !ok {
+		t.Errorf("DeepDiff(arrayContains) = false, want true: %s", diffs)
+	}
+
+	c := decodeJSON(t, `{"events": [{"type": "created", "id": 7}, {"type": "deleted", "at": "yesterday"}]}`)
+	if ok, diffs := DeepDiff(a, c, &DiffConfig{AllowUnexpectedKeys: true, RootPath: rootPath, Rules: rules}); ok {
+		t.Error("DeepDiff(arrayContains) = true, want false")
+	} else if len(diffs) != 1 || !strings.Contains(diffs[0].String(), "no element matches the variant 1") {
+		t.Errorf("expected the unmatched variant, got %s", diffs)
+	}
+}
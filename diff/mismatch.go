@@ -3,6 +3,7 @@ package diff
 import (
 	"fmt"
 	"reflect"
+	"time"
 )
 
 type mismatchType int
@@ -25,19 +26,43 @@ const (
 	mKeyUnexpected
 	mNilVsNonNil
 	mNonNilFunc
+	mRule
+	mLatency
+	mFieldAccepted
+	mSchema
+	mNotAcceptable
+	mPanic
+	mPartNotFound
+	mMultipart
+	mForbiddenHeader
+	mServerError
+	mServerErrorNoBody
+	mVariantNotFound
 )
 
 var typeMsgs = map[mismatchType]string{
-	mType:            "type mismatch expected %s received %s",
-	mLen:             "length mismatch, expected %d received %d",
-	mUnequal:         "unequal",
-	mValidty:         "validity mismatch",
-	mFieldUnexpected: "unexpected field %s",
-	mFieldNotFound:   "field %s not found",
-	mKeyNotFound:     "key %s not found",
-	mKeyUnexpected:   "unexpected key %s",
-	mNilVsNonNil:     "nil vs non-nil mismatch",
-	mNonNilFunc:      "non-nil functions",
+	mType:              "type mismatch expected %s received %s",
+	mLen:               "length mismatch, expected %d received %d",
+	mUnequal:           "unequal",
+	mValidty:           "validity mismatch",
+	mFieldUnexpected:   "unexpected field %s",
+	mFieldNotFound:     "field %s not found",
+	mKeyNotFound:       "key %s not found",
+	mKeyUnexpected:     "unexpected key %s",
+	mNilVsNonNil:       "nil vs non-nil mismatch",
+	mNonNilFunc:        "non-nil functions",
+	mRule:              "matching rule failed, %s",
+	mLatency:           "response took %s, longer than the maximum latency of %s",
+	mFieldAccepted:     "request with unexpected field %s was accepted with status %d, expected a 4xx status",
+	mSchema:            "schema violation, %s",
+	mNotAcceptable:     "content type %s is not acceptable for the request accept header %s",
+	mPanic:             "panicked during the %s: %v\n%s",
+	mPartNotFound:      "part with content id %s not found",
+	mMultipart:         "invalid multipart body, %s",
+	mForbiddenHeader:   "forbidden header %s is present",
+	mServerError:       "provider returned the server error %d, expected status %d, the response body was: %s",
+	mServerErrorNoBody: "provider returned the server error %d, expected status %d",
+	mVariantNotFound:   "no element matches the variant %d of the arrayContains matcher",
 }
 
 func newMismatch(v1, v2 reflect.Value, path string, typ mismatchType, typMsgArgs ...interface{}) *Mismatch {
@@ -50,6 +75,79 @@ func newMismatch(v1, v2 reflect.Value, path string, typ mismatchType, typMsgArgs
 	}
 }
 
+//LatencyMismatch is the mismatch of a response which took longer than the maximum latency
+func LatencyMismatch(max, actual time.Duration) *Mismatch {
+	return newMismatch(reflect.ValueOf(max), reflect.ValueOf(actual), "[\"latency\"]", mLatency, actual, max)
+}
+
+//UnexpectedFieldAcceptedMismatch is the mismatch of a provider which did not reject a request with an unexpected field
+func UnexpectedFieldAcceptedMismatch(field string, status int) *Mismatch {
+	return newMismatch(reflect.ValueOf("4xx"), reflect.ValueOf(status), "[\"status\"]", mFieldAccepted, field, status)
+}
+
+//SchemaMismatch is the mismatch of a response value which does not validate against the response schema
+func SchemaMismatch(path, message string, actual interface{}) *Mismatch {
+	return newMismatch(reflect.Value{}, reflect.ValueOf(actual), path, mSchema, message)
+}
+
+//NotAcceptableMismatch is the mismatch of a response whose content type the request does not accept
+func NotAcceptableMismatch(accept, contentType string) *Mismatch {
+	return newMismatch(reflect.ValueOf(accept), reflect.ValueOf(contentType), "[\"header\"][\"content-type\"]", mNotAcceptable, contentType, accept)
+}
+
+//PanicMismatch is the failure of an interaction whose verification panicked, with the stack trace of the panic
+func PanicMismatch(stage string, value interface{}, stack []byte) *Mismatch {
+	return newMismatch(reflect.Value{}, reflect.ValueOf(value), "[\"interaction\"]", mPanic, stage, value, stack)
+}
+
+//PartNotFoundMismatch is the mismatch of a multipart body without the part of the content id
+func PartNotFoundMismatch(path, contentID string) *Mismatch {
+	return newMismatch(reflect.ValueOf(contentID), reflect.Value{}, path, mPartNotFound, contentID)
+}
+
+//MultipartMismatch is the mismatch of a multipart body which cannot be parsed
+func MultipartMismatch(path string, err error) *Mismatch {
+	return newMismatch(reflect.Value{}, reflect.Value{}, path, mMultipart, err)
+}
+
+//ForbiddenHeaderMismatch is the mismatch of a response with a header it must not return
+func ForbiddenHeaderMismatch(path, header string, actual []string) *Mismatch {
+	return newMismatch(reflect.Value{}, reflect.ValueOf(actual), path, mForbiddenHeader, header)
+}
+
+//ServerErrorMismatch is the mismatch of an unexpected 5xx response, with the snippet of its error body
+func ServerErrorMismatch(expected, actual int, body string) *Mismatch {
+	if body == "" {
+		return newMismatch(reflect.ValueOf(expected), reflect.ValueOf(actual), "[\"status\"]", mServerErrorNoBody, actual, expected)
+	}
+	return newMismatch(reflect.ValueOf(expected), reflect.ValueOf(actual), "[\"status\"]", mServerError, actual, expected, body)
+}
+
+//Path is where the mismatch is in the response, e.g. ["body"]["id"]
+func (m *Mismatch) Path() string {
+	return m.path
+}
+
 func (m *Mismatch) String() string {
 	return fmt.Sprintf("mismatch at %s: %s", m.path, m.how)
 }
+
+//Expected is the expected value at the path, nil when there is none
+func (m *Mismatch) Expected() interface{} {
+	return interfaceOf(m.v1)
+}
+
+//Actual is the value received at the path, nil when there is none
+func (m *Mismatch) Actual() interface{} {
+	return interfaceOf(m.v2)
+}
+
+//Description describes how the values mismatched, e.g. field id not found
+func (m *Mismatch) Description() string {
+	return m.how
+}
+
+//Unequal reports whether the values are of the same type but not equal, rather than mismatched by a rule or structure
+func (m *Mismatch) Unequal() bool {
+	return m.typ == mUnequal
+}
@@ -0,0 +1,76 @@
+package pact
+
+import (
+	"bufio"
+	"errors"
+	goio "io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
+
+const eventStreamMediaType = "text/event-stream"
+
+var errNoEvent = errors.New("The provider closed the event stream before its first event.")
+
+//isEventStream returns true when the headers have the content type of server-sent events
+func isEventStream(h http.Header) bool {
+	if h == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	return err == nil && mediaType == eventStreamMediaType
+}
+
+//firstEventResponse reads the event stream of the provider response until its first complete event and returns
+//the response with the data of the event as its body, the rest of the stream is not read. The data is read as json
+//when the expected body is not text
+func firstEventResponse(resp *http.Response, expected *provider.Response) (*provider.Response, error) {
+	data, err := readFirstEvent(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r := provider.NewResponse(resp.StatusCode, resp.Header)
+	if _, ok := expected.GetBody().(string); !ok && expected.GetBody() != nil {
+		var body interface{}
+		if err := provider.DecodeJSON([]byte(data), &body); err == nil {
+			return r, r.SetBody(body)
+		}
+	}
+	return r, r.SetBody(data)
+}
+
+//readFirstEvent returns the data of the first event of the stream, its data lines joined by new lines. The events
+//without data are skipped as the event stream format specifies
+func readFirstEvent(stream goio.Reader) (string, error) {
+	if stream == nil {
+		return "", errNoEvent
+	}
+
+	var data []string
+	s := bufio.NewScanner(stream)
+	for s.Scan() {
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if line == "" {
+			if d := strings.Join(data, "\n"); d != "" {
+				return d, nil
+			}
+			data = nil
+			continue
+		}
+		field, value := line, ""
+		if n := strings.Index(line, ":"); n >= 0 {
+			field, value = line[:n], strings.TrimPrefix(line[n+1:], " ")
+		}
+		if field == "data" {
+			data = append(data, value)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+	return "", errNoEvent
+}
@@ -0,0 +1,78 @@
+package pact
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const eventStreamPact = `{
+	"consumer": {"name": "dashboard app"},
+	"provider": {"name": "go api"},
+	"interactions": [
+		{
+			"description": "subscribe to the user updates",
+			"request": {"method": "GET", "path": "/user/events"},
+			"response": {
+				"status": 200,
+				"headers": {"Content-Type": "text/event-stream"},
+				"body": {"id": 23, "name": "alice"}
+			}
+		}
+	],
+	"metadata": {"pactSpecification": {"version": "2.0.0"}}
+}`
+
+func eventStreamProvider(firstEvent string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": connected\nretry: 1000\n\n")
+		fmt.Fprint(w, firstEvent)
+		fmt.Fprint(w, "event: user\ndata: {\"id\": 24, \"name\": \"bob\"}\n\n")
+		w.(http.Flusher).Flush()
+		//the stream stays open until the verifier closes the connection
+		<-r.Context().Done()
+	}))
+}
+
+func verifyEventStream(s *httptest.Server) (*VerificationResult, error) {
+	u, _ := url.Parse(s.URL)
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		PactReader(strings.NewReader(eventStreamPact)).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	err := v.Verify()
+	return v.Result(), err
+}
+
+func Test_Verifier_EventStream_MatchesTheFirstEvent(t *testing.T) {
+	s := eventStreamProvider("event: user\ndata: {\"id\": 23,\ndata: \"name\": \"alice\"}\r\n\r\n")
+	defer s.Close()
+
+	if _, err := verifyEventStream(s); err != nil {
+		t.Errorf("expected the first event to match, got %s", err)
+	}
+}
+
+func Test_Verifier_EventStream_FailsWhenTheFirstEventMismatches(t *testing.T) {
+	s := eventStreamProvider("event: user\ndata: {\"id\": 23, \"name\": \"carol\"}\n\n")
+	defer s.Close()
+
+	r, err := verifyEventStream(s)
+	if err != errVerficationFailed {
+		t.Fatalf("expected the verification to fail, got %v", err)
+	}
+	if d := r.Interactions[0].Differences; len(d) != 1 || !strings.Contains(d.Error(), `["body"]["name"]`) {
+		t.Errorf("expected a mismatch of the name in the first event, got %v", d)
+	}
+}
+
+func Test_ReadFirstEvent_FailsWithoutAnEvent(t *testing.T) {
+	if _, err := readFirstEvent(strings.NewReader(": connected\n\ndata:\n\nevent: user\ndata: incomplete")); err != errNoEvent {
+		t.Errorf("expected no event, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package pact
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+	"github.com/SEEK-Jobs/pact-go/provider"
+)
+
+var exploreDescriptionMsg = "explore %s %s"
+
+//EndpointSpec is an endpoint of the provider which ExploreEndpoints probes when no interaction of the pacts covers it
+type EndpointSpec struct {
+	Method string
+	//Path may include a query, e.g. /users?page=2
+	Path    string
+	Headers http.Header
+	//Body is sent as it is, set its Content-Type in the Headers
+	Body string
+	//Statuses are the statuses the endpoint is expected to respond with, any status below 500 when empty
+	Statuses []int
+}
+
+//ExplorationResult is how the provider responded to a probed endpoint, the findings do not fail the verification
+type ExplorationResult struct {
+	Method string
+	Path   string
+	Status int
+	//Unexpected is set when the status is not one the endpoint is expected to respond with or the request failed
+	Unexpected bool
+	//Error is the error of the request which could not be sent or answered
+	Error error
+}
+
+func (e *ExplorationResult) String() string {
+	if e.Error != nil {
+		return fmt.Sprintf("%s %s failed: %s", e.Method, e.Path, e.Error)
+	}
+	return fmt.Sprintf("%s %s responded %d", e.Method, e.Path, e.Status)
+}
+
+//expects returns true when the endpoint is expected to respond with the status
+func (e *EndpointSpec) expects(status int) bool {
+	if len(e.Statuses) == 0 {
+		return status < 500
+	}
+	for _, s := range e.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+//uncoveredEndpoints returns the endpoints whose method and path no interaction of the pacts requests
+func uncoveredEndpoints(endpoints []EndpointSpec, pacts []*loadedPact) []EndpointSpec {
+	covered := make(map[string]bool)
+	for _, p := range pacts {
+		for _, i := range p.file.Interactions {
+			covered[endpointKey(i.Request.Method, i.Request.Path)] = true
+		}
+	}
+
+	var uncovered []EndpointSpec
+	for _, e := range endpoints {
+		if !covered[endpointKey(e.Method, e.Path)] {
+			uncovered = append(uncovered, e)
+		}
+	}
+	return uncovered
+}
+
+//endpointKey is the method and the path without its query and trailing slash
+func endpointKey(method, path string) string {
+	if n := strings.Index(path, "?"); n >= 0 {
+		path = path[:n]
+	}
+	return strings.ToUpper(method) + " " + strings.TrimSuffix(path, "/")
+}
+
+//Explore sends the requests of the endpoints to the provider, the same way as the requests of the interactions
+func (v *pactValidator) Explore(ctx context.Context, endpoints []EndpointSpec) []*ExplorationResult {
+	results := make([]*ExplorationResult, len(endpoints))
+	for n := range endpoints {
+		e := &endpoints[n]
+		r := &ExplorationResult{Method: strings.ToUpper(e.Method), Path: e.Path}
+		r.Status, r.Error = v.probe(ctx, e)
+		r.Unexpected = r.Error != nil || !e.expects(r.Status)
+		results[n] = r
+	}
+	return results
+}
+
+func (v *pactValidator) probe(ctx context.Context, e *EndpointSpec) (int, error) {
+	if err := v.resolveURL(); err != nil {
+		return 0, err
+	}
+	u, err := url.Parse(e.Path)
+	if err != nil {
+		return 0, err
+	}
+	req := provider.NewRequest(strings.ToUpper(e.Method), u.Path, u.RawQuery, e.Headers)
+	if e.Body != "" {
+		if err := req.SetBody([]byte(e.Body)); err != nil {
+			return 0, err
+		}
+	}
+	i := &consumer.Interaction{Description: fmt.Sprintf(exploreDescriptionMsg, req.Method, e.Path), Request: req,
+		Response: provider.NewResponse(0, nil)}
+
+	auth, err := v.authHeader()
+	if err != nil {
+		return 0, err
+	}
+	if err := v.opts.limiter.wait(ctx); err != nil {
+		return 0, err
+	}
+	httpReq, err := v.newRequest(ctx, i, auth)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := v.c.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
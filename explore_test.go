@@ -0,0 +1,73 @@
+package pact
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_Verifier_ExploreEndpoints_ReportsTheUncoveredEndpointsSeparately(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	mux.HandleFunc("/admin", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var summary bytes.Buffer
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ExploreEndpoints([]EndpointSpec{
+			{Method: "GET", Path: "/user?id=7"},
+			{Method: "GET", Path: "/health"},
+			{Method: "GET", Path: "/admin"},
+			{Method: "delete", Path: "/missing", Statuses: []int{http.StatusMethodNotAllowed}},
+		}).
+		SummaryWriter(&summary)
+	if err := v.Verify(); err != nil {
+		t.Fatalf("expected the findings not to fail the verification, got %s", err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 2 || len(r.Failures()) != 0 {
+		t.Errorf("expected the contract results to be kept apart, got %d interactions", len(r.Interactions))
+	}
+	unexpected := make(map[string]bool)
+	for _, e := range r.Explorations {
+		unexpected[e.Method+" "+e.Path] = e.Unexpected
+	}
+	expected := map[string]bool{"GET /health": false, "GET /admin": true, "DELETE /missing": true}
+	if len(unexpected) != len(expected) {
+		t.Fatalf("expected the covered endpoint to be skipped, got %v", unexpected)
+	}
+	for endpoint, u := range expected {
+		if unexpected[endpoint] != u {
+			t.Errorf("expected %s to be unexpected %v, got %v", endpoint, u, unexpected[endpoint])
+		}
+	}
+
+	if !strings.Contains(summary.String(), "Explored 3 endpoints not covered by the pacts, 2 unexpected") ||
+		!strings.Contains(summary.String(), "GET /admin responded 500") {
+		t.Errorf("expected the findings in the summary, got %s", summary.String())
+	}
+	var b bytes.Buffer
+	if err := r.WriteJSONReport(&b); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(b.String(), `"explorations"`) {
+		t.Errorf("expected the findings in the report, got %s", b.String())
+	}
+}
@@ -0,0 +1,25 @@
+package pact
+
+//the stages of a verification a FaultInjector can fail
+const (
+	//FaultStageDownload is the download of every pact, before it is read
+	FaultStageDownload = "download"
+	//FaultStageSetup is the setup of every interaction, before its states are set up
+	FaultStageSetup = "setup"
+	//FaultStageRequest is the request of every interaction, before it is sent to the provider
+	FaultStageRequest = "request"
+	//FaultStagePublish is the writing or publishing of the verification results, before they are written
+	FaultStagePublish = "publish"
+)
+
+//FaultInjector is called at every stage of a verification, an error fails the stage as if the stage itself had
+//failed. It is meant for testing only, e.g. how a wrapper or a build reacts to a failing pact broker or provider
+type FaultInjector func(stage string) error
+
+//injectFault returns the error the fault injector forces at the stage, nil when none is set
+func (o *validationOptions) injectFault(stage string) error {
+	if o.faultInjector == nil {
+		return nil
+	}
+	return o.faultInjector(stage)
+}
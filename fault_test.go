@@ -0,0 +1,85 @@
+package pact
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_Verifier_SetFaultInjector_FailsEachStage(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+	dir, err := ioutil.TempDir("", "pact-fault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		stage  string
+		reason FailureReason
+	}{
+		{stage: FaultStageDownload},
+		{stage: FaultStageSetup, reason: StateSetupError},
+		{stage: FaultStageRequest, reason: RequestError},
+		{stage: FaultStagePublish},
+	}
+	for _, c := range cases {
+		fault := errors.New("injected " + c.stage + " fault")
+		var stages []string
+		v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			WriteVerificationResults(filepath.Join(dir, c.stage+".json"), "1.0.0", "").
+			SetFaultInjector(func(stage string) error {
+				stages = append(stages, stage)
+				if stage == c.stage {
+					return fault
+				}
+				return nil
+			}).
+			SummaryWriter(ioutil.Discard)
+
+		err := v.Verify()
+		if err == nil || !strings.Contains(err.Error(), fault.Error()) {
+			t.Errorf("expected the %s fault to fail the verification, got %v", c.stage, err)
+		}
+		if stages[len(stages)-1] != c.stage {
+			t.Errorf("expected the verification to stop at the %s stage, got the stages %v", c.stage, stages)
+		}
+		if c.reason != NoFailure {
+			r := v.Result().Interactions[0]
+			if r.Reason != c.reason || r.Error != fault {
+				t.Errorf("expected the %s fault to fail the interaction with %s, got %s %v", c.stage, c.reason, r.Reason, r.Error)
+			}
+		}
+	}
+}
+
+func Test_Verifier_SetFaultInjector_NilInjectsNoFault(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SetFaultInjector(nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Errorf("expected no fault without an injector, got %s", err)
+	}
+}
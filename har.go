@@ -0,0 +1,152 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const harEncodingBase64 = "base64"
+
+var (
+	errReadHARMsg     = "Failed to read the HAR file '%s': %s"
+	errHARNoEntryMsg  = "No response was recorded in the HAR file for %s %s."
+	errHARBadEntryMsg = "The HAR entry %d has an invalid request url: %s"
+)
+
+//harURL is the base url of the requests answered from a HAR file
+var harURL = &url.URL{Scheme: "http", Host: "har"}
+
+type harFile struct {
+	Log struct {
+		Entries []*harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	//query is the parsed query of the request url
+	query   url.Values
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		Status  int          `json:"status"`
+		Headers []*harHeader `json:"headers"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+//harTransport answers the requests with the responses recorded in a HAR file, matched by method and path. Among the
+//entries of a method and path the one recorded with the same query is preferred, otherwise they answer in order and
+//the last entry is reused once all were answered
+type harTransport struct {
+	mu      sync.Mutex
+	entries map[string][]*harEntry
+	served  map[string]int
+}
+
+//newHARTransport reads the HAR file
+func newHARTransport(path string) (*harTransport, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(errReadHARMsg, path, err)
+	}
+	var f harFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf(errReadHARMsg, path, err)
+	}
+
+	t := &harTransport{entries: make(map[string][]*harEntry), served: make(map[string]int)}
+	for n, e := range f.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf(errReadHARMsg, path, fmt.Sprintf(errHARBadEntryMsg, n, err))
+		}
+		e.query = u.Query()
+		key := harKey(e.Request.Method, u.Path)
+		t.entries[key] = append(t.entries[key], e)
+	}
+	return t, nil
+}
+
+func harKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func (t *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	key := harKey(req.Method, req.URL.Path)
+	entries := t.entries[key]
+	if len(entries) == 0 {
+		return nil, fmt.Errorf(errHARNoEntryMsg, req.Method, req.URL.Path)
+	}
+
+	query := req.URL.Query()
+	for _, e := range entries {
+		if reflect.DeepEqual(e.query, query) {
+			return e.response(req)
+		}
+	}
+
+	t.mu.Lock()
+	n := t.served[key]
+	t.served[key]++
+	t.mu.Unlock()
+	if n >= len(entries) {
+		n = len(entries) - 1
+	}
+	return entries[n].response(req)
+}
+
+//response builds the recorded response of the entry
+func (e *harEntry) response(req *http.Request) (*http.Response, error) {
+	body := []byte(e.Response.Content.Text)
+	if e.Response.Content.Encoding == harEncodingBase64 {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(e.Response.Content.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	header := make(http.Header)
+	for _, h := range e.Response.Headers {
+		header.Add(h.Name, h.Value)
+	}
+	if header.Get("Content-Type") == "" && e.Response.Content.MimeType != "" {
+		header.Set("Content-Type", e.Response.Content.MimeType)
+	}
+	//the recorded body is already decoded
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Response.Status, http.StatusText(e.Response.Status)),
+		StatusCode:    e.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	utf8BOM                  = []byte{0xEF, 0xBB, 0xBF}
+	errUnsupportedCharsetMsg = "Pact file is encoded with unsupported charset '%s', only utf-8, utf-16 and iso-8859-1 are supported."
+	errInvalidUTF16Msg       = "Pact file is not valid %s, it has an odd number of bytes."
+)
+
+//charsetFromContentType returns the charset parameter of the content type, defaults to utf-8
+func charsetFromContentType(contentType string) string {
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return params["charset"]
+	}
+	return "utf-8"
+}
+
+//toUTF8 transcodes the pact content from the charset to utf-8 and strips any byte order mark
+func toUTF8(b []byte, charset string) ([]byte, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return bytes.TrimPrefix(b, utf8BOM), nil
+	case "iso-8859-1", "latin1", "latin-1":
+		var buf bytes.Buffer
+		for _, c := range b {
+			buf.WriteRune(rune(c))
+		}
+		return buf.Bytes(), nil
+	case "utf-16", "utf-16le", "utf-16be":
+		return utf16ToUTF8(b, strings.ToLower(charset))
+	}
+	return nil, fmt.Errorf(errUnsupportedCharsetMsg, charset)
+}
+
+func utf16ToUTF8(b []byte, charset string) ([]byte, error) {
+	var order binary.ByteOrder = binary.BigEndian
+	if charset == "utf-16le" {
+		order = binary.LittleEndian
+	}
+	//the byte order mark takes precedence when present
+	if len(b) >= 2 && b[0] == 0xFF && b[1] == 0xFE {
+		order, b = binary.LittleEndian, b[2:]
+	} else if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		order, b = binary.BigEndian, b[2:]
+	}
+	if len(b)%2 != 0 {
+		return nil, fmt.Errorf(errInvalidUTF16Msg, charset)
+	}
+
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = order.Uint16(b[i*2:])
+	}
+	runes := utf16.Decode(units)
+	buf := make([]byte, 0, len(runes))
+	for _, r := range runes {
+		buf = utf8.AppendRune(buf, r)
+	}
+	return buf, nil
+}
@@ -0,0 +1,28 @@
+package io
+
+import "testing"
+
+func Test_Charset_DefaultsToUTF8(t *testing.T) {
+	if c := charsetFromContentType("application/json"); c != "utf-8" {
+		t.Errorf("expected utf-8, got %s", c)
+	}
+	if c := charsetFromContentType("application/json; charset=UTF-16LE"); c != "UTF-16LE" {
+		t.Errorf("expected UTF-16LE, got %s", c)
+	}
+}
+
+func Test_Charset_TranscodesUTF16(t *testing.T) {
+	le := []byte{0xFF, 0xFE, '{', 0, '}', 0}
+	if b, err := toUTF8(le, "utf-16"); err != nil {
+		t.Error(err)
+	} else if string(b) != "{}" {
+		t.Errorf("expected {}, got %q", b)
+	}
+
+	be := []byte{0, '{', 0, '}'}
+	if b, err := toUTF8(be, "utf-16be"); err != nil {
+		t.Error(err)
+	} else if string(b) != "{}" {
+		t.Errorf("expected {}, got %q", b)
+	}
+}
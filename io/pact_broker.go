@@ -0,0 +1,284 @@
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	errUnknownEnvironmentMsg = "The environment '%s' does not exist on the pact broker."
+	errBrokerResponseMsg     = "failed to %s %s on the pact broker, the response came back with %d status code"
+)
+
+//brokerPactName is the name of a pact to verify, e.g. Pact between web (2.0.0) and api
+var brokerPactName = regexp.MustCompile(`^Pact between (.+) \(([^)]*)\) and .+$`)
+
+//BrokerPactUri returns the uri of the pact between the consumer and provider on the pact broker,
+//the latest pact is returned when the consumer version is empty
+func BrokerPactUri(baseURL, provider, consumer, consumerVersion string) string {
+	version := "latest"
+	if consumerVersion != "" {
+		version = "version/" + url.PathEscape(consumerVersion)
+	}
+	return fmt.Sprintf("%s/pacts/provider/%s/consumer/%s/%s", strings.TrimRight(baseURL, "/"),
+		url.PathEscape(provider), url.PathEscape(consumer), version)
+}
+
+//PacticipantVersion is the version of a pacticipant (consumer or provider) known to the pact broker
+type PacticipantVersion struct {
+	Pacticipant string
+	Version     string
+}
+
+type brokerEnvironments struct {
+	Embedded struct {
+		Environments []struct {
+			UUID string `json:"uuid"`
+			Name string `json:"name"`
+		} `json:"environments"`
+	} `json:"_embedded"`
+}
+
+type brokerEnvironmentVersion struct {
+	Embedded struct {
+		Pacticipant struct {
+			Name string `json:"name"`
+		} `json:"pacticipant"`
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	} `json:"_embedded"`
+}
+
+type brokerEnvironmentVersions struct {
+	Embedded struct {
+		DeployedVersions []*brokerEnvironmentVersion `json:"deployedVersions"`
+		ReleasedVersions []*brokerEnvironmentVersion `json:"releasedVersions"`
+	} `json:"_embedded"`
+}
+
+//BrokerEnvironmentVersions returns the pacticipant versions currently deployed or released to the environment,
+//ordered by pacticipant name and version
+func BrokerEnvironmentVersions(baseURL, environment string, opts *WebOptions) ([]*PacticipantVersion, error) {
+	if opts == nil {
+		opts = &WebOptions{}
+	}
+	c := &brokerClient{opts: opts}
+	base := strings.TrimRight(baseURL, "/")
+
+	var envs brokerEnvironments
+	if err := c.getJSON(base+"/environments", &envs); err != nil {
+		return nil, err
+	}
+	uuid := ""
+	for _, e := range envs.Embedded.Environments {
+		if e.Name == environment {
+			uuid = e.UUID
+		}
+	}
+	if uuid == "" {
+		return nil, fmt.Errorf(errUnknownEnvironmentMsg, environment)
+	}
+
+	var deployed, released brokerEnvironmentVersions
+	if err := c.getJSON(fmt.Sprintf("%s/environments/%s/deployed-versions/currently-deployed", base, url.PathEscape(uuid)), &deployed); err != nil {
+		return nil, err
+	}
+	if err := c.getJSON(fmt.Sprintf("%s/environments/%s/released-versions/currently-supported", base, url.PathEscape(uuid)), &released); err != nil {
+		return nil, err
+	}
+
+	var versions []*PacticipantVersion
+	seen := make(map[PacticipantVersion]bool)
+	all := append(deployed.Embedded.DeployedVersions, released.Embedded.ReleasedVersions...)
+	for _, d := range all {
+		v := PacticipantVersion{Pacticipant: d.Embedded.Pacticipant.Name, Version: d.Embedded.Version.Number}
+		if !seen[v] {
+			seen[v] = true
+			versions = append(versions, &v)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Pacticipant != versions[j].Pacticipant {
+			return versions[i].Pacticipant < versions[j].Pacticipant
+		}
+		return versions[i].Version < versions[j].Version
+	})
+	return versions, nil
+}
+
+//BrokerPactMetadata is the verification metadata the pact broker returns for a pact to verify
+type BrokerPactMetadata struct {
+	Consumer        string
+	ConsumerVersion string
+	//ShortDescription describes why the pact is verified, e.g. latest from web
+	ShortDescription string
+	//Pending is true when a failure of the pact does not fail the build of the provider
+	Pending bool
+	//WIP is true when the pact is a work in progress pact
+	WIP     bool
+	Notices []*BrokerNotice
+}
+
+//BrokerNotice is a notice of the pact broker about a pact to verify, e.g. this pact is pending
+type BrokerNotice struct {
+	//When is the stage of the verification the notice is for, e.g. before_verification
+	When string `json:"when"`
+	Text string `json:"text"`
+}
+
+type brokerPactsForVerification struct {
+	Embedded struct {
+		Pacts []struct {
+			ShortDescription       string `json:"shortDescription"`
+			VerificationProperties struct {
+				Pending bool            `json:"pending"`
+				WIP     bool            `json:"wip"`
+				Notices []*BrokerNotice `json:"notices"`
+			} `json:"verificationProperties"`
+			Links struct {
+				Self struct {
+					Name string `json:"name"`
+				} `json:"self"`
+			} `json:"_links"`
+		} `json:"pacts"`
+	} `json:"_embedded"`
+}
+
+//BrokerPactsForVerification returns the verification metadata of the latest pacts of the consumers with the
+//provider, or of the pacts of the consumers in the environment when it is set
+func BrokerPactsForVerification(baseURL, provider string, consumers []string, environment string, opts *WebOptions) ([]*BrokerPactMetadata, error) {
+	if opts == nil {
+		opts = &WebOptions{}
+	}
+	c := &brokerClient{opts: opts}
+
+	var selectors []map[string]interface{}
+	if environment != "" {
+		selectors = append(selectors, map[string]interface{}{"environment": environment})
+	}
+	for _, consumer := range consumers {
+		selectors = append(selectors, map[string]interface{}{"consumer": consumer, "latest": true})
+	}
+	body := map[string]interface{}{"consumerVersionSelectors": selectors, "includePendingStatus": true}
+	uri := fmt.Sprintf("%s/pacts/provider/%s/for-verification", strings.TrimRight(baseURL, "/"), url.PathEscape(provider))
+
+	var resp brokerPactsForVerification
+	if err := c.postJSON(uri, body, &resp); err != nil {
+		return nil, err
+	}
+	var metadata []*BrokerPactMetadata
+	for _, p := range resp.Embedded.Pacts {
+		m := &BrokerPactMetadata{ShortDescription: p.ShortDescription, Pending: p.VerificationProperties.Pending,
+			WIP: p.VerificationProperties.WIP, Notices: p.VerificationProperties.Notices}
+		if match := brokerPactName.FindStringSubmatch(p.Links.Self.Name); match != nil {
+			m.Consumer, m.ConsumerVersion = match[1], match[2]
+		}
+		metadata = append(metadata, m)
+	}
+	return metadata, nil
+}
+
+//TagPacticipantVersion tags the version of the pacticipant, the pact broker creates the pacticipant and its
+//version when they do not exist yet
+func TagPacticipantVersion(baseURL, pacticipant, version string, tags []string, opts *WebOptions) error {
+	if opts == nil {
+		opts = &WebOptions{}
+	}
+	c := &brokerClient{opts: opts}
+	for _, tag := range tags {
+		uri := fmt.Sprintf("%s/pacticipants/%s/versions/%s/tags/%s", strings.TrimRight(baseURL, "/"),
+			url.PathEscape(pacticipant), url.PathEscape(version), url.PathEscape(tag))
+		if err := c.putJSON(uri, struct{}{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//BranchPacticipantVersion adds the version of the pacticipant to the branch, the pact broker creates the
+//pacticipant, its branch and its version when they do not exist yet
+func BranchPacticipantVersion(baseURL, pacticipant, branch, version string, opts *WebOptions) error {
+	if opts == nil {
+		opts = &WebOptions{}
+	}
+	uri := fmt.Sprintf("%s/pacticipants/%s/branches/%s/versions/%s", strings.TrimRight(baseURL, "/"),
+		url.PathEscape(pacticipant), url.PathEscape(branch), url.PathEscape(version))
+	return (&brokerClient{opts: opts}).putJSON(uri, struct{}{})
+}
+
+//brokerPactLinks are the relations of a pact read from the pact broker
+type brokerPactLinks struct {
+	Links struct {
+		PublishResults struct {
+			Href string `json:"href"`
+		} `json:"pb:publish-verification-results"`
+	} `json:"_links"`
+}
+
+//PublishVerificationResults posts the verification results of a pact to its pb:publish-verification-results
+//relation, the results are the document the pact broker publishes
+func PublishVerificationResults(uri string, results interface{}, opts *WebOptions) error {
+	if opts == nil {
+		opts = &WebOptions{}
+	}
+	return (&brokerClient{opts: opts}).postJSON(uri, results, nil)
+}
+
+//brokerClient gets resources from the pact broker
+type brokerClient struct {
+	opts *WebOptions
+}
+
+func (c *brokerClient) getJSON(uri string, v interface{}) error {
+	return c.do("GET", uri, nil, v)
+}
+
+func (c *brokerClient) postJSON(uri string, body, v interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.do("POST", uri, b, v)
+}
+
+//putJSON creates the resource, its response is not read
+func (c *brokerClient) putJSON(uri string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.do("PUT", uri, b, nil)
+}
+
+func (c *brokerClient) do(method, uri string, body []byte, v interface{}) error {
+	resp, err := c.opts.Retry.Do(&http.Client{}, func() (*http.Request, error) {
+		req, err := http.NewRequest(method, uri, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Accept", "application/hal+json, application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		c.opts.setHeaders(req)
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if v == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) {
+		return nil
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(errBrokerResponseMsg, method, uri, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,126 @@
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_BrokerPactUri_Latest(t *testing.T) {
+	expected := "http://broker/pacts/provider/go%20api/consumer/chrome%20browser/latest"
+	if uri := BrokerPactUri("http://broker/", "go api", "chrome browser", ""); uri != expected {
+		t.Errorf("expected %s, got %s", expected, uri)
+	}
+}
+
+func Test_BrokerPactUri_ConsumerVersion(t *testing.T) {
+	expected := "http://broker/pacts/provider/go%20api/consumer/chrome%20browser/version/4f2a9c1"
+	if uri := BrokerPactUri("http://broker", "go api", "chrome browser", "4f2a9c1"); uri != expected {
+		t.Errorf("expected %s, got %s", expected, uri)
+	}
+}
+
+func stubBrokerEnvironments() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/environments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"environments": [
+			{"uuid": "1a2b", "name": "test"},
+			{"uuid": "3c4d", "name": "production"}
+		]}}`)
+	})
+	mux.HandleFunc("/environments/3c4d/deployed-versions/currently-deployed", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"deployedVersions": [
+			{"_embedded": {"pacticipant": {"name": "web"}, "version": {"number": "2.0.0"}}},
+			{"_embedded": {"pacticipant": {"name": "android"}, "version": {"number": "1.4.0"}}}
+		]}}`)
+	})
+	mux.HandleFunc("/environments/3c4d/released-versions/currently-supported", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"releasedVersions": [
+			{"_embedded": {"pacticipant": {"name": "android"}, "version": {"number": "1.3.0"}}},
+			{"_embedded": {"pacticipant": {"name": "android"}, "version": {"number": "1.4.0"}}}
+		]}}`)
+	})
+	mux.HandleFunc("/environments/1a2b/deployed-versions/currently-deployed", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"deployedVersions": []}}`)
+	})
+	mux.HandleFunc("/environments/1a2b/released-versions/currently-supported", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"releasedVersions": []}}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func Test_BrokerEnvironmentVersions_DeployedAndReleased(t *testing.T) {
+	s := stubBrokerEnvironments()
+	defer s.Close()
+
+	versions, err := BrokerEnvironmentVersions(s.URL, "production", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []PacticipantVersion{{"android", "1.3.0"}, {"android", "1.4.0"}, {"web", "2.0.0"}}
+	if len(versions) != len(expected) {
+		t.Fatalf("expected %d versions, got %d", len(expected), len(versions))
+	}
+	for i, e := range expected {
+		if *versions[i] != e {
+			t.Errorf("expected %v, got %v", e, *versions[i])
+		}
+	}
+}
+
+func Test_BrokerEnvironmentVersions_EmptyEnvironment(t *testing.T) {
+	s := stubBrokerEnvironments()
+	defer s.Close()
+
+	if versions, err := BrokerEnvironmentVersions(s.URL, "test", nil); err != nil {
+		t.Error(err)
+	} else if len(versions) != 0 {
+		t.Errorf("expected no versions, got %d", len(versions))
+	}
+}
+
+func Test_BrokerEnvironmentVersions_UnknownEnvironment(t *testing.T) {
+	s := stubBrokerEnvironments()
+	defer s.Close()
+
+	expected := fmt.Sprintf(errUnknownEnvironmentMsg, "staging")
+	if _, err := BrokerEnvironmentVersions(s.URL, "staging", nil); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_BrokerPactsForVerification_ReturnsMetadata(t *testing.T) {
+	var body map[string]interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/pacts/provider/api/for-verification" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"_embedded": {"pacts": [{
+			"shortDescription": "latest from web",
+			"verificationProperties": {"pending": true, "notices": [{"when": "before_verification", "text": "This pact is pending"}]},
+			"_links": {"self": {"href": "http://broker/pacts/1", "name": "Pact between web (2.0.0) and api"}}
+		}]}}`)
+	}))
+	defer s.Close()
+
+	metadata, err := BrokerPactsForVerification(s.URL, "api", []string{"web"}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata) != 1 {
+		t.Fatalf("expected the metadata of 1 pact, got %d", len(metadata))
+	}
+	m := metadata[0]
+	if m.Consumer != "web" || m.ConsumerVersion != "2.0.0" || !m.Pending || m.ShortDescription != "latest from web" ||
+		len(m.Notices) != 1 || m.Notices[0].Text != "This pact is pending" {
+		t.Errorf("unexpected metadata %+v", m)
+	}
+	if fmt.Sprint(body["consumerVersionSelectors"]) != "[map[consumer:web latest:true]]" || body["includePendingStatus"] != true {
+		t.Errorf("unexpected request body %v", body)
+	}
+}
@@ -13,9 +13,9 @@ import (
 const pactSpecificationVersion = "1.1.0"
 
 var (
-	errEmptyProvider    = errors.New("Pactfile is invalid, provider name should not be empty.")
-	errEmptyConsumer    = errors.New("Pactfile is invalid, consumer name should not be empty.")
-	errIncompatiblePact = fmt.Errorf("Incompatible pact specification! We only support version %s.", pactSpecificationVersion)
+	errEmptyProvider       = errors.New("Pactfile is invalid, provider name should not be empty.")
+	errEmptyConsumer       = errors.New("Pactfile is invalid, consumer name should not be empty.")
+	errIncompatiblePactMsg = "Incompatible pact specification '%s'! We only support versions up to %s."
 )
 
 type Participant struct {
@@ -31,6 +31,9 @@ type PactFile struct {
 	Provider     *Participant            `json:"provider"`
 	Interactions []*consumer.Interaction `json:"interactions"`
 	Metadata     *metadata               `json:"metaData"`
+
+	//PublishResultsURL is the pb:publish-verification-results relation of a pact read from the pact broker
+	PublishResultsURL string `json:"-"`
 }
 
 func NewPactFile(consumer string, provider string, interactions []*consumer.Interaction) *PactFile {
@@ -52,6 +55,14 @@ func (p *PactFile) FileName() string {
 	return fmt.Sprintf("%s-%s.json", consumer, provider)
 }
 
+//SpecVersion returns the pact specification version of the pact, empty when the pact does not record it
+func (p *PactFile) SpecVersion() string {
+	if p.Metadata == nil {
+		return ""
+	}
+	return p.Metadata.PactSpecificationVersion
+}
+
 func (p *PactFile) Validate() error {
 	if p.Provider == nil || p.Provider.Name == "" {
 		return errEmptyProvider
@@ -61,19 +72,14 @@ func (p *PactFile) Validate() error {
 		return errEmptyConsumer
 	}
 
-	psv, err := version.NewVersion(pactSpecificationVersion)
-	if err != nil {
-		return err
-	}
-
-	fpsv, err := version.NewVersion(p.Metadata.PactSpecificationVersion)
+	fpsv, err := version.NewVersion(p.SpecVersion())
 	if err != nil {
 		return err
 	}
 
-	//should be backwards compatible with version 1.0.0
-	if fpsv.GreaterThan(psv) {
-		return errIncompatiblePact
+	//every major version up to the newest has a loader
+	if _, ok := specLoaders[fpsv.Segments()[0]]; !ok {
+		return fmt.Errorf(errIncompatiblePactMsg, p.SpecVersion(), maxPactSpecificationVersion)
 	}
 
 	return nil
@@ -1,14 +1,46 @@
 package io
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
+	goio "io"
 	"io/ioutil"
+	"sync"
 )
 
+var errEmptyPactStream = errors.New("The pact read from the stream is empty, please check the pact was piped in.")
+
 type PactReader interface {
 	Read() (*PactFile, error)
 }
 
+//pactStreamReader reads the pact from a stream like stdin, the stream is read once and the pact is decoded again
+//from its contents by every Read
+type pactStreamReader struct {
+	once sync.Once
+	r    goio.Reader
+	data []byte
+	err  error
+}
+
+//NewPactStreamReader creates a reader of the pact read from the stream until EOF
+func NewPactStreamReader(r goio.Reader) PactReader {
+	return &pactStreamReader{r: r}
+}
+
+func (r *pactStreamReader) Read() (*PactFile, error) {
+	r.once.Do(func() {
+		r.data, r.err = ioutil.ReadAll(r.r)
+		if r.err == nil && len(bytes.TrimSpace(r.data)) == 0 {
+			r.err = errEmptyPactStream
+		}
+	})
+	if r.err != nil {
+		return nil, r.err
+	}
+	return readPact(r.data)
+}
+
 type pactFileReader struct {
 	filePath string
 }
@@ -17,14 +49,25 @@ func NewPactFileReader(filePath string) PactReader {
 	return &pactFileReader{filePath: filePath}
 }
 
-func (r *pactFileReader) Read() (f *PactFile, err error) {
-	var b []byte
+func (r *pactFileReader) Read() (*PactFile, error) {
+	b, err := ioutil.ReadFile(r.filePath)
+	if err != nil {
+		return nil, err
+	}
+	return readPact(b)
+}
+
+//readPact decodes the pact document into a pact file with the locations of its interactions
+func readPact(b []byte) (f *PactFile, err error) {
 	f = &PactFile{}
-	if b, err = ioutil.ReadFile(r.filePath); err != nil {
+	if b, err = toUTF8(b, "utf-8"); err != nil {
 		return nil, err
 	}
 
-	if err = json.Unmarshal(b, f); err != nil {
+	if err = decodePact(b, f); err != nil {
+		return nil, err
+	}
+	if err = locateInteractions(b, f); err != nil {
 		return nil, err
 	}
 	return f, nil
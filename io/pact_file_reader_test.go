@@ -1,6 +1,12 @@
 package io
 
-import "testing"
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+)
 
 func Test_FileReader_ValidFile_ShouldReturnPactFile(t *testing.T) {
 	path := "../pact_examples/consumer-provider.json"
@@ -19,3 +25,68 @@ func Test_FileReader_BadFilePath_ShouldReturnError(t *testing.T) {
 		t.Error("expected error")
 	}
 }
+
+func Test_FileReader_FileWithBOM_ShouldReturnPactFile(t *testing.T) {
+	path := "./pactWithBOM.json"
+	r := NewPactFileReader(path)
+
+	if f, err := r.Read(); err != nil {
+		t.Error(err)
+	} else if err := f.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_FileReader_ShouldLocateInteractions(t *testing.T) {
+	r := NewPactFileReader("../pact_examples/chrome_browser-go_api.json")
+
+	f, err := r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range []int{9, 29} {
+		l := f.Interactions[i].Location
+		if l == nil || l.Index != i || l.Line != line {
+			t.Errorf("expected interaction %d on line %d, got %v", i, line, l)
+		}
+	}
+	if s := f.Interactions[1].Location.String(); s != "interaction #2 at interactions[1], line 29" {
+		t.Errorf("unexpected location %s", s)
+	}
+}
+
+func Test_LocateInteractions_RecordsByteOffsets(t *testing.T) {
+	b := []byte(`{"consumer": {"name": "c"}, "interactions": [ {"description": "a"},{"description": "b"} ], "provider": {"name": "p"}}`)
+	f := &PactFile{Interactions: []*consumer.Interaction{&consumer.Interaction{}, &consumer.Interaction{}}}
+
+	if err := locateInteractions(b, f); err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range []string{"a", "b"} {
+		expected := int64(bytes.Index(b, []byte(`{"description": "`+d+`"}`)))
+		if l := f.Interactions[i].Location; l == nil || l.Offset != expected || l.Line != 1 {
+			t.Errorf("expected interaction %d at offset %d, got %v", i, expected, l)
+		}
+	}
+}
+
+func Test_StreamReader_ReadsStreamOnce(t *testing.T) {
+	b, err := ioutil.ReadFile("../pact_examples/chrome_browser-go_api.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewPactStreamReader(bytes.NewReader(b))
+	for n := 0; n < 2; n++ {
+		if f, err := r.Read(); err != nil {
+			t.Fatal(err)
+		} else if len(f.Interactions) != 2 {
+			t.Errorf("expected the interactions of the stream, got %d", len(f.Interactions))
+		}
+	}
+}
+
+func Test_StreamReader_EmptyStream_ShouldReturnError(t *testing.T) {
+	if _, err := NewPactStreamReader(bytes.NewReader(nil)).Read(); err != errEmptyPactStream {
+		t.Errorf("expected the empty stream error, got %v", err)
+	}
+}
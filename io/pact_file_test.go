@@ -1,6 +1,11 @@
 package io
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SEEK-Jobs/pact-go/matchers"
+)
 
 func Test_Validate_ValidFile(t *testing.T) {
 	path := "../pact_examples/consumer-provider.json"
@@ -26,10 +31,22 @@ func Test_Validate_MissingConsumer(t *testing.T) {
 }
 
 func Test_Validate_InvalidSpec(t *testing.T) {
-	path := "./pactWrongSpec.json"
-	p := readPactFile(t, path)
+	p := NewPactFile("consumer", "provider", nil)
+	p.Metadata.PactSpecificationVersion = "5.0.0"
 
-	expectError(t, p.Validate(), errIncompatiblePact)
+	expected := fmt.Sprintf(errIncompatiblePactMsg, "5.0.0", maxPactSpecificationVersion)
+	if err := p.Validate(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Read_InvalidSpec_NamesTheDetectedVersion(t *testing.T) {
+	_, err := NewPactFileReader("./pactWrongSpec.json").Read()
+
+	expected := fmt.Sprintf(errIncompatiblePactMsg, "5.0.0", maxPactSpecificationVersion)
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
 }
 
 func readPactFile(t *testing.T, path string) *PactFile {
@@ -48,3 +65,97 @@ func expectError(t *testing.T, actual, expected error) {
 		t.Errorf("got %q error, expected %q", actual, expected)
 	}
 }
+
+func Test_Read_ConvertsV2MatchingRules(t *testing.T) {
+	p := readPactFile(t, "../pact_examples/spec/android_app-go_api-v2.json")
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if p.SpecVersion() != "2.0.0" {
+		t.Errorf("expected the v2 specification, got %s", p.SpecVersion())
+	}
+
+	body := p.Interactions[0].Response.MatchingRules[matchers.BodyCategory]
+	if rs := body["$.firstName"]; rs == nil || len(rs.Matchers) != 1 || rs.Matchers[0].Match != "type" {
+		t.Errorf("expected the type rule of $.body.firstName in the body category, got %v", body)
+	}
+	if rs := body["$.lastName"]; rs == nil || rs.Matchers[0].Match != "regex" || rs.Matchers[0].Regex != "^[A-Z][a-z]+$" {
+		t.Errorf("expected the implied regex rule of $.body.lastName in the body category, got %v", body)
+	}
+}
+
+func Test_Read_V3SpecVersion(t *testing.T) {
+	p := readPactFile(t, "../pact_examples/spec/chrome_browser-go_api-v3.json")
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if p.SpecVersion() != "3.0.0" {
+		t.Errorf("expected the v3 specification, got %s", p.SpecVersion())
+	}
+	if body := p.Interactions[0].Response.MatchingRules[matchers.BodyCategory]; len(body) != 2 {
+		t.Errorf("expected the body rules, got %v", body)
+	}
+}
+
+func Test_Read_LoadsEverySpecVersion(t *testing.T) {
+	fixtures := map[string]string{
+		"1.0.0": "../pact_examples/spec/iphone_app-go_api-v1.json",
+		"2.0.0": "../pact_examples/spec/android_app-go_api-v2.json",
+		"3.0.0": "../pact_examples/spec/chrome_browser-go_api-v3.json",
+		"4.0":   "../pact_examples/spec/web_app-go_api-v4.json",
+	}
+	for spec, path := range fixtures {
+		p := readPactFile(t, path)
+		if err := p.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if p.SpecVersion() != spec {
+			t.Errorf("expected the %s specification, got %s", spec, p.SpecVersion())
+		}
+
+		i := p.Interactions[0]
+		if i.State != "there is a user with id {23}" || i.Request.Path != "/user" || i.Request.Query != "id=23" {
+			t.Errorf("expected the request of the %s pact, got %s %s?%s", spec, i.State, i.Request.Path, i.Request.Query)
+		}
+		body, _ := i.Response.GetBody().(map[string]interface{})
+		if body["firstName"] != "John" || i.Response.Headers.Get("Content-Type") != "application/json" {
+			t.Errorf("expected the json response of the %s pact, got %v %v", spec, i.Response.Headers, body)
+		}
+		if spec == "4.0" && fmt.Sprint(i.StateParams["id"]) != "23" {
+			t.Errorf("expected the params of the provider state of the %s pact, got %v", spec, i.StateParams)
+		}
+		if rs := i.Response.MatchingRules[matchers.BodyCategory]["$.firstName"]; spec != "1.0.0" && (rs == nil || rs.Matchers[0].Match != "type") {
+			t.Errorf("expected the type rule of $.firstName of the %s pact, got %v", spec, i.Response.MatchingRules)
+		}
+
+		b, err := p.ToJson()
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := readPact(b)
+		if err != nil {
+			t.Fatalf("expected the %s pact to be read again, got %s", spec, err)
+		}
+		if b2, _ := again.ToJson(); string(b2) != string(b) {
+			t.Errorf("expected the %s pact to round trip, got\n%s\ninstead of\n%s", spec, b2, b)
+		}
+	}
+}
+
+func Test_Read_V4SkipsTheMessageInteractions(t *testing.T) {
+	p := readPactFile(t, "../pact_examples/spec/web_app-go_api-v4.json")
+
+	if len(p.Interactions) != 2 {
+		t.Fatalf("expected the 2 http interactions, got %d", len(p.Interactions))
+	}
+	if l := p.Interactions[0].Location; l == nil || l.Index != 1 {
+		t.Errorf("expected the location of the second interaction of the pact, got %v", l)
+	}
+	post := p.Interactions[1]
+	if post.Request.GetBody() != "hello" || post.Request.Headers.Get("Content-Type") != "text/plain" {
+		t.Errorf("expected the text body and its content type, got %v %v", post.Request.Headers, post.Request.GetBody())
+	}
+	if accept := p.Interactions[0].Request.Headers.Get("Accept"); accept != "application/json" {
+		t.Errorf("expected the header array to be joined, got %s", accept)
+	}
+}
@@ -0,0 +1,80 @@
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+)
+
+var errUnexpectedPactTokenMsg = "unexpected token %v whilst locating the pact interactions"
+
+//locateInteractions records the location of every interaction within the pact document
+func locateInteractions(b []byte, f *PactFile) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key, _ := t.(string); key != "interactions" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		//the interactions which are not verified, e.g. the v4 messages, are not loaded
+		for i, n := 0, 0; dec.More(); i++ {
+			offset := valueStart(b, dec.InputOffset())
+			var skip struct {
+				Type string `json:"type"`
+			}
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			if !isHTTPInteraction(skip.Type) {
+				continue
+			}
+			if n < len(f.Interactions) && f.Interactions[n] != nil {
+				f.Interactions[n].Location = &consumer.Location{Index: i, Offset: offset, Line: bytes.Count(b[:offset], []byte("\n")) + 1}
+			}
+			n++
+		}
+		return nil
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != d {
+		return fmt.Errorf(errUnexpectedPactTokenMsg, t)
+	}
+	return nil
+}
+
+//valueStart skips the whitespace and separators preceding the next value
+func valueStart(b []byte, offset int64) int64 {
+	for offset < int64(len(b)) {
+		switch b[offset] {
+		case ' ', '\t', '\r', '\n', ',':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
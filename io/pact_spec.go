@@ -0,0 +1,312 @@
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/provider"
+	version "github.com/hashicorp/go-version"
+)
+
+//maxPactSpecificationVersion is the newest pact specification which can be verified
+const maxPactSpecificationVersion = "4.0.0"
+
+const (
+	//v4HTTPInteraction is the type of the v4 interactions which are verified, the message interactions are skipped
+	v4HTTPInteraction = "Synchronous/HTTP"
+	stateKey          = "provider_state"
+	stateParamsKey    = "provider_state_params"
+)
+
+//specLoader rewrites the decoded pact of a specification version to the pact model, which is the v3 pact whose
+//interactions have a single provider_state
+type specLoader func(pact map[string]interface{})
+
+//specLoaders are the loaders by the major version of the specification, v1 and v3 pacts only differ from the model
+//by how they record the provider states
+var specLoaders = map[int]specLoader{1: loadStates, 2: loadV2, 3: loadStates, 4: loadV4}
+
+//specMetadata holds the specification version of a pact, which v3 pacts record in pactSpecification and
+//some v2 pacts in pact-specification
+type specMetadata struct {
+	Metadata struct {
+		PactSpecificationVersion string `json:"pactSpecificationVersion"`
+		PactSpecification        struct {
+			Version string `json:"version"`
+		} `json:"pactSpecification"`
+		PactSpecificationV2 struct {
+			Version string `json:"version"`
+		} `json:"pact-specification"`
+	} `json:"metadata"`
+}
+
+func (m *specMetadata) version() string {
+	if v := m.Metadata.PactSpecification.Version; v != "" {
+		return v
+	} else if v := m.Metadata.PactSpecificationV2.Version; v != "" {
+		return v
+	}
+	return m.Metadata.PactSpecificationVersion
+}
+
+//decodePact decodes the pact with the loader of its specification version, which rewrites it to the pact model
+//before the interactions are decoded
+func decodePact(b []byte, f *PactFile) error {
+	var m specMetadata
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	spec := m.version()
+	load, err := loaderOf(spec)
+	if err != nil {
+		return err
+	}
+	var pact map[string]interface{}
+	if err := provider.DecodeJSON(b, &pact); err != nil {
+		return err
+	}
+	load(pact)
+	if b, err = json.Marshal(pact); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, f); err != nil {
+		return err
+	}
+	if f.Metadata == nil {
+		f.Metadata = &metadata{}
+	}
+	f.Metadata.PactSpecificationVersion = spec
+
+	var links brokerPactLinks
+	if err := json.Unmarshal(b, &links); err != nil {
+		return err
+	}
+	f.PublishResultsURL = links.Links.PublishResults.Href
+	return nil
+}
+
+//loaderOf returns the loader of the specification version, a pact which does not record its version is loaded
+//as a v1 pact
+func loaderOf(spec string) (specLoader, error) {
+	if spec == "" {
+		return loadStates, nil
+	}
+	if v, err := version.NewVersion(spec); err == nil {
+		if load, ok := specLoaders[v.Segments()[0]]; ok {
+			return load, nil
+		}
+	}
+	return nil, fmt.Errorf(errIncompatiblePactMsg, spec, maxPactSpecificationVersion)
+}
+
+//isHTTPInteraction returns true for the interactions of the v1 to v3 pacts, which have no type, and the v4 http
+//interactions
+func isHTTPInteraction(typ string) bool {
+	return typ == "" || typ == v4HTTPInteraction
+}
+
+//interactionsOf returns the interactions of the decoded pact
+func interactionsOf(pact map[string]interface{}) []map[string]interface{} {
+	list, _ := pact["interactions"].([]interface{})
+	interactions := make([]map[string]interface{}, 0, len(list))
+	for _, i := range list {
+		if interaction, ok := i.(map[string]interface{}); ok {
+			interactions = append(interactions, interaction)
+		}
+	}
+	return interactions
+}
+
+//partsOf returns the request and response of the decoded interaction
+func partsOf(interaction map[string]interface{}) []map[string]interface{} {
+	var parts []map[string]interface{}
+	for _, name := range []string{"request", "response"} {
+		if p, ok := interaction[name].(map[string]interface{}); ok {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func loadStates(pact map[string]interface{}) {
+	for _, i := range interactionsOf(pact) {
+		singleState(i)
+	}
+}
+
+//loadV2 converts the matching rules of the v2 interactions, keyed by a path into the request or response
+//e.g. $.body.id, to v3 matching rules keyed by category and then path e.g. body and $.id
+func loadV2(pact map[string]interface{}) {
+	for _, i := range interactionsOf(pact) {
+		singleState(i)
+		for _, p := range partsOf(i) {
+			if rules, ok := p["matchingRules"].(map[string]interface{}); ok {
+				p["matchingRules"] = v3MatchingRules(rules)
+			}
+		}
+	}
+}
+
+//loadV4 keeps the http interactions of the v4 pact, whose header values are arrays and whose bodies record
+//their content type
+func loadV4(pact map[string]interface{}) {
+	if _, ok := pact["interactions"]; !ok {
+		return
+	}
+	interactions := []interface{}{}
+	for _, i := range interactionsOf(pact) {
+		if typ, _ := i["type"].(string); !isHTTPInteraction(typ) {
+			continue
+		}
+		singleState(i)
+		for _, p := range partsOf(i) {
+			joinHeaderValues(p)
+			unwrapV4Body(p)
+		}
+		interactions = append(interactions, i)
+	}
+	pact["interactions"] = interactions
+}
+
+//singleState records the provider state of the interaction as its provider_state, and the params of a v3 state as
+//its provider_state_params. The verifier sets up a single state per interaction, so only the first of the v3
+//provider states is kept
+func singleState(interaction map[string]interface{}) {
+	if _, ok := interaction[stateKey]; ok {
+		return
+	}
+	if state, ok := interaction["providerState"].(string); ok {
+		interaction[stateKey] = state
+		return
+	}
+	states, _ := interaction["providerStates"].([]interface{})
+	if len(states) == 0 {
+		return
+	}
+	if s, ok := states[0].(map[string]interface{}); ok {
+		if name, ok := s["name"].(string); ok {
+			interaction[stateKey] = name
+		}
+		if params, ok := s["params"].(map[string]interface{}); ok && len(params) > 0 {
+			interaction[stateParamsKey] = params
+		}
+	}
+}
+
+//joinHeaderValues joins the array values of the headers, as the headers of the pact model are strings
+func joinHeaderValues(part map[string]interface{}) {
+	headers, _ := part["headers"].(map[string]interface{})
+	for name, val := range headers {
+		list, ok := val.([]interface{})
+		if !ok {
+			continue
+		}
+		values := make([]string, 0, len(list))
+		for _, v := range list {
+			values = append(values, fmt.Sprint(v))
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+}
+
+//unwrapV4Body replaces the v4 body by its content, its content type is recorded in the headers when they have none.
+//A base64 encoded body is kept for the binary content to decode
+func unwrapV4Body(part map[string]interface{}) {
+	body, ok := part["body"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	content, ok := body["content"]
+	if !ok {
+		return
+	}
+	if encoded, ok := body["encoded"].(string); ok {
+		s, isString := content.(string)
+		if !strings.EqualFold(encoded, "json") || !isString {
+			return
+		}
+		if err := provider.DecodeJSON([]byte(s), &content); err != nil {
+			return
+		}
+	}
+
+	if contentType, ok := body["contentType"].(string); ok {
+		headers, _ := part["headers"].(map[string]interface{})
+		if headers == nil {
+			headers = make(map[string]interface{})
+			part["headers"] = headers
+		}
+		hasType := false
+		for name := range headers {
+			hasType = hasType || strings.EqualFold(name, "Content-Type")
+		}
+		if !hasType {
+			headers["Content-Type"] = contentType
+		}
+	}
+	part["body"] = content
+}
+
+func v3MatchingRules(v2 map[string]interface{}) map[string]interface{} {
+	v3 := make(map[string]interface{})
+	for expr, rule := range v2 {
+		//the rules of a pact written by the verifier are already categorised
+		if !strings.HasPrefix(expr, "$") {
+			v3[expr] = rule
+			continue
+		}
+		category, path := splitV2Path(expr)
+		if category == "" {
+			continue
+		}
+		rules, ok := v3[category].(map[string]interface{})
+		if !ok {
+			rules = make(map[string]interface{})
+			v3[category] = rules
+		}
+		rules[path] = map[string]interface{}{"matchers": []interface{}{v3Matcher(rule)}}
+	}
+	return v3
+}
+
+//splitV2Path splits the v2 path into its category and the path within the category, the body path keeps
+//its json path root e.g. $.body.items[0] is $.items[0] of the body category
+func splitV2Path(expr string) (string, string) {
+	rest := strings.TrimPrefix(expr, "$.")
+	for _, c := range []struct{ prefix, category string }{{"body", "body"}, {"headers", "header"}, {"query", "query"}, {"path", "path"}} {
+		if !strings.HasPrefix(rest, c.prefix) {
+			continue
+		}
+		path := strings.TrimPrefix(rest, c.prefix)
+		if c.category == "body" {
+			return c.category, "$" + path
+		}
+		return c.category, strings.TrimPrefix(path, ".")
+	}
+	return "", ""
+}
+
+//v3Matcher returns the matcher of the v2 rule, which may leave out the match when it is implied by the
+//regex or a minimum or maximum
+func v3Matcher(rule interface{}) interface{} {
+	r, ok := rule.(map[string]interface{})
+	if !ok {
+		return rule
+	}
+	if _, ok := r["match"]; ok {
+		return r
+	}
+	m := make(map[string]interface{}, len(r)+1)
+	for k, v := range r {
+		m[k] = v
+	}
+	if _, ok := r["regex"]; ok {
+		m["match"] = "regex"
+	} else {
+		m["match"] = "type"
+	}
+	return m
+}
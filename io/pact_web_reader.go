@@ -1,16 +1,38 @@
 package io
 
 import (
-	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/util"
 )
 
+//NotFoundError is returned when there is no pact at the requested uri
+type NotFoundError struct {
+	Uri string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("failed to get the pact file from %s, the pact was not found", e.Uri)
+}
+
 type pactWebReader struct {
-	url      string
-	username string
-	password string
+	url  string
+	opts *WebOptions
+}
+
+//WebOptions configures the requests to a web uri or the pact broker
+type WebOptions struct {
+	Username string
+	Password string
+	//Token is sent as a bearer token when there is no username and password
+	Token string
+	//UserAgent is the User-Agent header of the requests, util.DefaultUserAgent is sent when it is empty
+	UserAgent string
+	//Retry is the policy used to retry the failed or throttled requests
+	Retry *util.RetryPolicy
 }
 
 func IsWebUri(url string) bool {
@@ -18,35 +40,72 @@ func IsWebUri(url string) bool {
 }
 
 func NewPactWebReader(url, username, password string) PactReader {
-	return &pactWebReader{url: url, username: username, password: password}
+	return NewRetryingPactWebReader(url, username, password, nil)
 }
 
-func (p *pactWebReader) Read() (*PactFile, error) {
+//NewRetryingPactWebReader creates a web reader which retries failed or throttled requests using the policy
+func NewRetryingPactWebReader(url, username, password string, retry *util.RetryPolicy) PactReader {
+	return NewPactWebReaderWithOptions(url, &WebOptions{Username: username, Password: password, Retry: retry})
+}
+
+//NewPactWebReaderWithOptions creates a web reader sending its requests as configured by the options
+func NewPactWebReaderWithOptions(url string, opts *WebOptions) PactReader {
+	if opts == nil {
+		opts = &WebOptions{}
+	}
+	return &pactWebReader{url: url, opts: opts}
+}
+
+func (p *pactWebReader) newRequest() (*http.Request, error) {
 	req, err := http.NewRequest("GET", p.url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Add("Accept", "application/json")
-	if p.username != "" && p.password != "" {
-		req.SetBasicAuth(p.username, p.password)
+	p.opts.setHeaders(req)
+	return req, nil
+}
+
+//setHeaders sets the user agent and the basic auth credentials of the request
+func (o *WebOptions) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", util.UserAgent(o.UserAgent))
+	if o.Username != "" && o.Password != "" {
+		req.SetBasicAuth(o.Username, o.Password)
+	} else if o.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+o.Token)
 	}
+}
 
+func (p *pactWebReader) Read() (*PactFile, error) {
 	c := &http.Client{}
-	resp, err := c.Do(req)
+	resp, err := p.opts.Retry.Do(c, p.newRequest)
 	if err != nil {
 		return nil, err
 	} else if resp != nil {
 		defer resp.Body.Close()
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &NotFoundError{Uri: p.url}
+	} else if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to get the pact file from %s, the response came back with %d status code", p.url, resp.StatusCode)
 	}
 
-	decoder := json.NewDecoder(resp.Body)
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if b, err = toUTF8(b, charsetFromContentType(resp.Header.Get("Content-Type"))); err != nil {
+		return nil, err
+	}
+
 	var f PactFile
-	if err := decoder.Decode(&f); err != nil {
+	if err := decodePact(b, &f); err != nil {
+		return nil, err
+	}
+	if err := locateInteractions(b, &f); err != nil {
 		return nil, err
 	}
 
@@ -1,10 +1,13 @@
 package io
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/SEEK-Jobs/pact-go/util"
 )
 
 func Test_WebReader_ReadsPactWithoutAuth(t *testing.T) {
@@ -80,3 +83,69 @@ func Test_WebReader_ReturnsErrorWhenWebRequestFails(t *testing.T) {
 		t.Error("expected error")
 	}
 }
+
+func Test_WebReader_ReturnsNotFoundError(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+
+	r := NewPactWebReader(s.URL, "", "")
+	if _, err := r.Read(); err == nil {
+		t.Error("expected error")
+	} else if nf, ok := err.(*NotFoundError); !ok {
+		t.Errorf("expected not found error, got %s", err)
+	} else if nf.Uri != s.URL {
+		t.Errorf("expected uri %s, got %s", s.URL, nf.Uri)
+	}
+}
+
+func Test_WebReader_TranscodesCharset(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=iso-8859-1")
+		w.Write([]byte("{\"consumer\":{\"name\":\"caf\xe9\"},\"provider\":{\"name\":\"provider\"},\"interactions\":[],\"metaData\":{\"pactSpecificationVersion\":\"1.1.0\"}}"))
+	}))
+	defer s.Close()
+
+	r := NewPactWebReader(s.URL, "", "")
+	if f, err := r.Read(); err != nil {
+		t.Error(err)
+	} else if f.Consumer.Name != "café" {
+		t.Errorf("expected consumer name café, got %s", f.Consumer.Name)
+	}
+}
+
+func Test_WebReader_ReturnsErrorForUnsupportedCharset(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=ebcdic")
+		w.Write([]byte("{}"))
+	}))
+	defer s.Close()
+
+	r := NewPactWebReader(s.URL, "", "")
+	expErrMsg := fmt.Sprintf(errUnsupportedCharsetMsg, "ebcdic")
+	if _, err := r.Read(); err == nil {
+		t.Errorf("expected %s", expErrMsg)
+	} else if err.Error() != expErrMsg {
+		t.Errorf("expected %s, got %s", expErrMsg, err)
+	}
+}
+
+func Test_WebReader_SendsUserAgent(t *testing.T) {
+	var agents []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		b, _ := ioutil.ReadFile("../pact_examples/consumer-provider.json")
+		w.Write(b)
+	}))
+	defer s.Close()
+
+	if _, err := NewPactWebReader(s.URL, "", "").Read(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPactWebReaderWithOptions(s.URL, &WebOptions{UserAgent: "ci-verifier/2"}).Read(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(agents) != 2 || agents[0] != util.DefaultUserAgent || agents[1] != "ci-verifier/2" {
+		t.Errorf("expected the default and the configured user agents, got %v", agents)
+	}
+}
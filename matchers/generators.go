@@ -0,0 +1,269 @@
+package matchers
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	//QueryCategory the category of the generators applied to the query parameters
+	QueryCategory = "query"
+	//PathCategory the category of the generator applied to the request path, keyed by the root path $
+	PathCategory = "path"
+
+	//RandomUUIDGenerator generates a random version 4 uuid
+	RandomUUIDGenerator = "RandomUuid"
+	//DateTimeGenerator generates the current date and time in the format of the generator
+	DateTimeGenerator = "DateTime"
+	//ProviderStateGenerator generates the expression of the generator from the values of the provider state setup
+	ProviderStateGenerator = "ProviderState"
+
+	//uuidGenerator is the name other pact implementations write the RandomUuid generator with
+	uuidGenerator         = "Uuid"
+	defaultDateTimeFormat = "yyyy-MM-dd'T'HH:mm:ssXXX"
+	rootPath              = "$"
+)
+
+var (
+	errUnknownGeneratorMsg = "Unknown generator '%s'."
+	errNoStateValueMsg     = "The provider state setup returned no value '%s' for the generator expression '%s'."
+
+	stateExpression = regexp.MustCompile(`\$\{([^}]+)\}`)
+)
+
+//Generator produces a value of the request at verification time e.g. {"type": "RandomUuid"}
+type Generator struct {
+	Type string `json:"type"`
+	//Format of the DateTime generator as a java date pattern e.g. yyyy-MM-dd'T'HH:mm:ss
+	Format string `json:"format,omitempty"`
+	//Expression of the ProviderState generator, each ${name} is replaced by the value of the state setup
+	Expression string `json:"expression,omitempty"`
+}
+
+//Generators are the generators of a request by category, then by body path, header or query parameter name
+type Generators map[string]map[string]*Generator
+
+//Validate returns an error for an unknown generator type
+func (g *Generator) Validate() error {
+	switch g.Type {
+	case RandomUUIDGenerator, uuidGenerator, DateTimeGenerator, ProviderStateGenerator:
+		return nil
+	}
+	return fmt.Errorf(errUnknownGeneratorMsg, g.Type)
+}
+
+//Generate returns the generated value, the values are the ones returned by the provider state setup
+func (g *Generator) Generate(values map[string]interface{}) (interface{}, error) {
+	switch g.Type {
+	case RandomUUIDGenerator, uuidGenerator:
+		return randomUUID(), nil
+	case DateTimeGenerator:
+		format := g.Format
+		if format == "" {
+			format = defaultDateTimeFormat
+		}
+		return time.Now().Format(javaDateLayout(format)), nil
+	case ProviderStateGenerator:
+		return expandExpression(g.Expression, values)
+	}
+	return nil, fmt.Errorf(errUnknownGeneratorMsg, g.Type)
+}
+
+//UnmarshalJSON reads the single generator of the path category into the root path
+func (g *Generators) UnmarshalJSON(b []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	gens := make(Generators, len(obj))
+	for category, raw := range obj {
+		if category == PathCategory {
+			var p Generator
+			if err := json.Unmarshal(raw, &p); err != nil {
+				return err
+			}
+			gens[category] = map[string]*Generator{rootPath: &p}
+			continue
+		}
+		var c map[string]*Generator
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return err
+		}
+		gens[category] = c
+	}
+	for _, c := range gens {
+		for _, gen := range c {
+			if err := gen.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	*g = gens
+	return nil
+}
+
+//MarshalJSON writes the generator of the path category without its root path
+func (g Generators) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]interface{}, len(g))
+	for category, c := range g {
+		if p, ok := c[rootPath]; ok && category == PathCategory {
+			obj[category] = p
+		} else {
+			obj[category] = c
+		}
+	}
+	return json.Marshal(obj)
+}
+
+//Path returns the generator of the request path, nil when there is none
+func (g Generators) Path() *Generator {
+	return g[PathCategory][rootPath]
+}
+
+//ApplyToBody replaces the values of the json body found at the paths of the body generators, the body is changed
+//in place. A path matching no value generates nothing
+func (g Generators) ApplyToBody(body interface{}, values map[string]interface{}) (interface{}, error) {
+	for expr, gen := range g[BodyCategory] {
+		tokens, err := compilePath(expr)
+		if err != nil {
+			return nil, err
+		}
+		if body, err = generateAt(body, tokens[1:], gen, values); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+func generateAt(v interface{}, tokens []*pathToken, gen *Generator, values map[string]interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return gen.Generate(values)
+	}
+	t, rest := tokens[0], tokens[1:]
+	var err error
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for k, e := range c {
+			if t.typ == tokenStar || t.typ == tokenField && t.name == k {
+				if c[k], err = generateAt(e, rest, gen, values); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case []interface{}:
+		for n, e := range c {
+			if t.typ == tokenStar || t.typ == tokenIndex && t.index == n {
+				if c[n], err = generateAt(e, rest, gen, values); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return v, nil
+}
+
+//expandExpression replaces each ${name} of the expression by its value, an expression of a single ${name} keeps
+//the type of the value e.g. a number id
+func expandExpression(expr string, values map[string]interface{}) (interface{}, error) {
+	var missing string
+	if m := stateExpression.FindStringSubmatch(expr); m != nil && m[0] == expr {
+		if v, ok := values[m[1]]; ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf(errNoStateValueMsg, m[1], expr)
+	}
+	s := stateExpression.ReplaceAllStringFunc(expr, func(e string) string {
+		name := e[2 : len(e)-1]
+		v, ok := values[name]
+		if !ok {
+			missing = name
+			return e
+		}
+		return fmt.Sprint(v)
+	})
+	if missing != "" {
+		return nil, fmt.Errorf(errNoStateValueMsg, missing, expr)
+	}
+	return s, nil
+}
+
+//javaDateLayouts are the go layouts of the letters of a java date pattern by the number of times they repeat,
+//the last layout is used for longer repeats
+var javaDateLayouts = map[rune][]string{
+	'y': {"2006", "06", "2006", "2006"},
+	'M': {"1", "01", "Jan", "January"},
+	'd': {"2", "02"},
+	'E': {"Mon", "Mon", "Mon", "Monday"},
+	'H': {"15", "15"},
+	'h': {"3", "03"},
+	'm': {"4", "04"},
+	's': {"5", "05"},
+	'a': {"PM"},
+	'X': {"Z07", "Z0700", "Z07:00"},
+	'Z': {"-0700"},
+	'z': {"MST"},
+}
+
+//javaDateLayout converts a java date pattern to a go time layout, text between single quotes is kept as it is
+func javaDateLayout(pattern string) string {
+	var layout strings.Builder
+	runes := []rune(pattern)
+	for n := 0; n < len(runes); {
+		r := runes[n]
+		if r == '\'' {
+			n = quotedText(runes, n+1, &layout)
+			continue
+		}
+
+		count := 1
+		for n+count < len(runes) && runes[n+count] == r {
+			count++
+		}
+		if r == 'S' {
+			layout.WriteString(strings.Repeat("0", count))
+		} else if layouts, ok := javaDateLayouts[r]; ok {
+			if count > len(layouts) {
+				count = len(layouts)
+			}
+			layout.WriteString(layouts[count-1])
+		} else {
+			layout.WriteString(string(runes[n : n+count]))
+		}
+		n += count
+	}
+	return layout.String()
+}
+
+//quotedText writes the text quoted from the start up to the closing quote, two single quotes are a quote.
+//It returns the position after the closing quote
+func quotedText(runes []rune, start int, layout *strings.Builder) int {
+	if start < len(runes) && runes[start] == '\'' {
+		layout.WriteRune('\'')
+		return start + 1
+	}
+	n := start
+	for ; n < len(runes); n++ {
+		if runes[n] != '\'' {
+			layout.WriteRune(runes[n])
+		} else if n+1 < len(runes) && runes[n+1] == '\'' {
+			layout.WriteRune('\'')
+			n++
+		} else {
+			return n + 1
+		}
+	}
+	return n
+}
+
+//randomUUID generates a random version 4 uuid
+func randomUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,108 @@
+package matchers
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func Test_Generator_RandomUuid(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[interface{}]bool)
+	for _, typ := range []string{RandomUUIDGenerator, uuidGenerator} {
+		v, err := (&Generator{Type: typ}).Generate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, _ := v.(string); !uuid.MatchString(s) {
+			t.Errorf("expected a version 4 uuid, got %v", v)
+		}
+		if seen[v] {
+			t.Errorf("expected a new uuid, got %v again", v)
+		}
+		seen[v] = true
+	}
+}
+
+func Test_Generator_DateTime(t *testing.T) {
+	cases := map[string]string{
+		"":                            time.RFC3339,
+		"yyyy-MM-dd'T'HH:mm:ss.SSS":   "2006-01-02T15:04:05.000",
+		"EEE, dd MMM yyyy HH:mm:ss Z": "Mon, 02 Jan 2006 15:04:05 -0700",
+		"dd/MM/yy h:mm a 'o''clock'":  "02/01/06 3:04 PM o'clock",
+		"yyyyMMdd'T'HHmmssXX":         "20060102T150405Z0700",
+	}
+	for format, layout := range cases {
+		before := time.Now().Add(-time.Minute)
+		v, err := (&Generator{Type: DateTimeGenerator, Format: format}).Generate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		generated, err := time.ParseInLocation(layout, v.(string), time.Local)
+		if err != nil {
+			t.Errorf("expected %s to generate a date time of the layout %s, got %v", format, layout, v)
+		} else if generated.Before(before.Truncate(time.Minute)) {
+			t.Errorf("expected %s to generate the current date time, got %v", format, v)
+		}
+	}
+}
+
+func Test_Generator_ProviderState(t *testing.T) {
+	values := map[string]interface{}{"id": json.Number("42"), "name": "alice"}
+
+	if v, err := (&Generator{Type: ProviderStateGenerator, Expression: "${id}"}).Generate(values); err != nil {
+		t.Fatal(err)
+	} else if v != json.Number("42") {
+		t.Errorf("expected a single value to keep its type, got %#v", v)
+	}
+	if v, err := (&Generator{Type: ProviderStateGenerator, Expression: "/users/${id}/${name}"}).Generate(values); err != nil {
+		t.Fatal(err)
+	} else if v != "/users/42/alice" {
+		t.Errorf("expected the values in the expression, got %v", v)
+	}
+	if _, err := (&Generator{Type: ProviderStateGenerator, Expression: "/users/${email}"}).Generate(values); err == nil {
+		t.Error("expected an error for a value the state setup did not return")
+	}
+}
+
+func Test_Generators_UnmarshalsTheCategories(t *testing.T) {
+	var g Generators
+	err := json.Unmarshal([]byte(`{
+		"path": {"type": "ProviderState", "expression": "/users/${id}"},
+		"header": {"X-Request-Id": {"type": "RandomUuid"}},
+		"body": {"$.items[*].createdAt": {"type": "DateTime", "format": "yyyy-MM-dd"}}
+	}`), &g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := g.Path(); p == nil || p.Expression != "/users/${id}" {
+		t.Errorf("expected the generator of the path, got %v", p)
+	}
+	if g[HeaderCategory]["X-Request-Id"] == nil || g[BodyCategory]["$.items[*].createdAt"] == nil {
+		t.Errorf("expected the generators of the header and body, got %v", g)
+	}
+
+	if err := json.Unmarshal([]byte(`{"body": {"$.id": {"type": "RandomEmail"}}}`), &g); err == nil {
+		t.Error("expected an error for an unknown generator")
+	}
+}
+
+func Test_Generators_ApplyToBody(t *testing.T) {
+	g := Generators{BodyCategory: {
+		"$.items[*].id": &Generator{Type: ProviderStateGenerator, Expression: "${id}"},
+		"$.owner.name":  &Generator{Type: ProviderStateGenerator, Expression: "${name}"},
+		"$.missing":     &Generator{Type: RandomUUIDGenerator},
+	}}
+	var body interface{}
+	json.Unmarshal([]byte(`{"items": [{"id": 1}, {"id": 2}], "owner": {"name": "bob"}}`), &body)
+
+	body, err := g.ApplyToBody(body, map[string]interface{}{"id": 7, "name": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(body)
+	if string(b) != `{"items":[{"id":7},{"id":7}],"owner":{"name":"alice"}}` {
+		t.Errorf("expected the values at the paths to be generated, got %s", b)
+	}
+}
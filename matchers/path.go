@@ -0,0 +1,123 @@
+package matchers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	tokenRoot = iota
+	tokenField
+	tokenIndex
+	tokenStar
+)
+
+type pathToken struct {
+	typ   int
+	name  string
+	index int
+}
+
+//parsedPath is a path expression parsed once, with the error when it is not valid
+type parsedPath struct {
+	tokens []*pathToken
+	err    error
+}
+
+//parsedPaths caches the parsed path expressions, the rules are resolved for every value compared so the
+//expressions of the rules are not parsed again each time. The tokens are shared and never modified
+var parsedPaths sync.Map
+
+//compilePath returns the tokens of the path expression, parsed on its first use
+func compilePath(expr string) ([]*pathToken, error) {
+	if p, ok := parsedPaths.Load(expr); ok {
+		return p.(*parsedPath).tokens, p.(*parsedPath).err
+	}
+	tokens, err := parsePath(expr)
+	parsedPaths.Store(expr, &parsedPath{tokens: tokens, err: err})
+	return tokens, err
+}
+
+//parsePath parses a json path expression like $.items[*].id or $['a key'].* into tokens
+func parsePath(expr string) ([]*pathToken, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf(errInvalidPathMsg, expr)
+	}
+	tokens := []*pathToken{&pathToken{typ: tokenRoot}}
+
+	for rest := expr[1:]; rest != ""; {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf(errInvalidPathMsg, expr)
+			}
+			tokens = append(tokens, fieldOrStar(name))
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end == -1 {
+				return nil, fmt.Errorf(errInvalidPathMsg, expr)
+			}
+			tokens = append(tokens, &pathToken{typ: tokenField, name: rest[2:end]})
+			rest = rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf(errInvalidPathMsg, expr)
+			}
+			if idx := rest[1:end]; idx == "*" {
+				tokens = append(tokens, &pathToken{typ: tokenStar})
+			} else if i, err := strconv.Atoi(idx); err == nil {
+				tokens = append(tokens, &pathToken{typ: tokenIndex, index: i})
+			} else {
+				return nil, fmt.Errorf(errInvalidPathMsg, expr)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf(errInvalidPathMsg, expr)
+		}
+	}
+	return tokens, nil
+}
+
+func fieldOrStar(name string) *pathToken {
+	if name == "*" {
+		return &pathToken{typ: tokenStar}
+	}
+	return &pathToken{typ: tokenField, name: name}
+}
+
+//weight returns how specifically the tokens match the start of the path, 0 means no match.
+//Concrete tokens weigh more than wildcards so the most specific rule wins.
+func weight(tokens []*pathToken, path []interface{}) int {
+	if len(tokens)-1 > len(path) {
+		return 0
+	}
+	w := 1
+	for i, t := range tokens {
+		switch t.typ {
+		case tokenRoot:
+			w *= 2
+		case tokenStar:
+			w *= 1
+		case tokenField:
+			if k, ok := path[i-1].(string); !ok || k != t.name {
+				return 0
+			}
+			w *= 2
+		case tokenIndex:
+			if k, ok := path[i-1].(int); !ok || k != t.index {
+				return 0
+			}
+			w *= 2
+		}
+	}
+	return w
+}
@@ -0,0 +1,46 @@
+package matchers
+
+import "testing"
+
+func Test_Path_ParsesExpressions(t *testing.T) {
+	tokens, err := parsePath("$.items[*].price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 4 || tokens[1].name != "items" || tokens[2].typ != tokenStar || tokens[3].name != "price" {
+		t.Errorf("unexpected tokens for $.items[*].price")
+	}
+
+	tokens, err = parsePath("$['first name'].*[2]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 4 || tokens[1].name != "first name" || tokens[2].typ != tokenStar || tokens[3].index != 2 {
+		t.Errorf("unexpected tokens for $['first name'].*[2]")
+	}
+}
+
+func Test_Path_RejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"items", "$.", "$[abc]", "$['unterminated", "$.a[1"} {
+		if _, err := parsePath(expr); err == nil {
+			t.Errorf("expected %s to be invalid", expr)
+		}
+	}
+}
+
+func Test_Path_WeighsConcreteTokensAboveWildcards(t *testing.T) {
+	path := []interface{}{"items", 1, "price"}
+	wildcard, _ := parsePath("$.items[*].price")
+	concrete, _ := parsePath("$.items[1].price")
+	other, _ := parsePath("$.items[0].price")
+
+	if weight(wildcard, path) == 0 {
+		t.Error("expected wildcard path to match")
+	}
+	if weight(concrete, path) <= weight(wildcard, path) {
+		t.Error("expected concrete path to weigh more than wildcard path")
+	}
+	if weight(other, path) != 0 {
+		t.Error("expected path with different index not to match")
+	}
+}
@@ -0,0 +1,533 @@
+package matchers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	//BodyCategory the category of the rules applied to the body
+	BodyCategory = "body"
+	//HeaderCategory the category of the rules applied to the headers
+	HeaderCategory = "header"
+	//StatusCategory the category of the rules applied to the response status
+	StatusCategory = "status"
+
+	combineAnd = "AND"
+	combineOr  = "OR"
+)
+
+var (
+	errInvalidPathMsg   = "Matching rule path '%s' is not a valid json path expression."
+	errUnknownMatcher   = "unknown matcher '%s'"
+	errNoMatchers       = errors.New("Matching rule has no matchers.")
+	errTypeMismatchMsg  = "expected a value of type %s but received %s"
+	errRegexMismatchMsg = "expected a value matching regex %s but received %v"
+	errNumberMsg        = "expected %s number but received %v"
+	errEqualityMsg      = "expected %v but received %v"
+	errEmptyBinaryMsg   = "expected a non empty binary value"
+	errSha256Msg        = "expected a binary value with sha256 %s but received %s"
+	errStatusCodeMsg    = "expected a %v status but received %v"
+	errEachKeyMsg       = "key %s: %s"
+	errMinLenMsg        = "expected at least %d elements but received %d"
+	errMaxLenMsg        = "expected at most %d elements but received %d"
+)
+
+//statusClasses are the ranges of the status codes matched by the statusCode matcher names
+var statusClasses = map[string][2]int{
+	"information": {100, 200},
+	"success":     {200, 300},
+	"redirect":    {300, 400},
+	"clientError": {400, 500},
+	"serverError": {500, 600},
+	"nonError":    {100, 400},
+	"error":       {400, 600},
+}
+
+//Rule is a single matcher of a matching rule e.g. {"match": "type"}
+type Rule struct {
+	Match string      `json:"match"`
+	Regex string      `json:"regex,omitempty"`
+	Min   *int        `json:"min,omitempty"`
+	Max   *int        `json:"max,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+	//Sha256 is the hex encoded hash a binary value must have, when empty any non empty value matches
+	Sha256 string `json:"sha256,omitempty"`
+	//ContentType is the media type a binary body must be returned with
+	ContentType string `json:"contentType,omitempty"`
+	//Status are the codes a statusCode matcher accepts, either a class like success or a list of codes
+	Status interface{} `json:"status,omitempty"`
+	//Rules are the matchers an eachKey matcher applies to every key of an object, and an eachValue matcher to every
+	//value of an object or array
+	Rules []*Rule `json:"rules,omitempty"`
+	//Variants are the elements an arrayContains matcher requires the array to contain, in any position
+	Variants []*Variant `json:"variants,omitempty"`
+}
+
+//Variant is an element of the example array which an element of the actual array must match by the rules of the
+//variant, keyed by path from the element e.g. $.id
+type Variant struct {
+	Index int   `json:"index"`
+	Rules Rules `json:"rules,omitempty"`
+}
+
+//RuleSet is the list of matchers for a path and the logic used to combine them
+type RuleSet struct {
+	Matchers []*Rule `json:"matchers"`
+	Combine  string  `json:"combine,omitempty"`
+	//Optional lets the value of the path be absent, when present it must match the matchers
+	Optional bool `json:"optional,omitempty"`
+}
+
+//Rules are the rule sets of a category keyed by json path expression
+type Rules map[string]*RuleSet
+
+//MatchingRules are the matching rules of a request or response keyed by category (body, header ...)
+type MatchingRules map[string]Rules
+
+//UnmarshalJSON reads the rules keyed by category, the flat rules of version 2 pacts keyed by the path of the value
+//in the request or response, e.g. {"$.body.id": {"match": "type"}}, are read into the category of the path. The
+//header rules of version 3 pacts keyed by the header name are read keyed by the path of the header, e.g. $['Accept']
+func (m *MatchingRules) UnmarshalJSON(b []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+
+	rules := make(MatchingRules, len(obj))
+	for key, raw := range obj {
+		if !strings.HasPrefix(key, "$") {
+			var r Rules
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return err
+			}
+			if key == HeaderCategory {
+				r = namedPaths(r)
+			}
+			rules[key] = r
+			continue
+		}
+
+		category, expr := v2Path(key)
+		var rule Rule
+		if err := json.Unmarshal(raw, &rule); err != nil {
+			return err
+		}
+		if rule.Match == "" && rule.Regex != "" {
+			rule.Match = "regex"
+		} else if rule.Match == "" {
+			rule.Match = "type"
+		}
+		if rules[category] == nil {
+			rules[category] = make(Rules)
+		}
+		rules[category][expr] = &RuleSet{Matchers: []*Rule{&rule}}
+	}
+	*m = rules
+	return nil
+}
+
+//namedPaths keys the rule sets keyed by a name rather than a path expression by the path of the name
+func namedPaths(r Rules) Rules {
+	paths := make(Rules, len(r))
+	for expr, s := range r {
+		if !strings.HasPrefix(expr, "$") {
+			expr = fmt.Sprintf("$['%s']", expr)
+		}
+		paths[expr] = s
+	}
+	return paths
+}
+
+//v2Path returns the category and the path within it of a version 2 rule path, e.g. $.body.id is $.id of the
+//body and $.headers.Accept is $.Accept of the headers
+func v2Path(key string) (string, string) {
+	for prefix, category := range map[string]string{"$.body": BodyCategory, "$.headers": HeaderCategory, "$.status": StatusCategory} {
+		if key == prefix {
+			return category, "$"
+		} else if rest := strings.TrimPrefix(key, prefix); rest != key && (rest[0] == '.' || rest[0] == '[') {
+			return category, "$" + rest
+		}
+	}
+	return key, "$"
+}
+
+//UnmarshalJSON reads the rule sets keyed by path, a category with a single rule set applying to the whole value
+//like {"status": {"matchers": [...]}} is keyed by the root path $
+func (r *Rules) UnmarshalJSON(b []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	if _, ok := obj["matchers"]; ok {
+		var s RuleSet
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*r = Rules{"$": &s}
+		return nil
+	}
+	rules := make(Rules, len(obj))
+	for expr, raw := range obj {
+		var s *RuleSet
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		rules[expr] = s
+	}
+	*r = rules
+	return nil
+}
+
+//Resolve returns the most specific rule set applying to the path, rules defined on a parent path
+//cascade down to its children. Rules of the same weight are ranked by the longer path, then by the lexical
+//order of their expressions so the same rule wins every time. Returns nil when no rule applies.
+func (r Rules) Resolve(path []interface{}) *RuleSet {
+	var resolved *RuleSet
+	var resolvedExpr string
+	max, maxLen := 0, 0
+	for expr, s := range r {
+		tokens, err := compilePath(expr)
+		if err != nil {
+			continue
+		}
+		w := weight(tokens, path)
+		if w == 0 || w < max || w == max && (len(tokens) < maxLen || len(tokens) == maxLen && expr > resolvedExpr) {
+			continue
+		}
+		//the values of an each rule cascade the rules of its eachValue matchers, rather than the each rule
+		if s.MatchesEach() && len(tokens)-1 < len(path) {
+			if s = s.eachValueRules(); s == nil {
+				continue
+			}
+		}
+		max, maxLen, resolved, resolvedExpr = w, len(tokens), s, expr
+	}
+	return resolved
+}
+
+//IsOptional returns true when a rule set marked optional is keyed by the path itself, so the value may be absent.
+//Unlike the matchers, optional does not cascade down to the children of the path
+func (r Rules) IsOptional(path []interface{}) bool {
+	for expr, s := range r {
+		if s == nil || !s.Optional {
+			continue
+		}
+		if tokens, err := compilePath(expr); err == nil && len(tokens)-1 == len(path) && weight(tokens, path) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+//Merge returns the rules with the rule sets of the override added, an override rule set replaces the rule set of
+//the same category and path. Neither rules are modified
+func (m MatchingRules) Merge(override MatchingRules) MatchingRules {
+	merged := make(MatchingRules, len(m))
+	for category, rules := range m {
+		merged[category] = make(Rules, len(rules))
+		for expr, s := range rules {
+			merged[category][expr] = s
+		}
+	}
+	for category, rules := range override {
+		if merged[category] == nil {
+			merged[category] = make(Rules, len(rules))
+		}
+		for expr, s := range rules {
+			merged[category][expr] = s
+		}
+	}
+	return merged
+}
+
+//Validate checks every path expression and rule set is well formed
+func (r Rules) Validate() error {
+	for expr, s := range r {
+		if _, err := compilePath(expr); err != nil {
+			return err
+		}
+		if s == nil || len(s.Matchers) == 0 {
+			return errNoMatchers
+		}
+	}
+	return nil
+}
+
+//Matches checks the actual value against the rules, the expected value is the example recorded by the consumer
+func (s *RuleSet) Matches(expected, actual interface{}) (bool, string) {
+	var failures []string
+	for _, m := range s.Matchers {
+		ok, how := m.matches(expected, actual)
+		if ok && strings.EqualFold(s.Combine, combineOr) {
+			return true, ""
+		} else if !ok {
+			failures = append(failures, how)
+		}
+	}
+	if len(failures) > 0 {
+		return false, strings.Join(failures, ", ")
+	}
+	return true, ""
+}
+
+//String describes the matchers of the rule set e.g. type or regex \d+ and min 1
+func (s *RuleSet) String() string {
+	m := make([]string, len(s.Matchers))
+	for n, r := range s.Matchers {
+		m[n] = r.String()
+	}
+	combine := " and "
+	if strings.EqualFold(s.Combine, combineOr) {
+		combine = " or "
+	}
+	return strings.Join(m, combine)
+}
+
+//String describes the matcher e.g. regex \d+
+func (m *Rule) String() string {
+	s := m.Match
+	if m.Regex != "" {
+		s += " " + m.Regex
+	}
+	if m.Min != nil {
+		s += fmt.Sprintf(" min %d", *m.Min)
+	}
+	if m.Max != nil {
+		s += fmt.Sprintf(" max %d", *m.Max)
+	}
+	if m.Status != nil {
+		s += fmt.Sprintf(" %v", m.Status)
+	}
+	if len(m.Rules) > 0 {
+		rules := make([]string, len(m.Rules))
+		for n, r := range m.Rules {
+			rules[n] = r.String()
+		}
+		s += "(" + strings.Join(rules, " and ") + ")"
+	}
+	return s
+}
+
+//IsBinary returns true when the values are matched as binary, rather than by their contents
+func (s *RuleSet) IsBinary() bool {
+	for _, m := range s.Matchers {
+		if m.Match == "binary" {
+			return true
+		}
+	}
+	return false
+}
+
+//ComparesByExample returns true when the collection elements are compared against the first example element
+func (s *RuleSet) ComparesByExample() bool {
+	for _, m := range s.Matchers {
+		if m.Match == "type" || m.Match == "eachValue" {
+			return true
+		}
+	}
+	return false
+}
+
+//MatchesEach returns true when the keys or values of an object are matched by an eachKey or eachValue matcher,
+//rather than the keys of the example
+func (s *RuleSet) MatchesEach() bool {
+	for _, m := range s.Matchers {
+		if m.Match == "eachKey" || m.Match == "eachValue" {
+			return true
+		}
+	}
+	return false
+}
+
+//ArrayVariants returns the variants of the arrayContains matchers, nil when there are none
+func (s *RuleSet) ArrayVariants() []*Variant {
+	var variants []*Variant
+	for _, m := range s.Matchers {
+		if m.Match == "arrayContains" {
+			variants = append(variants, m.Variants...)
+		}
+	}
+	return variants
+}
+
+//eachValueRules returns the rule set of the matchers of the eachValue matchers, nil when there are none
+func (s *RuleSet) eachValueRules() *RuleSet {
+	var rules []*Rule
+	for _, m := range s.Matchers {
+		if m.Match == "eachValue" {
+			rules = append(rules, m.Rules...)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return &RuleSet{Matchers: rules}
+}
+
+func (m *Rule) matches(expected, actual interface{}) (bool, string) {
+	switch m.Match {
+	case "type":
+		if et, at := jsonType(expected), jsonType(actual); et != at {
+			return false, fmt.Sprintf(errTypeMismatchMsg, et, at)
+		}
+	case "regex":
+		re, err := regexp.Compile("^(?:" + m.Regex + ")$")
+		if err != nil {
+			return false, err.Error()
+		}
+		if s, ok := actual.(string); !ok || !re.MatchString(s) {
+			return false, fmt.Sprintf(errRegexMismatchMsg, m.Regex, actual)
+		}
+	case "number":
+		if jsonType(actual) != "number" {
+			return false, fmt.Sprintf(errNumberMsg, "a", actual)
+		}
+	case "integer":
+		if jsonType(actual) != "number" || strings.ContainsAny(numberString(actual), ".eE") {
+			return false, fmt.Sprintf(errNumberMsg, "an integer", actual)
+		}
+	case "decimal":
+		if jsonType(actual) != "number" || !strings.Contains(numberString(actual), ".") {
+			return false, fmt.Sprintf(errNumberMsg, "a decimal", actual)
+		}
+	case "equality":
+		if !reflect.DeepEqual(expected, actual) {
+			return false, fmt.Sprintf(errEqualityMsg, expected, actual)
+		}
+	case "binary":
+		b := binaryOf(actual)
+		if len(b) == 0 {
+			return false, errEmptyBinaryMsg
+		}
+		if m.Sha256 != "" {
+			if sum := sha256.Sum256(b); !strings.EqualFold(m.Sha256, hex.EncodeToString(sum[:])) {
+				return false, fmt.Sprintf(errSha256Msg, m.Sha256, hex.EncodeToString(sum[:]))
+			}
+		}
+	case "statusCode":
+		if !m.matchesStatus(actual) {
+			return false, fmt.Sprintf(errStatusCodeMsg, m.Status, actual)
+		}
+	case "eachKey":
+		obj, ok := actual.(map[string]interface{})
+		if !ok {
+			return false, fmt.Sprintf(errTypeMismatchMsg, "object", jsonType(actual))
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, r := range m.Rules {
+				if ok, how := r.matches(k, k); !ok {
+					return false, fmt.Sprintf(errEachKeyMsg, k, how)
+				}
+			}
+		}
+	case "eachValue":
+		//the values are matched when they are traversed, by the rules cascaded to them
+		if t := jsonType(actual); t != "object" && t != "array" {
+			return false, fmt.Sprintf(errTypeMismatchMsg, "object or array", t)
+		}
+	case "arrayContains":
+		//the variants are matched when the array is traversed
+		if t := jsonType(actual); t != "array" {
+			return false, fmt.Sprintf(errTypeMismatchMsg, "array", t)
+		}
+	default:
+		return false, fmt.Sprintf(errUnknownMatcher, m.Match)
+	}
+	return m.matchesLength(actual)
+}
+
+//matchesLength checks an actual array has the min and max number of elements of the matcher
+func (m *Rule) matchesLength(actual interface{}) (bool, string) {
+	a, ok := actual.([]interface{})
+	if !ok {
+		return true, ""
+	}
+	if m.Min != nil && len(a) < *m.Min {
+		return false, fmt.Sprintf(errMinLenMsg, *m.Min, len(a))
+	}
+	if m.Max != nil && len(a) > *m.Max {
+		return false, fmt.Sprintf(errMaxLenMsg, *m.Max, len(a))
+	}
+	return true, ""
+}
+
+//matchesStatus checks the status code is in the class or the list of codes of the statusCode matcher
+func (m *Rule) matchesStatus(actual interface{}) bool {
+	code, ok := intOf(actual)
+	if !ok {
+		return false
+	}
+	switch s := m.Status.(type) {
+	case string:
+		r, ok := statusClasses[s]
+		return ok && code >= r[0] && code < r[1]
+	case []interface{}:
+		for _, v := range s {
+			if c, ok := intOf(v); ok && c == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func intOf(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), n == float64(int(n))
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64, float32, int, int64, int32:
+		return "number"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return reflect.TypeOf(v).String()
+}
+
+func binaryOf(v interface{}) []byte {
+	switch b := v.(type) {
+	case []byte:
+		return b
+	case string:
+		return []byte(b)
+	}
+	return nil
+}
+
+func numberString(v interface{}) string {
+	if n, ok := v.(json.Number); ok {
+		return n.String()
+	}
+	return fmt.Sprint(v)
+}
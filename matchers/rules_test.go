@@ -0,0 +1,251 @@
+package matchers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_Rules_ResolvesWildcardForEveryElement(t *testing.T) {
+	decimal := &RuleSet{Matchers: []*Rule{&Rule{Match: "decimal"}}}
+	r := Rules{"$.items[*].price": decimal}
+
+	for i := 0; i < 3; i++ {
+		if rs := r.Resolve([]interface{}{"items", i, "price"}); rs != decimal {
+			t.Errorf("expected decimal rule for item %d", i)
+		}
+	}
+	if rs := r.Resolve([]interface{}{"items", 0, "name"}); rs != nil {
+		t.Error("expected no rule for $.items[0].name")
+	}
+	if rs := r.Resolve([]interface{}{"items"}); rs != nil {
+		t.Error("expected no rule for $.items")
+	}
+}
+
+func Test_Rules_ResolvesStarForEveryKey(t *testing.T) {
+	typ := &RuleSet{Matchers: []*Rule{&Rule{Match: "type"}}}
+	r := Rules{"$.*": typ}
+
+	for _, k := range []string{"id", "name"} {
+		if rs := r.Resolve([]interface{}{k}); rs != typ {
+			t.Errorf("expected type rule for %s", k)
+		}
+	}
+}
+
+func Test_Rules_ResolvesMostSpecificRule(t *testing.T) {
+	typ := &RuleSet{Matchers: []*Rule{&Rule{Match: "type"}}}
+	regex := &RuleSet{Matchers: []*Rule{&Rule{Match: "regex", Regex: "\\d+"}}}
+	r := Rules{"$.items": typ, "$.items[*].id": regex}
+
+	if rs := r.Resolve([]interface{}{"items", 0, "id"}); rs != regex {
+		t.Error("expected regex rule for $.items[0].id")
+	}
+	if rs := r.Resolve([]interface{}{"items", 0, "name"}); rs != typ {
+		t.Error("expected type rule to cascade to $.items[0].name")
+	}
+}
+
+func Test_Rules_ResolvesRulesOfSameWeightDeterministically(t *testing.T) {
+	typ := &RuleSet{Matchers: []*Rule{&Rule{Match: "type"}}}
+	decimal := &RuleSet{Matchers: []*Rule{&Rule{Match: "decimal"}}}
+	regex := &RuleSet{Matchers: []*Rule{&Rule{Match: "regex", Regex: "\\w+"}}}
+	r := Rules{"$.items[0]": typ, "$.items[*].price": decimal, "$.a.*": typ, "$.*.b": regex}
+
+	//the maps are iterated in a random order, so the rules are resolved many times
+	for i := 0; i < 50; i++ {
+		if rs := r.Resolve([]interface{}{"items", 0, "price"}); rs != decimal {
+			t.Fatal("expected the rule of the longer path $.items[*].price for $.items[0].price")
+		}
+		if rs := r.Resolve([]interface{}{"a", "b"}); rs != regex {
+			t.Fatal("expected the rule of the lexically first path $.*.b for $.a.b")
+		}
+	}
+}
+
+func Test_Rules_IsOptionalOnlyAtItsPath(t *testing.T) {
+	r := Rules{"$.users[*].nickname": &RuleSet{Matchers: []*Rule{&Rule{Match: "type"}}, Optional: true}}
+
+	if !r.IsOptional([]interface{}{"users", 1, "nickname"}) {
+		t.Error("expected $.users[1].nickname to be optional")
+	}
+	if r.IsOptional([]interface{}{"users", 1}) || r.IsOptional([]interface{}{"users", 1, "nickname", "first"}) {
+		t.Error("expected optional to apply to the path only")
+	}
+}
+
+func Test_RuleSet_Matches(t *testing.T) {
+	tests := []struct {
+		rule     *Rule
+		expected interface{}
+		actual   interface{}
+		ok       bool
+	}{
+		{&Rule{Match: "type"}, "John", "Jane", true},
+		{&Rule{Match: "type"}, "John", json.Number("1"), false},
+		{&Rule{Match: "regex", Regex: "\\d{4}-\\d{2}-\\d{2}"}, "2000-01-01", "2016-02-29", true},
+		{&Rule{Match: "regex", Regex: "\\d{4}-\\d{2}-\\d{2}"}, "2000-01-01", "29/02/2016", false},
+		{&Rule{Match: "decimal"}, json.Number("1.5"), json.Number("10.25"), true},
+		{&Rule{Match: "decimal"}, json.Number("1.5"), json.Number("10"), false},
+		{&Rule{Match: "integer"}, json.Number("1"), json.Number("42"), true},
+		{&Rule{Match: "integer"}, json.Number("1"), json.Number("4.2"), false},
+		{&Rule{Match: "number"}, json.Number("1"), "1", false},
+		{&Rule{Match: "equality"}, "John", "John", true},
+		{&Rule{Match: "equality"}, "John", "Jane", false},
+		{&Rule{Match: "binary"}, nil, []byte{0x89, 0x50}, true},
+		{&Rule{Match: "binary"}, nil, []byte{}, false},
+		{&Rule{Match: "binary", Sha256: "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824"}, nil, []byte("hello"), true},
+		{&Rule{Match: "binary", Sha256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"}, nil, []byte("hello!"), false},
+		{&Rule{Match: "statusCode", Status: "success"}, 200, 204, true},
+		{&Rule{Match: "statusCode", Status: "success"}, 200, 302, false},
+		{&Rule{Match: "statusCode", Status: []interface{}{200.0, 201.0}}, 200, 201, true},
+		{&Rule{Match: "statusCode", Status: []interface{}{200.0, 201.0}}, 200, 202, false},
+		{&Rule{Match: "unknown"}, "John", "John", false},
+	}
+
+	for _, test := range tests {
+		rs := &RuleSet{Matchers: []*Rule{test.rule}}
+		if ok, how := rs.Matches(test.expected, test.actual); ok != test.ok {
+			t.Errorf("expected %s match of %v to be %v: %s", test.rule.Match, test.actual, test.ok, how)
+		}
+	}
+}
+
+func Test_RuleSet_CombinesMatchers(t *testing.T) {
+	matchers := []*Rule{&Rule{Match: "regex", Regex: "a+"}, &Rule{Match: "regex", Regex: "b+"}}
+
+	if ok, _ := (&RuleSet{Matchers: matchers}).Matches("a", "aaa"); ok {
+		t.Error("expected AND combined matchers to fail")
+	}
+	if ok, _ := (&RuleSet{Matchers: matchers, Combine: "OR"}).Matches("a", "aaa"); !ok {
+		t.Error("expected OR combined matchers to pass")
+	}
+}
+
+func Test_Rules_Validate(t *testing.T) {
+	if err := (Rules{"$.id": &RuleSet{Matchers: []*Rule{&Rule{Match: "type"}}}}).Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := (Rules{"id": &RuleSet{Matchers: []*Rule{&Rule{Match: "type"}}}}).Validate(); err == nil {
+		t.Error("expected invalid path error")
+	}
+	if err := (Rules{"$.id": &RuleSet{}}).Validate(); err != errNoMatchers {
+		t.Errorf("expected %s, got %v", errNoMatchers, err)
+	}
+}
+
+func Test_MatchingRules_MergeReplacesRuleSetOfSamePath(t *testing.T) {
+	typ := &RuleSet{Matchers: []*Rule{&Rule{Match: "type"}}}
+	regex := &RuleSet{Matchers: []*Rule{&Rule{Match: "regex", Regex: "\\d+"}}}
+	decimal := &RuleSet{Matchers: []*Rule{&Rule{Match: "decimal"}}}
+	rules := MatchingRules{BodyCategory: Rules{"$.id": regex, "$.name": typ}}
+
+	merged := rules.Merge(MatchingRules{BodyCategory: Rules{"$.id": typ, "$.price": decimal}, HeaderCategory: Rules{"$.Date": typ}})
+	body := merged[BodyCategory]
+	if body["$.id"] != typ || body["$.name"] != typ || body["$.price"] != decimal || merged[HeaderCategory]["$.Date"] != typ {
+		t.Errorf("unexpected merged rules %v", merged)
+	}
+	if rules[BodyCategory]["$.id"] != regex || len(rules[BodyCategory]) != 2 || len(rules) != 1 {
+		t.Errorf("expected the rules not to be modified, got %v", rules)
+	}
+}
+
+func Test_Rules_Resolve_CascadesEachValueRules(t *testing.T) {
+	each := &RuleSet{Matchers: []*Rule{
+		&Rule{Match: "eachKey", Rules: []*Rule{&Rule{Match: "regex", Regex: "[a-z]+"}}},
+		&Rule{Match: "eachValue", Rules: []*Rule{&Rule{Match: "type"}}},
+	}}
+	rules := Rules{"$.users": each}
+
+	if rs := rules.Resolve([]interface{}{"users"}); rs != each {
+		t.Errorf("expected the each rule set at its path, got %v", rs)
+	}
+	if rs := rules.Resolve([]interface{}{"users", "john", "age"}); rs == nil || rs.String() != "type" {
+		t.Errorf("expected the eachValue rules to cascade to the values, got %v", rs)
+	}
+	if ok, _ := each.Matches(nil, map[string]interface{}{"john": 1}); !ok {
+		t.Error("expected the keys to match")
+	}
+	if ok, how := each.Matches(nil, map[string]interface{}{"john": 1, "J0hn": 2}); ok || how != "key J0hn: expected a value matching regex [a-z]+ but received J0hn" {
+		t.Errorf("expected the key to fail the regex, got %s", how)
+	}
+
+	keysOnly := Rules{"$.users": &RuleSet{Matchers: []*Rule{each.Matchers[0]}}}
+	if rs := keysOnly.Resolve([]interface{}{"users", "john"}); rs != nil {
+		t.Errorf("expected an eachKey rule not to cascade to the values, got %v", rs)
+	}
+}
+
+func Test_Rules_UnmarshalsRuleSetOfWholeValue(t *testing.T) {
+	var rules MatchingRules
+	if err := json.Unmarshal([]byte(`{
+		"status": {"matchers": [{"match": "statusCode", "status": "success"}]},
+		"body": {"$.id": {"matchers": [{"match": "type"}]}}
+	}`), &rules); err != nil {
+		t.Fatal(err)
+	}
+	if rs := rules[StatusCategory].Resolve(nil); rs == nil || rs.Matchers[0].Status != "success" {
+		t.Errorf("expected the status rule set at the root path, got %v", rules[StatusCategory])
+	}
+	if rs := rules[BodyCategory]["$.id"]; rs == nil || rs.Matchers[0].Match != "type" {
+		t.Errorf("expected the body rule sets keyed by path, got %v", rules[BodyCategory])
+	}
+}
+
+func Test_MatchingRules_UnmarshalsHeaderRulesByName(t *testing.T) {
+	var rules MatchingRules
+	if err := json.Unmarshal([]byte(`{
+		"header": {
+			"X-Correlation-Id": {"matchers": [{"match": "regex", "regex": "[0-9a-f-]+"}]},
+			"$.Accept": {"matchers": [{"match": "type"}]}
+		}
+	}`), &rules); err != nil {
+		t.Fatal(err)
+	}
+	if err := rules[HeaderCategory].Validate(); err != nil {
+		t.Errorf("expected the header rules to be valid, got %s", err)
+	}
+	if rs := rules[HeaderCategory]["$['X-Correlation-Id']"]; rs == nil || rs.String() != "regex [0-9a-f-]+" {
+		t.Errorf("expected the rule of the header name keyed by its path, got %v", rules[HeaderCategory])
+	}
+	if rs := rules[HeaderCategory]["$.Accept"]; rs == nil || rs.String() != "type" {
+		t.Errorf("expected the rule keyed by path to be kept, got %v", rules[HeaderCategory])
+	}
+}
+
+func Test_MatchingRules_UnmarshalsVersion2Rules(t *testing.T) {
+	var rules MatchingRules
+	if err := json.Unmarshal([]byte(`{
+		"$.body": {"match": "type"},
+		"$.body[*].id": {"regex": "\\d+"},
+		"$.headers.Date": {"match": "type"}
+	}`), &rules); err != nil {
+		t.Fatal(err)
+	}
+	if rs := rules[BodyCategory]["$"]; rs == nil || rs.String() != "type" {
+		t.Errorf("expected the rule of the whole body at the root path, got %v", rules[BodyCategory])
+	}
+	if rs := rules[BodyCategory]["$[*].id"]; rs == nil || rs.String() != "regex \\d+" {
+		t.Errorf("expected the regex rule of the ids, got %v", rules[BodyCategory])
+	}
+	if rs := rules[HeaderCategory]["$.Date"]; rs == nil || rs.String() != "type" {
+		t.Errorf("expected the header rule, got %v", rules[HeaderCategory])
+	}
+}
+
+func Test_RuleSet_Matches_EnforcesMinAndMax(t *testing.T) {
+	min, max := 2, 3
+	rs := &RuleSet{Matchers: []*Rule{&Rule{Match: "type", Min: &min, Max: &max}}}
+
+	for _, n := range []int{2, 3} {
+		if ok, how := rs.Matches([]interface{}{"a"}, make([]interface{}, n)); !ok {
+			t.Errorf("expected %d elements to match, got %s", n, how)
+		}
+	}
+	if ok, how := rs.Matches([]interface{}{"a"}, []interface{}{"a"}); ok || how != "expected at least 2 elements but received 1" {
+		t.Errorf("expected too few elements to fail the min, got %v %s", ok, how)
+	}
+	if ok, how := rs.Matches([]interface{}{"a"}, make([]interface{}, 4)); ok || how != "expected at most 3 elements but received 4" {
+		t.Errorf("expected too many elements to fail the max, got %v %s", ok, how)
+	}
+}
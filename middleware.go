@@ -0,0 +1,41 @@
+package pact
+
+import (
+	"net/http"
+)
+
+//RequestHandler sends the request to the provider and returns its response
+type RequestHandler func(req *http.Request) (*http.Response, error)
+
+//RequestMiddleware wraps the handler sending the requests to the provider. It can inspect or modify the request
+//before calling next, inspect or modify the response it returns, or respond without calling next at all
+type RequestMiddleware func(next RequestHandler) RequestHandler
+
+//middlewareTransport sends the requests through the middlewares, the first middleware is the outermost
+type middlewareTransport struct {
+	handler RequestHandler
+}
+
+func (t *middlewareTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.handler(req)
+}
+
+//withMiddlewares returns a copy of the client whose requests are sent through the middlewares around its transport
+func withMiddlewares(c *http.Client, middlewares []RequestMiddleware) *http.Client {
+	if len(middlewares) == 0 || c == nil {
+		return c
+	}
+
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	handler := RequestHandler(base.RoundTrip)
+	for n := len(middlewares) - 1; n >= 0; n-- {
+		handler = middlewares[n](handler)
+	}
+
+	wrapped := *c
+	wrapped.Transport = &middlewareTransport{handler: handler}
+	return &wrapped
+}
@@ -0,0 +1,83 @@
+package pact
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_Verifier_Use_RunsMiddlewaresInOrder(t *testing.T) {
+	var calls []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "provider "+r.Header.Get("X-Tenant"))
+		userHandlerWithValidData(w, r)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	record := func(name string) RequestMiddleware {
+		return func(next RequestHandler) RequestHandler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				if name == "tenant" {
+					req.Header.Set("X-Tenant", "acme")
+				}
+				resp, err := next(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		Use(record("logging")).
+		Use(record("tenant")).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"logging before", "tenant before", "provider acme", "tenant after", "logging after"}
+	if len(calls) != 10 || fmt.Sprint(calls[:5]) != fmt.Sprint(expected) {
+		t.Errorf("expected the middlewares to run in order %v, got %v", expected, calls)
+	}
+}
+
+func Test_Verifier_Use_MiddlewareCanShortCircuit(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected the provider not to be called")
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	stub := func(next RequestHandler) RequestHandler {
+		return func(req *http.Request) (*http.Response, error) {
+			rec := httptest.NewRecorder()
+			userHandlerWithValidData(rec, req)
+			resp := rec.Result()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(rec.Body.Bytes()))
+			return resp, nil
+		}
+	}
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		Use(stub).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
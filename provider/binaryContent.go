@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const base64Encoding = "base64"
+
+type binaryContent struct {
+	data []byte
+}
+
+func (c *binaryContent) GetData() ([]byte, error) {
+	return c.data, nil
+}
+
+func (c *binaryContent) GetBody() interface{} {
+	return c.data
+}
+
+func (c *binaryContent) SetBody(content interface{}) error {
+	if v, ok := content.([]byte); ok {
+		c.data = v
+		return nil
+	}
+	return errors.New("content is not valid binary")
+}
+
+//decodeBinaryBody returns the bytes of a base64 encoded body, which is either an encoded body object
+//like {"content": "iVBORw0K", "contentType": "image/png", "encoded": "base64"} or a string body with a binary
+//content type. The content type of an encoded body object is added to the headers when they have none
+func decodeBinaryBody(body interface{}, headers *http.Header) ([]byte, bool) {
+	switch b := body.(type) {
+	case map[string]interface{}:
+		content, ok := b["content"].(string)
+		if encoded, _ := b["encoded"].(string); !ok || !strings.EqualFold(encoded, base64Encoding) {
+			return nil, false
+		}
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, false
+		}
+		if contentType, ok := b["contentType"].(string); ok && headers.Get("Content-Type") == "" {
+			if *headers == nil {
+				*headers = make(http.Header)
+			}
+			headers.Set("Content-Type", contentType)
+		}
+		return data, true
+	case string:
+		if !isBinaryContentType(headers.Get("Content-Type")) {
+			return nil, false
+		}
+		data, err := base64.StdEncoding.DecodeString(b)
+		return data, err == nil
+	}
+	return nil, false
+}
+
+//isBinaryContentType returns true for media types whose body is neither json nor text e.g. images and pdfs
+func isBinaryContentType(contentType string) bool {
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/", "application/pdf", "application/octet-stream", "application/zip"} {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(contentType)), prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestCanSetBinaryContent(t *testing.T) {
+	content := &binaryContent{}
+
+	if err := content.SetBody([]byte{0x89, 0x50, 0x4e, 0x47}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCannotSetNonBinaryContent(t *testing.T) {
+	content := &binaryContent{}
+
+	if err := content.SetBody("some text"); err == nil {
+		t.Error("expected to fail in setting non binary value")
+	}
+}
+
+func TestBinaryResponseIsNotParsed(t *testing.T) {
+	png := []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a}
+	httpResp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"image/png"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(png)),
+	}
+
+	resp, err := CreateResponseFromHTTPResponse(httpResp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := resp.GetBody().([]byte); !ok || !bytes.Equal(b, png) {
+		t.Errorf("expected the raw png bytes, got %#v", resp.GetBody())
+	}
+}
+
+func TestBase64BinaryBodiesAreDecoded(t *testing.T) {
+	var req Request
+	if err := json.Unmarshal([]byte(`{"method": "POST", "path": "/convert", "headers": {"Content-Type": "image/png"}, "body": "iVBORw0KGgoAAQID"}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := req.GetBody().([]byte); !ok || !bytes.Equal(b, []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x01, 0x02, 0x03}) {
+		t.Errorf("expected the decoded request bytes, got %#v", req.GetBody())
+	}
+
+	var resp Response
+	if err := json.Unmarshal([]byte(`{"status": 200, "body": {"content": "JVBERi0A//4=", "contentType": "application/pdf", "encoded": "base64"}}`), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := resp.GetBody().([]byte); !ok || !bytes.Equal(b, []byte("%PDF-\x00\xff\xfe")) {
+		t.Errorf("expected the decoded response bytes, got %#v", resp.GetBody())
+	}
+	if ct := resp.Headers.Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("expected the content type of the encoded body, got %s", ct)
+	}
+}
+
+func TestTextBodiesAreNotDecoded(t *testing.T) {
+	var req Request
+	if err := json.Unmarshal([]byte(`{"method": "POST", "path": "/notes", "headers": {"Content-Type": "text/plain"}, "body": "aGVsbG8="}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := req.GetBody().(string); !ok || b != "aGVsbG8=" {
+		t.Errorf("expected the text body as is, got %#v", req.GetBody())
+	}
+}
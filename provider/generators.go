@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/matchers"
+)
+
+//Generate returns a copy of the request whose values are produced by its generators, the values returned by the
+//provider state setup are given to the ProviderState generators. Body generators only apply to a json body
+func (p *Request) Generate(values map[string]interface{}) (*Request, error) {
+	r := *p
+	if g := p.Generators.Path(); g != nil {
+		path, err := g.Generate(values)
+		if err != nil {
+			return nil, err
+		}
+		r.Path = fmt.Sprint(path)
+	}
+
+	if headers := p.Generators[matchers.HeaderCategory]; len(headers) > 0 {
+		r.Headers = make(http.Header, len(p.Headers)+len(headers))
+		for k, v := range p.Headers {
+			r.Headers[k] = v
+		}
+		for name, g := range headers {
+			v, err := g.Generate(values)
+			if err != nil {
+				return nil, err
+			}
+			for k := range r.Headers {
+				if strings.EqualFold(k, name) {
+					delete(r.Headers, k)
+				}
+			}
+			r.Headers[name] = []string{fmt.Sprint(v)}
+		}
+	}
+
+	if params := p.Generators[matchers.QueryCategory]; len(params) > 0 {
+		q, err := url.ParseQuery(p.Query)
+		if err != nil {
+			return nil, err
+		}
+		for name, g := range params {
+			v, err := g.Generate(values)
+			if err != nil {
+				return nil, err
+			}
+			q.Set(name, fmt.Sprint(v))
+		}
+		r.Query = q.Encode()
+	}
+
+	if _, ok := p.httpContent.(*jsonContent); ok && len(p.Generators[matchers.BodyCategory]) > 0 {
+		data, err := p.GetData()
+		if err != nil {
+			return nil, err
+		}
+		//the body is decoded again so the generated values do not change the pact
+		var body interface{}
+		if err := DecodeJSON(data, &body); err != nil {
+			return nil, err
+		}
+		if body, err = p.Generators.ApplyToBody(body, values); err != nil {
+			return nil, err
+		}
+		r.httpContent = &jsonContent{}
+		if err := r.SetBody(body); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
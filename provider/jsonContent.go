@@ -6,16 +6,24 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strings"
 )
 
+//DecodeJSON decodes the json into v with its numbers as json.Number, so integers beyond 2^53 keep their precision
+func DecodeJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+const jsonContentType = "application/json"
+
 type jsonContent struct {
 	data      map[string]interface{}
 	sliceData []interface{}
 }
 
 func (c *jsonContent) GetData() ([]byte, error) {
-	if len(c.data) > 0 {
+	if c.data != nil {
 		return json.Marshal(c.data)
 	} else if c.sliceData != nil {
 		return json.Marshal(c.sliceData)
@@ -24,8 +32,9 @@ func (c *jsonContent) GetData() ([]byte, error) {
 	}
 }
 
+//GetBody returns the object or array of the content, an empty object {} is kept as an empty map
 func (c *jsonContent) GetBody() interface{} {
-	if len(c.data) > 0 {
+	if c.data != nil {
 		return c.data
 	} else if c.sliceData != nil {
 		return c.sliceData
@@ -54,9 +63,7 @@ func (c *jsonContent) setJSONStringBody(content string) error {
 	}
 
 	var val interface{}
-	d := json.NewDecoder(strings.NewReader(content))
-	d.UseNumber()
-	if err := d.Decode(&val); err != nil {
+	if err := DecodeJSON([]byte(content), &val); err != nil {
 		return err
 	}
 	switch v := reflect.ValueOf(val); v.Kind() {
@@ -0,0 +1,21 @@
+package provider
+
+import "testing"
+
+func TestKeepsEmptyJSONContent(t *testing.T) {
+	for _, body := range []string{"{}", "[]"} {
+		content := &jsonContent{}
+		if err := content.SetBody(body); err != nil {
+			t.Fatal(err)
+		}
+
+		if content.GetBody() == nil {
+			t.Errorf("expected the empty body %s to be kept", body)
+		}
+		if data, err := content.GetData(); err != nil {
+			t.Error(err)
+		} else if string(data) != body {
+			t.Errorf("expected the empty body %s to be serialised, got %q", body, data)
+		}
+	}
+}
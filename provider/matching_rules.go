@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"encoding/json"
+
+	"github.com/SEEK-Jobs/pact-go/matchers"
+)
+
+func unmarshalMatchingRules(b []byte, rules *matchers.MatchingRules) error {
+	var obj struct {
+		MatchingRules matchers.MatchingRules `json:"matchingRules"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	for _, r := range obj.MatchingRules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	*rules = obj.MatchingRules
+	return nil
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCanUnmarshalResponseMatchingRules(t *testing.T) {
+	var r Response
+	if err := json.Unmarshal([]byte(`{"status": 200, "matchingRules": {"body": {"$.items[*].id": {"matchers": [{"match": "type"}]}}}}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if rs := r.MatchingRules["body"]["$.items[*].id"]; rs == nil || rs.Matchers[0].Match != "type" {
+		t.Error("expected type matching rule for $.items[*].id")
+	}
+
+	b, err := json.Marshal(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"matchingRules"`) {
+		t.Errorf("expected matching rules to be marshalled, got %s", b)
+	}
+}
+
+func TestCannotUnmarshalInvalidMatchingRules(t *testing.T) {
+	var r Response
+	if err := json.Unmarshal([]byte(`{"status": 200, "matchingRules": {"body": {"items": {"matchers": [{"match": "type"}]}}}}`), &r); err == nil {
+		t.Error("expected invalid matching rule path error")
+	}
+}
+
+func TestCanUnmarshalRequestMatchingRules(t *testing.T) {
+	var r Request
+	if err := json.Unmarshal([]byte(`{"method": "POST", "path": "/orders", "body": {"id": 1}, "matchingRules": {"body": {"$.id": {"matchers": [{"match": "integer"}]}}}}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if rs := r.MatchingRules["body"]["$.id"]; rs == nil || rs.Matchers[0].Match != "integer" {
+		t.Error("expected integer matching rule for $.id")
+	}
+
+	b, err := json.Marshal(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"matchingRules"`) {
+		t.Errorf("expected matching rules to be marshalled, got %s", b)
+	}
+}
@@ -3,17 +3,28 @@ package provider
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/matchers"
 )
 
+var errQueryValueMsg = "Could not unmarshal request, the value of the query parameter '%s' is neither a string nor an array of strings"
+
 //Request provider request
 type Request struct {
-	Method     string
-	Path       string
-	Query      string
-	Headers    http.Header
+	Method  string
+	Path    string
+	Query   string
+	Headers http.Header
+	//MatchingRules are the rules the consumer mock service matches the requests it receives by, the verifier
+	//sends the recorded request as it is
+	MatchingRules matchers.MatchingRules
+	//Generators produce values of the request at verification time, e.g. a random id or the current date
+	Generators matchers.Generators
 	contentSet bool
 	httpContent
 }
@@ -28,6 +39,7 @@ func NewRequest(method, path, query string, headers http.Header) *Request {
 		//return error
 	}
 
+	path, query = splitQuery(path, query)
 	return &Request{
 		Method:  method,
 		Path:    path,
@@ -36,6 +48,42 @@ func NewRequest(method, path, query string, headers http.Header) *Request {
 	}
 }
 
+//splitQuery moves a query recorded inline in the path to the query, so the query is matched the same way
+//whichever way it was recorded
+func splitQuery(path, query string) (string, string) {
+	n := strings.Index(path, "?")
+	if n < 0 {
+		return path, query
+	}
+	inline := path[n+1:]
+	if query != "" && inline != "" {
+		inline += "&"
+	}
+	return path[:n], inline + query
+}
+
+//encodeQuery encodes the query recorded as an object of parameter names to a value or an array of values
+func encodeQuery(obj map[string]interface{}) (string, error) {
+	q := url.Values{}
+	for name, val := range obj {
+		switch v := val.(type) {
+		case string:
+			q.Add(name, v)
+		case []interface{}:
+			for _, e := range v {
+				s, ok := e.(string)
+				if !ok {
+					return "", fmt.Errorf(errQueryValueMsg, name)
+				}
+				q.Add(name, s)
+			}
+		default:
+			return "", fmt.Errorf(errQueryValueMsg, name)
+		}
+	}
+	return q.Encode(), nil
+}
+
 //NewJSONRequest creates new http request with content body as json
 func NewJSONRequest(method, path, query string, headers http.Header) *Request {
 	req := NewRequest(method, path, query, headers)
@@ -76,6 +124,12 @@ func (p *Request) MarshalJSON() ([]byte, error) {
 			obj["body"] = body
 		}
 	}
+	if len(p.MatchingRules) > 0 {
+		obj["matchingRules"] = p.MatchingRules
+	}
+	if len(p.Generators) > 0 {
+		obj["generators"] = p.Generators
+	}
 
 	return json.Marshal(obj)
 }
@@ -83,18 +137,12 @@ func (p *Request) MarshalJSON() ([]byte, error) {
 //UnmarshalJSON custom json unmarshalling
 func (p *Request) UnmarshalJSON(b []byte) error {
 	var obj map[string]interface{}
-	if err := json.Unmarshal(b, &obj); err != nil {
+	if err := DecodeJSON(b, &obj); err != nil {
 		return err
 	}
 
 	r := Request{}
 
-	if body, ok := obj["body"]; ok {
-		if err := r.SetBody(body); err != nil {
-			return err
-		}
-	}
-
 	if method, ok := obj["method"].(string); ok {
 		r.Method = method
 	} else {
@@ -107,9 +155,17 @@ func (p *Request) UnmarshalJSON(b []byte) error {
 		return errors.New("Could not unmarshal request, path value is either nil or not a string")
 	}
 
-	if query, ok := obj["query"].(string); ok {
+	switch query := obj["query"].(type) {
+	case string:
 		r.Query = query
+	case map[string]interface{}:
+		q, err := encodeQuery(query)
+		if err != nil {
+			return err
+		}
+		r.Query = q
 	}
+	r.Path, r.Query = splitQuery(r.Path, r.Query)
 
 	if headers, ok := obj["headers"].(map[string]interface{}); ok {
 		r.Headers = make(http.Header)
@@ -119,6 +175,30 @@ func (p *Request) UnmarshalJSON(b []byte) error {
 			}
 		}
 	}
+
+	if body, ok := obj["body"]; ok {
+		if data, ok := decodeBinaryBody(body, &r.Headers); ok {
+			body = data
+		}
+		if err := r.SetBody(body); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := obj["matchingRules"]; ok {
+		if err := unmarshalMatchingRules(b, &r.MatchingRules); err != nil {
+			return err
+		}
+	}
+	if _, ok := obj["generators"]; ok {
+		var gens struct {
+			Generators matchers.Generators `json:"generators"`
+		}
+		if err := json.Unmarshal(b, &gens); err != nil {
+			return err
+		}
+		r.Generators = gens.Generators
+	}
 	*p = Request(r)
 	return nil
 }
@@ -139,7 +219,7 @@ func CreateRequestFromHTTPRequest(httpReq *http.Request) (*Request, error) {
 				}
 			} else {
 				var body interface{}
-				if err = json.Unmarshal(data, &body); err != nil {
+				if err = DecodeJSON(data, &body); err != nil {
 					return nil, err
 				}
 				if err = req.SetBody(body); err != nil {
@@ -161,6 +241,15 @@ func (p *Request) BodyHasToBeSerialized() bool {
 	return p.contentSet
 }
 
+//DefaultContentType returns the content type the body is sent with when the request records no Content-Type
+//header, application/json for a json body. Other bodies are sent as they are without one
+func (p *Request) DefaultContentType() string {
+	if _, ok := p.httpContent.(*jsonContent); ok {
+		return jsonContentType
+	}
+	return ""
+}
+
 // GetData returns bytes from the content
 func (p *Request) GetData() ([]byte, error) {
 	if p.HasContent() {
@@ -189,6 +278,8 @@ func (p *Request) SetBody(body interface{}) error {
 		switch body.(type) {
 		case string:
 			p.httpContent = &plainTextContent{}
+		case []byte:
+			p.httpContent = &binaryContent{}
 		default:
 			p.httpContent = &jsonContent{}
 		}
@@ -5,14 +5,16 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
-	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/matchers"
 )
 
 //Response provider response
 type Response struct {
-	Status     int
-	Headers    http.Header
-	contentSet bool
+	Status        int
+	Headers       http.Header
+	MatchingRules matchers.MatchingRules
+	contentSet    bool
 	httpContent
 }
 
@@ -60,30 +62,28 @@ func (p *Response) MarshalJSON() ([]byte, error) {
 			obj["body"] = body
 		}
 	}
+	if len(p.MatchingRules) > 0 {
+		obj["matchingRules"] = p.MatchingRules
+	}
 	return json.Marshal(obj)
 }
 
 //UnmarshalJSON custom json unmarshalling
 func (p *Response) UnmarshalJSON(b []byte) error {
 	var obj map[string]interface{}
-	if err := json.Unmarshal(b, &obj); err != nil {
+	if err := DecodeJSON(b, &obj); err != nil {
 		return err
 	}
 
 	r := Response{}
-	if body, ok := obj["body"]; ok {
-		if err := r.SetBody(body); err != nil {
-			return err
-		}
-	}
 
 	if val, ok := obj["status"]; ok {
-		//default number deserialised as float64
-		if status, ok := val.(float64); ok {
-			r.Status = int(status)
-		} else {
+		n, ok := val.(json.Number)
+		status, err := n.Float64()
+		if !ok || err != nil {
 			return errors.New("Could not unmarshal response, status value is either nil or not a int")
 		}
+		r.Status = int(status)
 	}
 
 	if headers, ok := obj["headers"].(map[string]interface{}); ok {
@@ -95,6 +95,21 @@ func (p *Response) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	if body, ok := obj["body"]; ok {
+		if data, ok := decodeBinaryBody(body, &r.Headers); ok {
+			body = data
+		}
+		if err := r.SetBody(body); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := obj["matchingRules"]; ok {
+		if err := unmarshalMatchingRules(b, &r.MatchingRules); err != nil {
+			return err
+		}
+	}
+
 	*p = Response(r)
 	return nil
 }
@@ -103,19 +118,32 @@ func (p *Response) UnmarshalJSON(b []byte) error {
 func CreateResponseFromHTTPResponse(httpResp *http.Response) (*Response, error) {
 	resp := NewResponse(httpResp.StatusCode, httpResp.Header)
 
+	// the body of a switching protocols response is the upgraded connection, it is not read as it is only closed
+	// by the peer, so only the status and headers are matched
+	if httpResp.StatusCode == http.StatusSwitchingProtocols {
+		if httpResp.Body != nil {
+			httpResp.Body.Close()
+		}
+		return resp, nil
+	}
+
 	if httpResp.Body != nil {
 		data, err := ioutil.ReadAll(httpResp.Body)
 		if err != nil {
 			return nil, err
 		}
 		if len(data) > 0 {
-			if strings.Contains(httpResp.Header.Get("Content-Type"), "text/plain") {
+			if isTextContentType(httpResp.Header.Get("Content-Type")) {
 				if err = resp.SetBody(string(data)); err != nil {
 					return nil, err
 				}
+			} else if isBinaryContentType(httpResp.Header.Get("Content-Type")) {
+				if err = resp.SetBody(data); err != nil {
+					return nil, err
+				}
 			} else {
 				var body interface{}
-				if err = json.Unmarshal(data, &body); err != nil {
+				if err = DecodeJSON(data, &body); err != nil {
 					return nil, err
 				}
 				if err = resp.SetBody(body); err != nil {
@@ -164,6 +192,8 @@ func (p *Response) SetBody(body interface{}) error {
 		switch body.(type) {
 		case string:
 			p.httpContent = &plainTextContent{}
+		case []byte:
+			p.httpContent = &binaryContent{}
 		default:
 			p.httpContent = &jsonContent{}
 		}
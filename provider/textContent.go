@@ -1,6 +1,10 @@
 package provider
 
-import "errors"
+import (
+	"errors"
+	"mime"
+	"strings"
+)
 
 type plainTextContent struct {
 	data string
@@ -21,3 +25,13 @@ func (c *plainTextContent) SetBody(content interface{}) error {
 	}
 	return errors.New("content is not valid text")
 }
+
+//isTextContentType returns true for media types whose body is read as text e.g. text/plain, multipart and xml
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.Contains(contentType, "text/plain")
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasPrefix(mediaType, "multipart/") ||
+		mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml")
+}
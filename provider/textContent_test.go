@@ -17,3 +17,19 @@ func TestCannotSetNonTextContent(t *testing.T) {
 		t.Error("expected to not fail in setting non text value")
 	}
 }
+
+func TestIsTextContentType(t *testing.T) {
+	for ct, expected := range map[string]bool{
+		"text/plain; charset=utf-8": true,
+		"text/html":                 true,
+		"application/xml":           true,
+		"application/atom+xml":      true,
+		"application/json":          false,
+		"image/png":                 false,
+		"":                          false,
+	} {
+		if isTextContentType(ct) != expected {
+			t.Errorf("expected %q to be text content %v", ct, expected)
+		}
+	}
+}
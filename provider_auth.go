@@ -0,0 +1,49 @@
+package pact
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//ProviderAuth obtains the headers, e.g. an authorization token, attached to the requests sent to the provider.
+//The client is the provider client, which the hook can use to login
+type ProviderAuth func(client *http.Client) (http.Header, error)
+
+var errProviderAuthMsg = "The provider auth hook failed: %s"
+
+//authCache holds the headers obtained by the provider auth hook during a verification, the interactions verified
+//concurrently share them
+type authCache struct {
+	mu        sync.Mutex
+	header    http.Header
+	fetchedAt time.Time
+}
+
+//authHeader returns the headers of the provider auth hook, they are cached for the verification
+//unless they expire after the auth ttl
+func (v *pactValidator) authHeader() (http.Header, error) {
+	if v.opts.auth == nil {
+		return nil, nil
+	}
+	if v.opts.authCache == nil {
+		v.opts.authCache = &authCache{}
+	}
+
+	c := v.opts.authCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.header != nil && (v.opts.authTTL <= 0 || time.Since(c.fetchedAt) < v.opts.authTTL) {
+		return c.header, nil
+	}
+	h, err := v.opts.auth(v.c)
+	if err != nil {
+		return nil, fmt.Errorf(errProviderAuthMsg, err)
+	}
+	if h == nil {
+		h = http.Header{}
+	}
+	c.header, c.fetchedAt = h, time.Now()
+	return h, nil
+}
@@ -0,0 +1,75 @@
+package pact
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/util"
+)
+
+var errProviderNotReadyMsg = "The provider is not ready, %s did not respond with a 2xx status within %s: %s"
+
+//healthCheck is the endpoint polled until the provider is ready, before its interactions are verified
+type healthCheck struct {
+	path     string
+	timeout  time.Duration
+	interval time.Duration
+}
+
+//waitForProvider polls the health endpoint of the provider until it responds with a 2xx status, failing
+//once the timeout of the health check elapses
+func (v *pactValidator) waitForProvider(ctx context.Context, hc *healthCheck) error {
+	u, err := healthURL(v.u, hc.path)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hc.timeout)
+	defer cancel()
+	for {
+		status, err := v.checkHealth(ctx, u)
+		if err == nil && status >= 200 && status < 300 {
+			return nil
+		}
+
+		reason := fmt.Sprintf("the last status was %d", status)
+		if err != nil {
+			reason = fmt.Sprintf("the last error was %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(errProviderNotReadyMsg, u, hc.timeout, reason)
+		case <-time.After(hc.interval):
+		}
+	}
+}
+
+func (v *pactValidator) checkHealth(ctx context.Context, u *url.URL) (int, error) {
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", util.UserAgent(v.opts.userAgent))
+	resp, err := v.c.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+//healthURL returns the url of the health path relative to the base path of the provider url
+func healthURL(base *url.URL, path string) (*url.URL, error) {
+	p, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	u := *base
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + strings.TrimLeft(p.Path, "/")
+	u.RawQuery = p.RawQuery
+	return &u, nil
+}
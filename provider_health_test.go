@@ -0,0 +1,70 @@
+package pact
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Verifier_WaitForProvider_WaitsUntilHealthy(t *testing.T) {
+	ready := time.Now().Add(150 * time.Millisecond)
+	var checks int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&checks, 1)
+		if time.Now().Before(ready) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if time.Now().Before(ready) {
+			t.Error("expected the interactions to be verified once the provider is healthy")
+		}
+		userHandlerWithValidData(w, r)
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		WaitForProvider("/health", 2*time.Second, 20*time.Millisecond).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&checks) < 2 {
+		t.Errorf("expected the health path to be polled until healthy, got %d checks", checks)
+	}
+}
+
+func Test_Verifier_WaitForProvider_FailsWhenNotReady(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL + "/api")
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		WaitForProvider("healthz?deep=true", 100*time.Millisecond, 20*time.Millisecond).
+		SummaryWriter(ioutil.Discard)
+	err := v.Verify()
+	if err == nil || !strings.Contains(err.Error(), "The provider is not ready, "+s.URL+"/api/healthz?deep=true did not respond") ||
+		!strings.Contains(err.Error(), "the last status was 503") {
+		t.Errorf("expected a provider not ready error, got %v", err)
+	}
+}
@@ -0,0 +1,346 @@
+package pact
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/util"
+)
+
+//ReportFormat is the format of the report written by ReportFile
+type ReportFormat int
+
+const (
+	//JUnitXML is the junit xml report of WriteJUnitReport, with a test case per interaction for the test panels of CI
+	JUnitXML ReportFormat = iota
+	//JSONReport is the json report of WriteJSONReport
+	JSONReport
+)
+
+var errUnknownReportFormatMsg = "Unknown report format %d."
+
+const (
+	statusPassed          = "passed"
+	statusFailed          = "failed"
+	statusExpectedFailure = "expected failure"
+	statusSkipped         = "skipped"
+)
+
+type jsonReport struct {
+	Consumer     string                   `json:"consumer"`
+	Provider     string                   `json:"provider"`
+	Passed       int                      `json:"passed"`
+	Failed       int                      `json:"failed"`
+	Skipped      int                      `json:"skipped"`
+	Notes        []string                 `json:"notes,omitempty"`
+	Smoke        bool                     `json:"smoke,omitempty"`
+	Interactions []*jsonInteractionReport `json:"interactions"`
+	Explorations []*jsonExplorationReport `json:"explorations,omitempty"`
+}
+
+type jsonExplorationReport struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status,omitempty"`
+	Unexpected bool   `json:"unexpected"`
+	Error      string `json:"error,omitempty"`
+}
+
+type jsonInteractionReport struct {
+	Consumer      string       `json:"consumer"`
+	Pact          string       `json:"pact"`
+	PactOrigin    string       `json:"pactOrigin,omitempty"`
+	PactSpec      string       `json:"pactSpecification,omitempty"`
+	ProviderURL   string       `json:"providerUrl,omitempty"`
+	Description   string       `json:"description"`
+	ProviderState string       `json:"providerState,omitempty"`
+	TestName      string       `json:"testName,omitempty"`
+	Comments      []string     `json:"comments,omitempty"`
+	Location      string       `json:"location,omitempty"`
+	Status        string       `json:"status"`
+	Reason        string       `json:"reason,omitempty"`
+	ServerError   string       `json:"serverError,omitempty"`
+	CorrelationID string       `json:"correlationId,omitempty"`
+	LatencyMs     float64      `json:"latencyMs"`
+	Mismatches    []string     `json:"mismatches,omitempty"`
+	RuleMatches   []string     `json:"ruleMatches,omitempty"`
+	Timings       *jsonTimings `json:"timings,omitempty"`
+}
+
+//jsonTimings are the durations of the stages of an interaction verification in milliseconds
+type jsonTimings struct {
+	SetupMs    float64 `json:"setupMs"`
+	RequestMs  float64 `json:"requestMs"`
+	MatchingMs float64 `json:"matchingMs"`
+	TeardownMs float64 `json:"teardownMs"`
+}
+
+//brokerResults is the verification result in the shape the pact broker publishes it
+type brokerResults struct {
+	Success         bool                  `json:"success"`
+	ProviderVersion string                `json:"providerApplicationVersion"`
+	BuildURL        string                `json:"buildUrl,omitempty"`
+	TestResults     []*brokerTestResult   `json:"testResults"`
+	VerifiedBy      *brokerImplementation `json:"verifiedBy"`
+}
+
+type brokerTestResult struct {
+	Consumer      string            `json:"consumer"`
+	Description   string            `json:"interactionDescription"`
+	ProviderState string            `json:"providerState,omitempty"`
+	Success       bool              `json:"success"`
+	Mismatches    []*brokerMismatch `json:"mismatches,omitempty"`
+}
+
+type brokerMismatch struct {
+	Description string `json:"description"`
+}
+
+type brokerImplementation struct {
+	Implementation string `json:"implementation"`
+	Version        string `json:"version"`
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func (i *InteractionResult) status() string {
+	if i.Skipped {
+		return statusSkipped
+	} else if i.Failed() {
+		return statusFailed
+	} else if i.ExpectedFailure {
+		return statusExpectedFailure
+	}
+	return statusPassed
+}
+
+func (i *InteractionResult) location() string {
+	if i.Location == nil {
+		return ""
+	}
+	return i.Location.String()
+}
+
+//notes describes the consumer test and comments of the interaction, a line each
+func (i *InteractionResult) notes() string {
+	var n []string
+	if i.TestName != "" {
+		n = append(n, fmt.Sprintf("consumer test: %s", i.TestName))
+	}
+	n = append(n, i.Comments...)
+	if i.CorrelationID != "" {
+		n = append(n, fmt.Sprintf("correlation id: %s", i.CorrelationID))
+	}
+	return strings.Join(n, "\n")
+}
+
+func (i *InteractionResult) ruleMatches() []string {
+	var m []string
+	for _, r := range i.RuleMatches {
+		m = append(m, r.String())
+	}
+	return m
+}
+
+func (i *InteractionResult) timings() *jsonTimings {
+	if i.Timings == nil {
+		return nil
+	}
+	ms := func(d time.Duration) float64 { return d.Seconds() * 1000 }
+	return &jsonTimings{SetupMs: ms(i.Timings.Setup), RequestMs: ms(i.Timings.Request), MatchingMs: ms(i.Timings.Matching), TeardownMs: ms(i.Timings.Teardown)}
+}
+
+func (i *InteractionResult) mismatches() []string {
+	var m []string
+	for _, d := range i.Differences {
+		m = append(m, d.String())
+	}
+	if i.Error != nil {
+		m = append(m, i.Error.Error())
+	}
+	if i.ExpectedFailure && i.Matched() {
+		m = append(m, strings.TrimSpace(summaryUnexpectedPass))
+	}
+	return m
+}
+
+//failureDetails describes the failure of the interaction by the part and path of its mismatches, followed by its error
+func (i *InteractionResult) failureDetails() []string {
+	var m []string
+	for _, f := range i.Mismatches() {
+		m = append(m, f.detail())
+	}
+	return append(m, i.mismatches()[len(i.Differences):]...)
+}
+
+//WriteJSONReport writes the result as a json report covering the interactions of every verified pact
+func (r *VerificationResult) WriteJSONReport(w io.Writer) error {
+	failed, skipped := len(r.Failures()), len(r.Skipped())
+	report := &jsonReport{
+		Consumer:     r.Consumer,
+		Provider:     r.Provider,
+		Passed:       len(r.Interactions) - failed - skipped,
+		Failed:       failed,
+		Skipped:      skipped,
+		Notes:        r.Notes,
+		Smoke:        r.Smoke,
+		Interactions: make([]*jsonInteractionReport, len(r.Interactions)),
+	}
+	for n, i := range r.Interactions {
+		report.Interactions[n] = &jsonInteractionReport{
+			Consumer:      i.Consumer,
+			Pact:          i.PactUri,
+			PactOrigin:    i.PactOrigin,
+			PactSpec:      i.PactSpecVersion,
+			ProviderURL:   i.ProviderURL,
+			Description:   i.Description,
+			ProviderState: i.State,
+			TestName:      i.TestName,
+			Comments:      i.Comments,
+			Location:      i.location(),
+			Status:        i.status(),
+			Reason:        i.Reason.String(),
+			ServerError:   i.ServerError,
+			CorrelationID: i.CorrelationID,
+			LatencyMs:     i.Latency.Seconds() * 1000,
+			Mismatches:    i.mismatches(),
+			RuleMatches:   i.ruleMatches(),
+			Timings:       i.timings(),
+		}
+	}
+
+	for _, e := range r.Explorations {
+		er := &jsonExplorationReport{Method: e.Method, Path: e.Path, Status: e.Status, Unexpected: e.Unexpected}
+		if e.Error != nil {
+			er.Error = e.Error.Error()
+		}
+		report.Explorations = append(report.Explorations, er)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(report)
+}
+
+//WriteBrokerResults writes the result as the verification results the pact broker publishes, for the provider
+//version and the url of the build which verified it. The skipped interactions are not part of the results
+func (r *VerificationResult) WriteBrokerResults(w io.Writer, providerVersion, buildURL string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(r.brokerResults(providerVersion, buildURL))
+}
+
+//brokerResults returns the verification results the pact broker publishes, without the skipped interactions
+func (r *VerificationResult) brokerResults(providerVersion, buildURL string) *brokerResults {
+	results := &brokerResults{
+		Success:         len(r.Failures()) == 0,
+		ProviderVersion: providerVersion,
+		BuildURL:        buildURL,
+		TestResults:     []*brokerTestResult{},
+		VerifiedBy:      &brokerImplementation{Implementation: "pact-go", Version: util.Version},
+	}
+	for _, i := range r.Interactions {
+		if i.Skipped {
+			continue
+		}
+		tr := &brokerTestResult{Consumer: i.Consumer, Description: i.Description, ProviderState: i.State, Success: !i.Failed()}
+		if i.Failed() {
+			for _, m := range i.mismatches() {
+				tr.Mismatches = append(tr.Mismatches, &brokerMismatch{Description: m})
+			}
+		}
+		results.TestResults = append(results.TestResults, tr)
+	}
+	return results
+}
+
+//WriteJUnitReport writes the result as a junit xml report with a test suite for the pact of every consumer
+func (r *VerificationResult) WriteJUnitReport(w io.Writer) error {
+	var suites junitTestSuites
+	bySuite := make(map[string]*junitTestSuite)
+	for _, i := range r.Interactions {
+		name := fmt.Sprintf("%s-%s", i.Consumer, r.Provider)
+		s, ok := bySuite[name]
+		if !ok {
+			s = &junitTestSuite{Name: name}
+			bySuite[name] = s
+			suites.Suites = append(suites.Suites, s)
+		}
+
+		caseName := i.Description
+		if i.State != "" {
+			caseName += fmt.Sprintf(summaryStateMsg, i.State)
+		}
+		c := &junitTestCase{
+			Name:      caseName,
+			ClassName: i.Consumer,
+			Time:      fmt.Sprintf("%.3f", i.Latency.Seconds()),
+			SystemOut: i.notes(),
+		}
+		if i.Failed() {
+			m := i.failureDetails()
+			c.Failure = &junitFailure{Message: m[0], Content: strings.Join(m, "\n")}
+			s.Failures++
+		} else if i.Skipped {
+			c.Skipped = &junitSkipped{Message: statusSkipped}
+			s.Skipped++
+		}
+		s.Tests++
+		s.Cases = append(s.Cases, c)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(&suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+//writeReport writes the result as a report of the format
+func (r *VerificationResult) writeReport(w io.Writer, format ReportFormat) error {
+	switch format {
+	case JUnitXML:
+		return r.WriteJUnitReport(w)
+	case JSONReport:
+		return r.WriteJSONReport(w)
+	}
+	return fmt.Errorf(errUnknownReportFormatMsg, format)
+}
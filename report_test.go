@@ -0,0 +1,128 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func testReportResult() *VerificationResult {
+	return &VerificationResult{Consumer: "android app, chrome browser", Provider: "go api", Interactions: []*InteractionResult{
+		{Consumer: "android app", PactUri: "android_app-go_api.json", ProviderURL: "http://green.local", Description: "first", State: "a user", TestName: "TestGetUser", Comments: []string{"uses the v2 api"}, Latency: 15 * time.Millisecond,
+			Timings: &InteractionTimings{Setup: 5 * time.Millisecond, Request: 15 * time.Millisecond, Matching: time.Millisecond, Teardown: 2 * time.Millisecond}},
+		{Consumer: "chrome browser", PactUri: "chrome_browser-go_api.json", Description: "second", Differences: testDifferences("x", "y")},
+		{Consumer: "chrome browser", PactUri: "chrome_browser-go_api.json", Description: "third", ExpectedFailure: true, Differences: testDifferences("x", "y")},
+		{Consumer: "chrome browser", PactUri: "chrome_browser-go_api.json", Description: "fourth", Skipped: true},
+	}, Notes: []string{"verified the changed interactions"}}
+}
+
+func Test_Report_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReportResult().WriteJSONReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var report jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Provider != "go api" || report.Passed != 2 || report.Failed != 1 || report.Skipped != 1 || len(report.Interactions) != 4 {
+		t.Errorf("unexpected report totals %#v", report)
+	}
+
+	first, second, third := report.Interactions[0], report.Interactions[1], report.Interactions[2]
+	if first.Consumer != "android app" || first.Pact != "android_app-go_api.json" || first.ProviderURL != "http://green.local" || first.Status != statusPassed || first.LatencyMs != 15 {
+		t.Errorf("unexpected first interaction %#v", first)
+	}
+	if first.Timings == nil || first.Timings.SetupMs != 5 || first.Timings.RequestMs != 15 || first.Timings.MatchingMs != 1 || first.Timings.TeardownMs != 2 {
+		t.Errorf("expected the timings of the first interaction, got %#v", first.Timings)
+	}
+	if report.Interactions[1].Timings != nil {
+		t.Errorf("expected no timings when they were not recorded, got %#v", report.Interactions[1].Timings)
+	}
+	if first.TestName != "TestGetUser" || len(first.Comments) != 1 || first.Comments[0] != "uses the v2 api" {
+		t.Errorf("expected the consumer test and comments of the first interaction, got %#v", first)
+	}
+	if second.Status != statusFailed || len(second.Mismatches) != 1 {
+		t.Errorf("unexpected second interaction %#v", second)
+	}
+	if third.Status != statusExpectedFailure {
+		t.Errorf("unexpected third interaction %#v", third)
+	}
+	if fourth := report.Interactions[3]; fourth.Status != statusSkipped {
+		t.Errorf("unexpected fourth interaction %#v", fourth)
+	}
+	if len(report.Notes) != 1 || report.Notes[0] != "verified the changed interactions" {
+		t.Errorf("expected the notes of the result, got %v", report.Notes)
+	}
+}
+
+func Test_Report_JUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReportResult().WriteJUnitReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Suites) != 2 {
+		t.Fatalf("expected a suite per consumer, got %d", len(report.Suites))
+	}
+	android, chrome := report.Suites[0], report.Suites[1]
+	if android.Name != "android app-go api" || android.Tests != 1 || android.Failures != 0 {
+		t.Errorf("unexpected android suite %#v", android)
+	}
+	if name := android.Cases[0].Name; name != "first given a user" {
+		t.Errorf("expected the test case to be named by the description and the state, got %s", name)
+	}
+	if out := android.Cases[0].SystemOut; out != "consumer test: TestGetUser\nuses the v2 api" {
+		t.Errorf("expected the consumer test and comments in the system out, got %q", out)
+	}
+	if chrome.Name != "chrome browser-go api" || chrome.Tests != 3 || chrome.Failures != 1 || chrome.Skipped != 1 {
+		t.Errorf("unexpected chrome suite %#v", chrome)
+	}
+	if c := chrome.Cases[0]; c.Name != "second" || c.Failure == nil || c.Failure.Message != `body path $ expected "x" got "y"` {
+		t.Errorf("expected the second interaction to have a failure, got %#v", c)
+	}
+	if chrome.Cases[1].Failure != nil {
+		t.Error("expected the expected failure not to be reported as a failure")
+	}
+	if c := chrome.Cases[2]; c.Failure != nil || c.Skipped == nil {
+		t.Errorf("expected the fourth interaction to be skipped, got %#v", c)
+	}
+}
+
+func Test_Report_BrokerResults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReportResult().WriteBrokerResults(&buf, "1.2.3", "https://ci.example.com/builds/42"); err != nil {
+		t.Fatal(err)
+	}
+
+	var results brokerResults
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatal(err)
+	}
+	if results.Success || results.ProviderVersion != "1.2.3" || results.BuildURL != "https://ci.example.com/builds/42" {
+		t.Errorf("unexpected results %#v", results)
+	}
+	if results.VerifiedBy == nil || results.VerifiedBy.Implementation != "pact-go" {
+		t.Errorf("expected the results to be verified by pact-go, got %#v", results.VerifiedBy)
+	}
+	if len(results.TestResults) != 3 {
+		t.Fatalf("expected a test result per verified interaction, got %d", len(results.TestResults))
+	}
+	first, second, third := results.TestResults[0], results.TestResults[1], results.TestResults[2]
+	if !first.Success || first.Description != "first" || first.ProviderState != "a user" || first.Consumer != "android app" {
+		t.Errorf("unexpected first test result %#v", first)
+	}
+	if second.Success || len(second.Mismatches) != 1 || second.Mismatches[0].Description == "" {
+		t.Errorf("expected the mismatch of the second interaction, got %#v", second)
+	}
+	if !third.Success || len(third.Mismatches) != 0 {
+		t.Errorf("expected the expected failure to succeed, got %#v", third)
+	}
+}
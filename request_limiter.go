@@ -0,0 +1,56 @@
+package pact
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//requestLimiter spaces the requests sent to the provider by the interval, it limits the aggregate rate of the
+//requests however many are sent at once
+type requestLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	//next is when the next request may be sent
+	next time.Time
+	//now and sleep are the clock of the limiter, replaced by the tests
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRequestLimiter(interval time.Duration) *requestLimiter {
+	return &requestLimiter{interval: interval, now: time.Now, sleep: sleepContext}
+}
+
+//wait blocks until the request may be sent, or returns the error of the context when it is cancelled first
+func (l *requestLimiter) wait(ctx context.Context) error {
+	if l == nil || l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	d := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+	return l.sleep(ctx, d)
+}
+
+//sleepContext waits for the delay unless the context is cancelled first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
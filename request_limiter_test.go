@@ -0,0 +1,95 @@
+package pact
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+//fakeClock stands in for the clock of a limiter, the sleeps are recorded and advance the time when advance is set
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	advance bool
+	slept   []time.Duration
+}
+
+func newFakeClockLimiter(interval time.Duration, c *fakeClock) *requestLimiter {
+	l := newRequestLimiter(interval)
+	l.now = func() time.Time {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.now
+	}
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.slept = append(c.slept, d)
+		if c.advance {
+			c.now = c.now.Add(d)
+		}
+		return nil
+	}
+	return l
+}
+
+func Test_RequestLimiter_SpacesConcurrentRequests(t *testing.T) {
+	interval := 20 * time.Millisecond
+	c := &fakeClock{now: time.Unix(0, 0)}
+	l := newFakeClockLimiter(interval, c)
+
+	//the requests all arrive at once, so each waits an interval more than the one before it
+	for i := 0; i < 4; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []time.Duration{interval, 2 * interval, 3 * interval}
+	if !reflect.DeepEqual(c.slept, expected) {
+		t.Errorf("expected the waits %v, got %v", expected, c.slept)
+	}
+}
+
+func Test_RequestLimiter_SpacesSequentialRequests(t *testing.T) {
+	interval := 20 * time.Millisecond
+	c := &fakeClock{now: time.Unix(0, 0), advance: true}
+	l := newFakeClockLimiter(interval, c)
+
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		c.now = c.now.Add(5 * time.Millisecond)
+	}
+	//a request sent after the interval has passed is not delayed
+	c.now = c.now.Add(time.Second)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []time.Duration{interval - 5*time.Millisecond, interval - 5*time.Millisecond}
+	if !reflect.DeepEqual(c.slept, expected) {
+		t.Errorf("expected the waits %v, got %v", expected, c.slept)
+	}
+}
+
+func Test_RequestLimiter_ReturnsErrorWhenCancelled(t *testing.T) {
+	l := newRequestLimiter(time.Hour)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func Test_RequestLimiter_NilDoesNotWait(t *testing.T) {
+	var l *requestLimiter
+	if err := l.wait(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,379 @@
+package pact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+	"github.com/SEEK-Jobs/pact-go/diff"
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+//VerificationResult holds the outcome of each interaction verified with the provider, in the order of the
+//pacts and their interactions
+type VerificationResult struct {
+	//Consumer is the name of the consumer, the names are comma separated when multiple consumers are verified
+	Consumer     string
+	Provider     string
+	Interactions []*InteractionResult
+	//Notes describe how the interactions were selected, e.g. those verified by VerifyChangesSince
+	Notes []string
+	//Smoke is set when the verification ran in smoke mode, the response bodies were not verified
+	Smoke bool
+	//BrokerMetadata is the verification metadata the pact broker returned for the pacts it provided
+	BrokerMetadata []*PactMetadata
+	//Explorations are the responses of the endpoints probed by ExploreEndpoints, separate from the interactions
+	Explorations []*ExplorationResult
+}
+
+//PactMetadata is the verification metadata the pact broker returned for a verified pact, e.g. its notices
+type PactMetadata struct {
+	//PactUri is the uri of the pact
+	PactUri string
+	io.BrokerPactMetadata
+}
+
+//InteractionResult holds the outcome of verifying a single interaction
+type InteractionResult struct {
+	//Consumer is the consumer of the pact the interaction is from
+	Consumer string
+	//PactUri is the uri of the pact the interaction is from
+	PactUri string
+	//PactOrigin is where the pact the interaction is from was read from, e.g. PactOriginBroker
+	PactOrigin string
+	//PactSpecVersion is the pact specification version of the pact the interaction is from
+	PactSpecVersion string
+	//ProviderURL is the url of the provider the interaction was verified against
+	ProviderURL string
+	//Location is where the interaction is in its pact
+	Location    *consumer.Location
+	Description string
+	State       string
+	//TestName is the consumer test which generated the interaction, empty when the pact does not record it
+	TestName string
+	//Comments are the notes recorded with the interaction in the pact
+	Comments        []string
+	ExpectedFailure bool
+	//Skipped is true when the interaction is flagged as pending or skip, its request was not sent
+	Skipped     bool
+	Differences diff.Differences
+	//RuleMatches are the body values which matched by a matching rule, recorded when ExplainMatches is set
+	RuleMatches []*diff.RuleMatch
+	//Latency is the time taken by the provider to respond to the interaction request
+	Latency time.Duration
+	//Timings are the durations of the stages of the verification, recorded when DetailedTimings is set
+	Timings *InteractionTimings
+	//Reason is the category of the most significant failure of the interaction
+	Reason FailureReason
+	//ServerError is the body, truncated, of an unexpected 5xx response of the provider
+	ServerError string
+	//CorrelationID is the id the requests of the interaction were sent with, set when CorrelationID is set
+	CorrelationID string
+	//Error is the error of the state setup or the request which stopped the verification at the interaction
+	Error error
+}
+
+//FailureReason is the category of the failure of an interaction, e.g. to count the kinds of contract breaks
+type FailureReason int
+
+const (
+	//NoFailure is the reason of the interactions which matched or were skipped
+	NoFailure FailureReason = iota
+	StatusMismatch
+	HeaderMismatch
+	BodyMismatch
+	//RequestError is a request which could not be sent to the provider or whose response could not be read
+	RequestError
+	StateSetupError
+	//Timeout is a request which timed out or a response slower than the maximum latency
+	Timeout
+	//OtherFailure is any other failure, e.g. a panic of the verification
+	OtherFailure
+)
+
+var failureReasons = map[FailureReason]string{
+	NoFailure:       "",
+	StatusMismatch:  "statusMismatch",
+	HeaderMismatch:  "headerMismatch",
+	BodyMismatch:    "bodyMismatch",
+	RequestError:    "requestError",
+	StateSetupError: "stateSetupError",
+	Timeout:         "timeout",
+	OtherFailure:    "otherFailure",
+}
+
+func (r FailureReason) String() string {
+	return failureReasons[r]
+}
+
+//mismatchReason returns the reason of the first mismatch, the mismatches are in the order of significance
+func mismatchReason(diffs diff.Differences) FailureReason {
+	if len(diffs) == 0 {
+		return NoFailure
+	}
+	switch p := diffs[0].Path(); {
+	case strings.HasPrefix(p, "[\"status\"]"):
+		return StatusMismatch
+	case strings.HasPrefix(p, "[\"header\"]"):
+		return HeaderMismatch
+	case strings.HasPrefix(p, "[\"body\"]"):
+		return BodyMismatch
+	case strings.HasPrefix(p, "[\"latency\"]"):
+		return Timeout
+	}
+	return OtherFailure
+}
+
+//requestReason returns the reason of the error of the request to the provider
+func requestReason(err error) FailureReason {
+	var ne net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &ne) && ne.Timeout()) {
+		return Timeout
+	}
+	return RequestError
+}
+
+//InteractionTimings are the durations of the stages of an interaction verification
+type InteractionTimings struct {
+	//Setup is the duration of the default setup and the provider state setups
+	Setup time.Duration
+	//Request is the round trip of the request to the provider, the same as the latency
+	Request time.Duration
+	//Matching is the duration of matching the provider response against the expected response
+	Matching time.Duration
+	//Teardown is the duration of the provider state teardowns, the AfterInteraction hook and the default teardown
+	Teardown time.Duration
+}
+
+func newVerificationResult(provider string, pacts []*loadedPact) *VerificationResult {
+	var consumers []string
+	seen := make(map[string]bool)
+	for _, p := range pacts {
+		if name := p.file.Consumer.Name; !seen[name] {
+			seen[name] = true
+			consumers = append(consumers, name)
+		}
+	}
+	r := &VerificationResult{Consumer: strings.Join(consumers, ", "), Provider: provider}
+	for _, p := range pacts {
+		if p.metadata != nil {
+			r.BrokerMetadata = append(r.BrokerMetadata, &PactMetadata{PactUri: p.uri, BrokerPactMetadata: *p.metadata})
+		}
+	}
+	return r
+}
+
+//add appends the interactions verified for the pact
+func (r *VerificationResult) add(p *loadedPact, pr *VerificationResult) {
+	if pr == nil {
+		return
+	}
+	for _, i := range pr.Interactions {
+		i.Consumer = p.file.Consumer.Name
+		i.PactUri = p.uri
+		i.PactOrigin = p.origin
+		i.PactSpecVersion = p.file.SpecVersion()
+		r.Interactions = append(r.Interactions, i)
+	}
+}
+
+//Matched reports whether the provider response matched the interaction
+func (r *InteractionResult) Matched() bool {
+	return len(r.Differences) == 0 && r.Error == nil
+}
+
+//Failed reports whether the interaction fails the verification, an expected failure which
+//matched is treated as a failure. A skipped interaction neither passes nor fails
+func (r *InteractionResult) Failed() bool {
+	return !r.Skipped && r.Matched() == r.ExpectedFailure
+}
+
+//Failures returns the interactions which failed the verification
+func (r *VerificationResult) Failures() []*InteractionResult {
+	var f []*InteractionResult
+	for _, i := range r.Interactions {
+		if i.Failed() {
+			f = append(f, i)
+		}
+	}
+	return f
+}
+
+//Skipped returns the interactions which were skipped by the verification
+func (r *VerificationResult) Skipped() []*InteractionResult {
+	var s []*InteractionResult
+	for _, i := range r.Interactions {
+		if i.Skipped {
+			s = append(s, i)
+		}
+	}
+	return s
+}
+
+//FieldMismatch is a mismatch of a failed interaction at the status, a header or a path of the body
+type FieldMismatch struct {
+	//Interaction is the description of the interaction
+	Interaction string
+	//Consumer and PactUri are the consumer and the uri of the pact the interaction is from
+	Consumer string
+	PactUri  string
+	//Part is the part of the response which mismatched, e.g. status, header or body
+	Part string
+	//Path is the name of a mismatched header, or the json path of a mismatched body value e.g. $.firstName
+	Path     string
+	Expected interface{}
+	Actual   interface{}
+	//Description describes how the values mismatched, e.g. field id not found
+	Description string
+	unequal     bool
+}
+
+var (
+	fieldMismatchMsg = "interaction '%s': %s"
+	valueMismatchMsg = "%s expected %s got %s"
+	bodyPathMsg      = "%s path %s"
+
+	mismatchPathSegment = regexp.MustCompile(`\[("(?:[^"\\]|\\.)*"|[^\]]*)\]`)
+)
+
+func (f *FieldMismatch) String() string {
+	return fmt.Sprintf(fieldMismatchMsg, f.Interaction, f.detail())
+}
+
+//detail describes the mismatch without its interaction, e.g. body path $.firstName expected "John" got "Jane"
+func (f *FieldMismatch) detail() string {
+	at := f.Part
+	if f.Path != "" && f.Part == "body" {
+		at = fmt.Sprintf(bodyPathMsg, f.Part, f.Path)
+	} else if f.Path != "" {
+		at = strings.TrimSpace(f.Part + " " + f.Path)
+	}
+	if f.unequal {
+		return fmt.Sprintf(valueMismatchMsg, at, mismatchValue(f.Expected), mismatchValue(f.Actual))
+	}
+	return at + ": " + f.Description
+}
+
+//mismatchValue formats the value as json, e.g. "John" for a string
+func mismatchValue(v interface{}) string {
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+//newFieldMismatch splits the path of the mismatch, e.g. ["body"]["items"][0]["id"], into the part and the header
+//name or json path of the body
+func newFieldMismatch(r *InteractionResult, m *diff.Mismatch) *FieldMismatch {
+	f := &FieldMismatch{Interaction: r.Description, Consumer: r.Consumer, PactUri: r.PactUri, Expected: m.Expected(), Actual: m.Actual(),
+		Description: m.Description(), unequal: m.Unequal()}
+	segs := mismatchPathSegment.FindAllStringSubmatch(m.Path(), -1)
+	if len(segs) == 0 {
+		f.Path = m.Path()
+		return f
+	}
+	f.Part = unquoteSegment(segs[0][1])
+	if f.Part == "body" {
+		f.Path = "$"
+		for _, s := range segs[1:] {
+			if _, err := strconv.Atoi(s[1]); err == nil {
+				f.Path += "[" + s[1] + "]"
+			} else {
+				f.Path += "." + unquoteSegment(s[1])
+			}
+		}
+		return f
+	}
+	var names []string
+	for _, s := range segs[1:] {
+		names = append(names, unquoteSegment(s[1]))
+	}
+	f.Path = strings.Join(names, ".")
+	return f
+}
+
+func unquoteSegment(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
+
+//Mismatches returns the mismatches of the interaction by the part and path of the response they are at
+func (r *InteractionResult) Mismatches() []*FieldMismatch {
+	m := make([]*FieldMismatch, len(r.Differences))
+	for n, d := range r.Differences {
+		m[n] = newFieldMismatch(r, d)
+	}
+	return m
+}
+
+//Mismatches returns the mismatches of every failed interaction, in the order of the interactions
+func (r *VerificationResult) Mismatches() []*FieldMismatch {
+	var m []*FieldMismatch
+	for _, i := range r.Failures() {
+		m = append(m, i.Mismatches()...)
+	}
+	return m
+}
+
+//StateFailures are the failed interactions of a provider state
+type StateFailures struct {
+	State        string
+	Interactions []*InteractionResult
+	//CommonCause is the mismatch shared by every failed interaction of the state, empty when there is none
+	CommonCause string
+}
+
+//FailuresByState groups the failed interactions by provider state, the states with the most failures come first.
+//Many failures under the same state usually indicate a broken provider state setup.
+func (r *VerificationResult) FailuresByState() []*StateFailures {
+	var groups []*StateFailures
+	byState := make(map[string]*StateFailures)
+	for _, i := range r.Failures() {
+		g, ok := byState[i.State]
+		if !ok {
+			g = &StateFailures{State: i.State}
+			byState[i.State] = g
+			groups = append(groups, g)
+		}
+		g.Interactions = append(g.Interactions, i)
+	}
+
+	for _, g := range groups {
+		g.CommonCause = commonCause(g.Interactions)
+	}
+	sort.SliceStable(groups, func(a, b int) bool {
+		return len(groups[a].Interactions) > len(groups[b].Interactions)
+	})
+	return groups
+}
+
+//commonCause returns the first mismatch of the first interaction which every other interaction also has
+func commonCause(interactions []*InteractionResult) string {
+	counts := make(map[string]int)
+	for _, i := range interactions {
+		seen := make(map[string]bool)
+		for _, m := range i.Differences {
+			if s := m.String(); !seen[s] {
+				seen[s] = true
+				counts[s]++
+			}
+		}
+	}
+
+	for _, m := range interactions[0].Differences {
+		if s := m.String(); counts[s] == len(interactions) {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package pact
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/diff"
+)
+
+func testDifferences(expected, actual interface{}) diff.Differences {
+	_, d := diff.DeepDiff(expected, actual, &diff.DiffConfig{RootPath: "[\"body\"]"})
+	return d
+}
+
+func Test_Result_FailuresByState_GroupsAndOrders(t *testing.T) {
+	missingUser := testDifferences(map[string]interface{}{"id": 1}, map[string]interface{}{})
+	r := &VerificationResult{Interactions: []*InteractionResult{
+		{Description: "a", State: "no orders", Differences: testDifferences("x", "y")},
+		{Description: "b", State: "a user", Differences: missingUser},
+		{Description: "c", State: "a user", Differences: append(testDifferences("x", "y"), missingUser...)},
+		{Description: "d", State: "a user"},
+	}}
+
+	groups := r.FailuresByState()
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].State != "a user" || len(groups[0].Interactions) != 2 {
+		t.Errorf("expected 'a user' with 2 failures first, got '%s' with %d", groups[0].State, len(groups[0].Interactions))
+	}
+	if groups[0].CommonCause != missingUser[0].String() {
+		t.Errorf("expected the common cause to be %q, got %q", missingUser[0].String(), groups[0].CommonCause)
+	}
+	if groups[1].State != "no orders" || len(groups[1].Interactions) != 1 {
+		t.Errorf("expected 'no orders' with 1 failure second, got '%s' with %d", groups[1].State, len(groups[1].Interactions))
+	}
+}
+
+func Test_Result_FailuresByState_NoCommonCause(t *testing.T) {
+	r := &VerificationResult{Interactions: []*InteractionResult{
+		{Description: "a", State: "a user", Differences: testDifferences("x", "y")},
+		{Description: "b", State: "a user", Differences: testDifferences(map[string]interface{}{"id": 1}, map[string]interface{}{})},
+	}}
+
+	if groups := r.FailuresByState(); len(groups) != 1 || groups[0].CommonCause != "" {
+		t.Errorf("expected a single group without a common cause, got %#v", groups)
+	}
+}
+
+func Test_Result_Reason_CategorisesFailures(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		userHandlerWithValidData(w, r)
+	}
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		setup    Action
+		closed   bool
+		timeout  time.Duration
+		expected FailureReason
+	}{
+		{name: "matched", handler: userHandlerWithValidData, expected: NoFailure},
+		{name: "status", handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) }, expected: StatusMismatch},
+		{name: "header", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(`{"id":23,"first_name":"John","last_name":"Doe"}`))
+		}, expected: HeaderMismatch},
+		{name: "body", handler: userHandlerWithMismatchedData, expected: BodyMismatch},
+		{name: "request", handler: userHandlerWithValidData, closed: true, expected: RequestError},
+		{name: "state setup", handler: userHandlerWithValidData, setup: func() error { return errors.New("no database") }, expected: StateSetupError},
+		{name: "timeout", handler: slow, timeout: 50 * time.Millisecond, expected: Timeout},
+	}
+
+	for _, test := range tests {
+		s := httptest.NewServer(test.handler)
+		u, _ := url.Parse(s.URL)
+		if test.closed {
+			s.Close()
+		}
+		v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{Timeout: test.timeout}, u).
+			ProviderState("there is a user with id {23}", test.setup, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			SummaryWriter(ioutil.Discard)
+		v.Verify()
+		s.Close()
+
+		r := v.Result()
+		if r == nil || len(r.Interactions) == 0 {
+			t.Errorf("%s: expected the interaction to have a result", test.name)
+			continue
+		}
+		i := r.Interactions[0]
+		if i.Reason != test.expected {
+			t.Errorf("%s: expected the reason %q, got %q (%v %v)", test.name, test.expected, i.Reason, i.Differences, i.Error)
+		}
+		if i.Matched() != (test.expected == NoFailure) {
+			t.Errorf("%s: expected the interaction to match only without a failure", test.name)
+		}
+	}
+}
+
+func Test_Result_Mismatches_ByPartAndPath(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	var m *FieldMismatch
+	for _, f := range v.Result().Mismatches() {
+		if f.Path == "$.firstName" {
+			m = f
+		}
+	}
+	if m == nil {
+		t.Fatalf("expected the mismatch of the first name, got %v", v.Result().Mismatches())
+	}
+	if m.Interaction != "get request for user with id {23}" || m.Part != "body" || m.Path != "$.firstName" ||
+		m.Expected != "John" || m.Actual != "Jane" {
+		t.Errorf("expected the mismatch of the first name, got %#v", m)
+	}
+	expected := `interaction 'get request for user with id {23}': body path $.firstName expected "John" got "Jane"`
+	if m.String() != expected {
+		t.Errorf("expected %s, got %s", expected, m)
+	}
+}
@@ -0,0 +1,366 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//Schema is a JSON Schema used to validate json documents. It supports the keywords type, enum, const,
+//properties, required, additionalProperties, items, minItems, maxItems, uniqueItems, minLength, maxLength,
+//pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum, allOf, anyOf, oneOf, not and local $ref
+type Schema struct {
+	root interface{}
+}
+
+//Violation is a value of the document which does not validate against the schema
+type Violation struct {
+	Path    string
+	Message string
+	//Value is the value of the document which is not valid
+	Value interface{}
+}
+
+func (v *Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+var (
+	errInvalidSchema     = errors.New("The schema must be a json object or boolean.")
+	errInvalidKeywordMsg = "The schema keyword '%s' is invalid: %s"
+)
+
+//Parse parses the json schema
+func Parse(b []byte) (*Schema, error) {
+	var root interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&root); err != nil {
+		return nil, err
+	}
+	if err := check(root); err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+//check verifies the schema and its sub schemas are objects or booleans and their patterns compile
+func check(node interface{}) error {
+	switch n := node.(type) {
+	case bool:
+		return nil
+	case map[string]interface{}:
+		if p, ok := n["pattern"]; ok {
+			s, ok := p.(string)
+			if !ok {
+				return fmt.Errorf(errInvalidKeywordMsg, "pattern", "it must be a string")
+			} else if _, err := regexp.Compile(s); err != nil {
+				return fmt.Errorf(errInvalidKeywordMsg, "pattern", err)
+			}
+		}
+		for _, k := range []string{"additionalProperties", "items", "not"} {
+			if sub, ok := n[k]; ok {
+				if err := check(sub); err != nil {
+					return err
+				}
+			}
+		}
+		for _, k := range []string{"properties", "definitions", "$defs"} {
+			subs, _ := n[k].(map[string]interface{})
+			for _, sub := range subs {
+				if err := check(sub); err != nil {
+					return err
+				}
+			}
+		}
+		for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+			subs, _ := n[k].([]interface{})
+			for _, sub := range subs {
+				if err := check(sub); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		return errInvalidSchema
+	}
+}
+
+//Validate validates the document against the schema, the paths of the violations start with the root path
+func (s *Schema) Validate(v interface{}, rootPath string) []*Violation {
+	var violations []*Violation
+	s.validate(s.root, v, rootPath, &violations)
+	return violations
+}
+
+func (s *Schema) validate(node, v interface{}, path string, out *[]*Violation) {
+	violate := func(format string, args ...interface{}) {
+		*out = append(*out, &Violation{Path: path, Message: fmt.Sprintf(format, args...), Value: v})
+	}
+
+	n, ok := node.(map[string]interface{})
+	if !ok {
+		if allowed, _ := node.(bool); !allowed {
+			violate("no value is allowed")
+		}
+		return
+	}
+
+	if ref, ok := n["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			violate("%s", err)
+			return
+		}
+		s.validate(target, v, path, out)
+		return
+	}
+
+	if t, ok := n["type"]; ok && !matchesType(t, v) {
+		violate("expected type %s, received %s", formatType(t), typeOf(v))
+		return
+	}
+	if enum, ok := n["enum"].([]interface{}); ok && !contains(enum, v) {
+		violate("value %s is not one of %s", format(v), format(enum))
+	}
+	if c, ok := n["const"]; ok && !equal(c, v) {
+		violate("value %s is not %s", format(v), format(c))
+	}
+
+	switch val := v.(type) {
+	case json.Number, float64, int:
+		f, _ := number(val)
+		if min, ok := number(n["minimum"]); ok && f < min {
+			violate("%s is less than the minimum of %s", format(v), format(n["minimum"]))
+		}
+		if max, ok := number(n["maximum"]); ok && f > max {
+			violate("%s is greater than the maximum of %s", format(v), format(n["maximum"]))
+		}
+		if min, ok := number(n["exclusiveMinimum"]); ok && f <= min {
+			violate("%s is not greater than the exclusive minimum of %s", format(v), format(n["exclusiveMinimum"]))
+		}
+		if max, ok := number(n["exclusiveMaximum"]); ok && f >= max {
+			violate("%s is not less than the exclusive maximum of %s", format(v), format(n["exclusiveMaximum"]))
+		}
+	case string:
+		l := float64(utf8.RuneCountInString(val))
+		if min, ok := number(n["minLength"]); ok && l < min {
+			violate("length %d is shorter than the minimum length of %s", int(l), format(n["minLength"]))
+		}
+		if max, ok := number(n["maxLength"]); ok && l > max {
+			violate("length %d is longer than the maximum length of %s", int(l), format(n["maxLength"]))
+		}
+		if p, ok := n["pattern"].(string); ok && !regexp.MustCompile(p).MatchString(val) {
+			violate("%s does not match the pattern %s", format(v), p)
+		}
+	case []interface{}:
+		l := float64(len(val))
+		if min, ok := number(n["minItems"]); ok && l < min {
+			violate("%d items are fewer than the minimum of %s", len(val), format(n["minItems"]))
+		}
+		if max, ok := number(n["maxItems"]); ok && l > max {
+			violate("%d items are more than the maximum of %s", len(val), format(n["maxItems"]))
+		}
+		if unique, _ := n["uniqueItems"].(bool); unique {
+			for i := 1; i < len(val); i++ {
+				if contains(val[:i], val[i]) {
+					violate("item %d is not unique", i)
+					break
+				}
+			}
+		}
+		if items, ok := n["items"]; ok {
+			for i, item := range val {
+				s.validate(items, item, fmt.Sprintf("%s[%d]", path, i), out)
+			}
+		}
+	case map[string]interface{}:
+		required, _ := n["required"].([]interface{})
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := val[name]; !ok {
+					violate("required property %s is missing", name)
+				}
+			}
+		}
+		properties, _ := n["properties"].(map[string]interface{})
+		additional, hasAdditional := n["additionalProperties"]
+		for _, k := range sortedKeys(val) {
+			p := fmt.Sprintf("%s[\"%s\"]", path, k)
+			if sub, ok := properties[k]; ok {
+				s.validate(sub, val[k], p, out)
+			} else if allowed, ok := additional.(bool); ok && !allowed {
+				*out = append(*out, &Violation{Path: p, Message: fmt.Sprintf("additional property %s is not allowed", k), Value: val[k]})
+			} else if hasAdditional {
+				s.validate(additional, val[k], p, out)
+			}
+		}
+	}
+
+	if all, ok := n["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			s.validate(sub, v, path, out)
+		}
+	}
+	if anyOf, ok := n["anyOf"].([]interface{}); ok && s.countValid(anyOf, v, path) == 0 {
+		violate("value does not validate against any subschema of anyOf")
+	}
+	if oneOf, ok := n["oneOf"].([]interface{}); ok {
+		if c := s.countValid(oneOf, v, path); c != 1 {
+			violate("value validates against %d subschemas of oneOf, expected exactly 1", c)
+		}
+	}
+	if not, ok := n["not"]; ok && s.countValid([]interface{}{not}, v, path) == 1 {
+		violate("value must not validate against the not subschema")
+	}
+}
+
+//countValid returns how many of the schemas the value validates against
+func (s *Schema) countValid(schemas []interface{}, v interface{}, path string) int {
+	c := 0
+	for _, sub := range schemas {
+		var violations []*Violation
+		s.validate(sub, v, path, &violations)
+		if len(violations) == 0 {
+			c++
+		}
+	}
+	return c
+}
+
+//resolve returns the sub schema of the local reference, e.g. #/definitions/user
+func (s *Schema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("reference %s is not a local reference", ref)
+	}
+	node := s.root
+	for _, token := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("reference %s cannot be resolved", ref)
+		}
+		if node, ok = m[token]; !ok {
+			return nil, fmt.Errorf("reference %s cannot be resolved", ref)
+		}
+	}
+	return node, nil
+}
+
+func matchesType(t, v interface{}) bool {
+	switch types := t.(type) {
+	case string:
+		return isType(types, v)
+	case []interface{}:
+		for _, typ := range types {
+			if s, ok := typ.(string); ok && isType(s, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isType(t string, v interface{}) bool {
+	actual := typeOf(v)
+	if t == "number" && actual == "integer" {
+		return true
+	}
+	return t == actual
+}
+
+//typeOf returns the json schema type of the value
+func typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		if f, ok := number(val); ok {
+			if f == math.Trunc(f) {
+				return "integer"
+			}
+			return "number"
+		}
+	}
+	return reflect.TypeOf(v).String()
+}
+
+func formatType(t interface{}) string {
+	if types, ok := t.([]interface{}); ok {
+		s := make([]string, len(types))
+		for i, typ := range types {
+			s[i] = fmt.Sprint(typ)
+		}
+		return strings.Join(s, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func contains(values []interface{}, v interface{}) bool {
+	for _, e := range values {
+		if equal(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+//equal compares json values, numbers are equal by value regardless of their representation
+func equal(a, b interface{}) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func format(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return strconv.Quote(fmt.Sprint(v))
+	}
+	return string(b)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
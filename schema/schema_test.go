@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"name": {"type": "string", "minLength": 1, "pattern": "^[A-Z]"},
+		"email": {"type": ["string", "null"]},
+		"role": {"enum": ["admin", "member"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2, "uniqueItems": true},
+		"address": {"$ref": "#/definitions/address"}
+	},
+	"additionalProperties": false,
+	"definitions": {
+		"address": {"type": "object", "required": ["city"], "properties": {"city": {"type": "string"}}}
+	}
+}`
+
+func document(t *testing.T, s string) interface{} {
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(s))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func Test_Schema_ValidDocument(t *testing.T) {
+	s, err := Parse([]byte(userSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := document(t, `{"id": 23, "name": "John", "email": null, "role": "admin", "tags": ["a", "b"], "address": {"city": "Melbourne"}}`)
+	if v := s.Validate(doc, "[\"body\"]"); len(v) != 0 {
+		t.Errorf("expected no violations, got %v", v)
+	}
+}
+
+func Test_Schema_ReportsViolations(t *testing.T) {
+	s, err := Parse([]byte(userSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := document(t, `{"id": 1.5, "name": "john", "role": "owner", "tags": ["a", "a", "b"], "address": {}, "age": 30}`)
+
+	expected := []string{
+		`["body"]["address"]: required property city is missing`,
+		`["body"]["age"]: additional property age is not allowed`,
+		`["body"]["id"]: expected type integer, received number`,
+		`["body"]["name"]: "john" does not match the pattern ^[A-Z]`,
+		`["body"]["role"]: value "owner" is not one of ["admin","member"]`,
+		`["body"]["tags"]: 3 items are more than the maximum of 2`,
+		`["body"]["tags"]: item 1 is not unique`,
+	}
+	violations := s.Validate(doc, "[\"body\"]")
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %d violations, got %v", len(expected), violations)
+	}
+	for i, v := range violations {
+		if v.String() != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], v.String())
+		}
+	}
+}
+
+func Test_Schema_Combinators(t *testing.T) {
+	s, err := Parse([]byte(`{"oneOf": [{"type": "string"}, {"type": "integer"}], "not": {"const": 0}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := s.Validate(document(t, `"a"`), "$"); len(v) != 0 {
+		t.Errorf("expected a string to be valid, got %v", v)
+	}
+	if v := s.Validate(document(t, `0`), "$"); len(v) != 1 || !strings.Contains(v[0].Message, "not subschema") {
+		t.Errorf("expected the not violation, got %v", v)
+	}
+	if v := s.Validate(document(t, `true`), "$"); len(v) != 1 || !strings.Contains(v[0].Message, "oneOf") {
+		t.Errorf("expected the oneOf violation, got %v", v)
+	}
+}
+
+func Test_Schema_InvalidSchema(t *testing.T) {
+	for _, s := range []string{`[]`, `{"properties": {"id": 1}}`, `{"pattern": "("}`, `{`} {
+		if _, err := Parse([]byte(s)); err == nil {
+			t.Errorf("expected schema %s to be invalid", s)
+		}
+	}
+}
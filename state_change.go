@@ -0,0 +1,75 @@
+package pact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/SEEK-Jobs/pact-go/util"
+)
+
+const (
+	stateChangeSetup    = "setup"
+	stateChangeTeardown = "teardown"
+)
+
+var errStateChangeFailedMsg = "The %s of provider state '%s' failed, the state change url responded with %d status code."
+
+//stateChangeRequest is the body posted to the state change url
+type stateChangeRequest struct {
+	State  string                 `json:"state"`
+	Params map[string]interface{} `json:"params,omitempty"`
+	Action string                 `json:"action"`
+}
+
+type stateParamsKey struct{}
+
+//withStateParams returns the context of the setup and teardown of a provider state with its params
+func withStateParams(ctx context.Context, params map[string]interface{}) context.Context {
+	return context.WithValue(ctx, stateParamsKey{}, params)
+}
+
+//StateParams returns the params of the provider state a setup or teardown of the context is run for, e.g. {"id": 23}
+//for the v3 state {"name": "a user exists", "params": {"id": 23}}. It returns nil when the state has no params
+func StateParams(ctx context.Context) map[string]interface{} {
+	params, _ := ctx.Value(stateParamsKey{}).(map[string]interface{})
+	return params
+}
+
+//stateChangeAction returns the actions posting the setup, and unless setup only the teardown, of the state to the state change url
+func (v *pactValidator) stateChangeAction(state string) *stateAction {
+	var teardown ContextAction
+	if !v.opts.stateChangeSetupOnly {
+		teardown = v.postStateChange(state, stateChangeTeardown)
+	}
+	return newContextStateAction(v.postStateChange(state, stateChangeSetup), teardown)
+}
+
+func (v *pactValidator) postStateChange(state, action string) ContextAction {
+	return func(ctx context.Context) error {
+		b, err := json.Marshal(&stateChangeRequest{State: state, Params: StateParams(ctx), Action: action})
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest("POST", v.opts.stateChangeURL.String(), bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", util.UserAgent(v.opts.userAgent))
+
+		resp, err := v.c.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf(errStateChangeFailedMsg, action, state, resp.StatusCode)
+		}
+		return nil
+	}
+}
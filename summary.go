@@ -0,0 +1,159 @@
+package pact
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	summaryRed   = "\x1b[31m"
+	summaryGreen = "\x1b[32m"
+	summaryReset = "\x1b[0m"
+)
+
+var (
+	summaryHeadingMsg     = "Verified the pact between %s and %s\n"
+	summaryTotalsMsg      = "  %d interactions, %s, %s\n"
+	summaryNoteMsg        = "  Note: %s\n"
+	summaryPassedMsg      = "%d passed"
+	summaryFailedMsg      = "%d failed"
+	summarySkippedMsg     = ", %d skipped"
+	summaryFailuresMsg    = "  Failures:\n"
+	summaryFailureMsg     = "    - %s"
+	summaryMismatchMsg    = "        %s\n"
+	summaryStateMsg       = " given %s"
+	summaryLocationMsg    = " (%s)"
+	summaryTestNameMsg    = " [consumer test: %s]"
+	summaryCorrelationMsg = " [correlation id: %s]"
+	summaryPactMsg        = " [consumer: %s, pact: %s]"
+	summaryUnexpectedPass = " (expected to fail but passed)"
+	summaryByStateMsg     = "  Failures by provider state:\n"
+	summaryStateFailedMsg = "    - %s: %d interactions failed"
+	summaryCauseMsg       = ", likely common cause: %s"
+	summaryExploredMsg    = "  Explored %d endpoints not covered by the pacts, %d unexpected\n"
+	summaryFindingMsg     = "    - %s"
+)
+
+//DefaultMismatchFormatter renders a failing interaction as its description followed by its provider state, location,
+//consumer test and correlation id when they are known
+func DefaultMismatchFormatter(f *InteractionResult) string {
+	line := f.Description
+	if f.State != "" {
+		line += fmt.Sprintf(summaryStateMsg, f.State)
+	}
+	if f.Location != nil {
+		line += fmt.Sprintf(summaryLocationMsg, f.Location)
+	}
+	if f.TestName != "" {
+		line += fmt.Sprintf(summaryTestNameMsg, f.TestName)
+	}
+	if f.CorrelationID != "" {
+		line += fmt.Sprintf(summaryCorrelationMsg, f.CorrelationID)
+	}
+	if f.Matched() {
+		line += summaryUnexpectedPass
+	}
+	return line
+}
+
+//writeSummary writes a human readable summary of the verification result, the failing interactions are rendered by
+//the formatter or DefaultMismatchFormatter when it is nil
+func writeSummary(w io.Writer, r *VerificationResult, color bool, format func(f *InteractionResult) string) {
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + summaryReset
+	}
+
+	failures, skipped := r.Failures(), r.Skipped()
+	passed := fmt.Sprintf(summaryPassedMsg, len(r.Interactions)-len(failures)-len(skipped))
+	failed := fmt.Sprintf(summaryFailedMsg, len(failures))
+	if len(failures) > 0 {
+		failed = paint(summaryRed, failed)
+	} else {
+		passed = paint(summaryGreen, passed)
+	}
+	if len(skipped) > 0 {
+		failed += fmt.Sprintf(summarySkippedMsg, len(skipped))
+	}
+
+	fmt.Fprintf(w, summaryHeadingMsg, r.Consumer, r.Provider)
+	fmt.Fprintf(w, summaryTotalsMsg, len(r.Interactions), passed, failed)
+	for _, n := range r.Notes {
+		fmt.Fprintf(w, summaryNoteMsg, n)
+	}
+	defer writeExplorations(w, r.Explorations, paint)
+	if len(failures) == 0 {
+		return
+	}
+
+	if format == nil {
+		format = DefaultMismatchFormatter
+	}
+	//the failures of multiple pacts are labelled with the pact they are from
+	uris := make(map[string]bool)
+	for _, i := range r.Interactions {
+		uris[i.PactUri] = true
+	}
+	fmt.Fprint(w, summaryFailuresMsg)
+	for _, f := range failures {
+		line := format(f)
+		if len(uris) > 1 {
+			line += fmt.Sprintf(summaryPactMsg, f.Consumer, f.PactUri)
+		}
+		fmt.Fprintln(w, paint(summaryRed, fmt.Sprintf(summaryFailureMsg, line)))
+		for _, m := range f.Mismatches() {
+			fmt.Fprintf(w, summaryMismatchMsg, m.detail())
+		}
+	}
+
+	writeStateFailures(w, r.FailuresByState())
+}
+
+//writeStateFailures lists the provider states with more than one failed interaction
+func writeStateFailures(w io.Writer, groups []*StateFailures) {
+	heading := false
+	for _, g := range groups {
+		if g.State == "" || len(g.Interactions) < 2 {
+			continue
+		}
+		if !heading {
+			fmt.Fprint(w, summaryByStateMsg)
+			heading = true
+		}
+		line := fmt.Sprintf(summaryStateFailedMsg, g.State, len(g.Interactions))
+		if g.CommonCause != "" {
+			line += fmt.Sprintf(summaryCauseMsg, g.CommonCause)
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+//writeExplorations lists the probed endpoints which responded unexpectedly, after the contract results
+func writeExplorations(w io.Writer, explorations []*ExplorationResult, paint func(c, s string) string) {
+	if len(explorations) == 0 {
+		return
+	}
+	var unexpected []*ExplorationResult
+	for _, e := range explorations {
+		if e.Unexpected {
+			unexpected = append(unexpected, e)
+		}
+	}
+	fmt.Fprintf(w, summaryExploredMsg, len(explorations), len(unexpected))
+	for _, e := range unexpected {
+		fmt.Fprintln(w, paint(summaryRed, fmt.Sprintf(summaryFindingMsg, e)))
+	}
+}
+
+//isTerminal reports whether the writer is a terminal, colors are disabled for anything else
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
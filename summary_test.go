@@ -0,0 +1,113 @@
+package pact
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SEEK-Jobs/pact-go/diff"
+)
+
+func Test_Summary_AllPassed(t *testing.T) {
+	var buf bytes.Buffer
+	r := &VerificationResult{Consumer: "c", Provider: "p", Interactions: []*InteractionResult{
+		{Description: "first"},
+		{Description: "second", State: "some state"},
+	}}
+
+	writeSummary(&buf, r, false, nil)
+
+	expected := "Verified the pact between c and p\n  2 interactions, 2 passed, 0 failed\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func Test_Summary_ListsFailures(t *testing.T) {
+	var buf bytes.Buffer
+	_, status := diff.DeepDiff(200, 404, &diff.DiffConfig{RootPath: "[\"status\"]"})
+	r := &VerificationResult{Consumer: "c", Provider: "p", Interactions: []*InteractionResult{
+		{Description: "first", Differences: testDifferences(map[string]interface{}{"firstName": "John"}, map[string]interface{}{"firstName": "Jane"})},
+		{Description: "second", State: "some state", Differences: status},
+		{Description: "third", ExpectedFailure: true, Differences: diff.Differences{&diff.Mismatch{}}},
+		{Description: "fourth", ExpectedFailure: true},
+	}}
+
+	writeSummary(&buf, r, false, nil)
+
+	expected := "Verified the pact between c and p\n" +
+		"  4 interactions, 1 passed, 3 failed\n" +
+		"  Failures:\n" +
+		"    - first\n" +
+		"        body path $.firstName expected \"John\" got \"Jane\"\n" +
+		"    - second given some state\n" +
+		"        status expected 200 got 404\n" +
+		"    - fourth (expected to fail but passed)\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func Test_Summary_IncludesConsumerTestName(t *testing.T) {
+	var buf bytes.Buffer
+	r := &VerificationResult{Consumer: "c", Provider: "p", Interactions: []*InteractionResult{
+		{Description: "first", TestName: "TestGetUser", Differences: diff.Differences{&diff.Mismatch{}}},
+	}}
+
+	writeSummary(&buf, r, false, nil)
+
+	if !strings.Contains(buf.String(), "    - first [consumer test: TestGetUser]\n") {
+		t.Errorf("expected the consumer test in the failures, got %q", buf.String())
+	}
+}
+
+func Test_Summary_Colorized(t *testing.T) {
+	var buf bytes.Buffer
+	r := &VerificationResult{Interactions: []*InteractionResult{
+		{Description: "first", Differences: diff.Differences{&diff.Mismatch{}}},
+	}}
+
+	writeSummary(&buf, r, true, nil)
+
+	if !strings.Contains(buf.String(), summaryRed+"1 failed"+summaryReset) {
+		t.Errorf("expected failures to be colored red, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), summaryRed+"    - first"+summaryReset) {
+		t.Errorf("expected failed interaction to be colored red, got %q", buf.String())
+	}
+}
+
+func Test_Summary_IsTerminal_FalseForNonTTY(t *testing.T) {
+	if isTerminal(&bytes.Buffer{}) {
+		t.Error("expected a buffer not to be a terminal")
+	}
+
+	f, err := ioutil.TempFile("", "summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("expected a regular file not to be a terminal")
+	}
+}
+
+func Test_Summary_GroupsFailuresByState(t *testing.T) {
+	var buf bytes.Buffer
+	cause := testDifferences(map[string]interface{}{"id": 1}, map[string]interface{}{})
+	r := &VerificationResult{Interactions: []*InteractionResult{
+		{Description: "a", State: "a user", Differences: cause},
+		{Description: "b", State: "a user", Differences: cause},
+		{Description: "c", State: "no orders", Differences: cause},
+	}}
+
+	writeSummary(&buf, r, false, nil)
+
+	expected := "  Failures by provider state:\n    - a user: 2 interactions failed, likely common cause: " + cause[0].String() + "\n"
+	if !strings.HasSuffix(buf.String(), expected) {
+		t.Errorf("expected summary to end with %q, got %q", expected, buf.String())
+	}
+}
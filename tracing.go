@@ -0,0 +1,58 @@
+package pact
+
+import (
+	"context"
+	"net/http"
+)
+
+//Tracer creates spans for the verification steps, it allows the verification to be traced
+//by any tracing library (e.g. OpenTelemetry) without depending on it
+type Tracer interface {
+	//Start starts a span with the given name as a child of the span in the context
+	Start(ctx context.Context, name string) (context.Context, Span)
+	//Inject writes the span context as W3C trace headers (traceparent, tracestate)
+	Inject(ctx context.Context, h http.Header)
+}
+
+//Span is a single traced verification step
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End()
+}
+
+const (
+	spanPactDownload  = "pact.download"
+	spanInteraction   = "pact.interaction"
+	spanStateSetup    = "pact.state.setup"
+	attrPactUri       = "pact.uri"
+	attrDescription   = "pact.interaction.description"
+	attrProviderState = "pact.provider_state"
+	attrStatus        = "http.status_code"
+	attrOutcome       = "pact.outcome"
+	outcomePassed     = "passed"
+	outcomeFailed     = "failed"
+	outcomeErrored    = "error"
+)
+
+type noopTracer struct{}
+
+type noopSpan struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopTracer) Inject(ctx context.Context, h http.Header) {}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+
+func (noopSpan) End() {}
+
+func outcomeOf(ok bool, err error) string {
+	if err != nil {
+		return outcomeErrored
+	} else if ok {
+		return outcomePassed
+	}
+	return outcomeFailed
+}
@@ -0,0 +1,124 @@
+package pact
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type recordedSpan struct {
+	name  string
+	attrs map[string]interface{}
+	ended bool
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	s := &recordedSpan{name: name, attrs: make(map[string]interface{})}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, h http.Header) {
+	h.Set("traceparent", testTraceParent)
+}
+
+func (s *recordedSpan) SetAttribute(key string, value interface{}) {
+	s.attrs[key] = value
+}
+
+func (s *recordedSpan) End() {
+	s.ended = true
+}
+
+func (t *recordingTracer) named(name string) []*recordedSpan {
+	var spans []*recordedSpan
+	for _, s := range t.spans {
+		if s.name == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func Test_Tracing_CreatesSpansForVerificationSteps(t *testing.T) {
+	var traceParents []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+		userHandlerWithValidData(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	tracer := &recordingTracer{}
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		TraceWith(tracer).
+		PropagateTrace(true)
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	if spans := tracer.named(spanPactDownload); len(spans) != 1 {
+		t.Errorf("expected 1 download span, got %d", len(spans))
+	}
+	if spans := tracer.named(spanStateSetup); len(spans) != 2 {
+		t.Errorf("expected 2 state setup spans, got %d", len(spans))
+	}
+	interactions := tracer.named(spanInteraction)
+	if len(interactions) != 2 {
+		t.Fatalf("expected 2 interaction spans, got %d", len(interactions))
+	}
+	for _, s := range interactions {
+		if !s.ended {
+			t.Errorf("expected span %s to be ended", s.name)
+		}
+		if s.attrs[attrOutcome] != outcomePassed {
+			t.Errorf("expected outcome %s, got %v", outcomePassed, s.attrs[attrOutcome])
+		}
+	}
+	if interactions[1].attrs[attrStatus] != http.StatusNotFound {
+		t.Errorf("expected status %d, got %v", http.StatusNotFound, interactions[1].attrs[attrStatus])
+	}
+
+	for _, tp := range traceParents {
+		if tp != testTraceParent {
+			t.Errorf("expected traceparent %s, got %s", testTraceParent, tp)
+		}
+	}
+}
+
+func Test_Tracing_DoesNotPropagateByDefault(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tp := r.Header.Get("traceparent"); tp != "" {
+			t.Errorf("expected no traceparent, got %s", tp)
+		}
+		userHandlerWithValidData(w, r)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		TraceWith(&recordingTracer{})
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,40 @@
+package pact
+
+import (
+	"net/url"
+	"strings"
+)
+
+//TrailingSlashPolicy is how the trailing slash of the interaction paths is handled before they are sent
+type TrailingSlashPolicy int
+
+const (
+	//TrailingSlashPreserve sends the paths as they were recorded
+	TrailingSlashPreserve TrailingSlashPolicy = iota
+	//TrailingSlashStrip removes the trailing slash of the paths, e.g. /user/ is sent as /user
+	TrailingSlashStrip
+	//TrailingSlashAdd adds a trailing slash to the paths, e.g. /user is sent as /user/
+	TrailingSlashAdd
+)
+
+//apply changes the path of the url by the policy, the root path is left as it is
+func (p TrailingSlashPolicy) apply(u *url.URL) {
+	if u.Path == "" || u.Path == "/" {
+		return
+	}
+	switch p {
+	case TrailingSlashStrip:
+		u.Path = strings.TrimRight(u.Path, "/")
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	case TrailingSlashAdd:
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+			if u.RawPath != "" {
+				u.RawPath += "/"
+			}
+		}
+	}
+}
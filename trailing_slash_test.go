@@ -0,0 +1,62 @@
+package pact
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_TrailingSlashPolicy_AppliesToPath(t *testing.T) {
+	tests := []struct {
+		policy   TrailingSlashPolicy
+		path     string
+		expected string
+	}{
+		{TrailingSlashPreserve, "/user/", "/user/"},
+		{TrailingSlashPreserve, "/user", "/user"},
+		{TrailingSlashStrip, "/user/", "/user"},
+		{TrailingSlashStrip, "/user", "/user"},
+		{TrailingSlashStrip, "/", "/"},
+		{TrailingSlashAdd, "/user", "/user/"},
+		{TrailingSlashAdd, "/user/", "/user/"},
+		{TrailingSlashAdd, "", ""},
+	}
+
+	for _, test := range tests {
+		u := &url.URL{Path: test.path}
+		test.policy.apply(u)
+		if u.Path != test.expected {
+			t.Errorf("expected %q to be sent as %q by policy %d, got %q", test.path, test.expected, test.policy, u.Path)
+		}
+	}
+}
+
+func Test_Verifier_TrailingSlash_ChangesSentPaths(t *testing.T) {
+	var paths []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		userHandlerWithValidData(w, r)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	for policy, expected := range map[TrailingSlashPolicy]string{TrailingSlashPreserve: "/user", TrailingSlashStrip: "/user", TrailingSlashAdd: "/user/"} {
+		paths = nil
+		v := NewPactFileVerifier(nil, nil, nil).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			TrailingSlash(policy).
+			SummaryWriter(ioutil.Discard)
+		if err := v.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) != 2 || paths[0] != expected || paths[1] != expected {
+			t.Errorf("expected policy %d to send %s, got %v", policy, expected, paths)
+		}
+	}
+}
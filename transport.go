@@ -0,0 +1,158 @@
+package pact
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+//TransportConfig tunes the connections of the provider client, a zero duration keeps the default
+type TransportConfig struct {
+	//IdleConnTimeout is how long an idle keep-alive connection is kept open before it is closed
+	IdleConnTimeout time.Duration
+	//TLSHandshakeTimeout is how long the TLS handshake may take
+	TLSHandshakeTimeout time.Duration
+	//ExpectContinueTimeout is how long to wait for the provider's first response headers after sending the request
+	//headers, when the request has an Expect: 100-continue header
+	ExpectContinueTimeout time.Duration
+	//DisableKeepAlives opens a new connection for every request
+	DisableKeepAlives bool
+	//HTTP2 speaks only HTTP/2 to the provider, with prior knowledge (h2c) over http urls and negotiated over TLS
+	//for https urls, for providers which do not speak HTTP/1.1
+	HTTP2 bool
+	//MaxConnsPerHost limits the connections to the provider, including the ones in use, the requests wait for a
+	//connection once the limit is reached. Zero is no limit
+	MaxConnsPerHost int
+}
+
+//DefaultTransportConfig is the tuning used for the durations a TransportConfig leaves as zero, the same as the
+//http.DefaultTransport
+var DefaultTransportConfig = &TransportConfig{
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+//apply returns a copy of the client whose transport is tuned by the config, the client is returned as it is when
+//there is no config or its transport is not a *http.Transport
+func (tc *TransportConfig) apply(c *http.Client) *http.Client {
+	if tc == nil || c == nil {
+		return c
+	}
+
+	t, ok := cloneTransport(c)
+	if !ok {
+		return c
+	}
+
+	t.IdleConnTimeout = durationOrDefault(tc.IdleConnTimeout, DefaultTransportConfig.IdleConnTimeout)
+	t.TLSHandshakeTimeout = durationOrDefault(tc.TLSHandshakeTimeout, DefaultTransportConfig.TLSHandshakeTimeout)
+	t.ExpectContinueTimeout = durationOrDefault(tc.ExpectContinueTimeout, DefaultTransportConfig.ExpectContinueTimeout)
+	t.DisableKeepAlives = tc.DisableKeepAlives
+	t.MaxConnsPerHost = tc.MaxConnsPerHost
+
+	tuned := *c
+	tuned.Transport = t
+	return &tuned
+}
+
+//withHTTP2 returns a copy of the client speaking only HTTP/2 to the provider when the config asks for it, h2c
+//over the plain connections dialed by the transport for http urls and h2 negotiated by TLS for https urls. It is
+//applied once the transport is otherwise set up, the client is returned as it is when its transport is not a
+//*http.Transport
+func (tc *TransportConfig) withHTTP2(c *http.Client) *http.Client {
+	if tc == nil || !tc.HTTP2 || c == nil {
+		return c
+	}
+	t, ok := cloneTransport(c)
+	if !ok {
+		return c
+	}
+
+	//an error means the transport already negotiates h2, it is only restricted to h2 below
+	http2.ConfigureTransports(t)
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.NextProtos = []string{http2.NextProtoTLS}
+
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	t.RegisterProtocol("http", &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dial(ctx, network, addr)
+		},
+		IdleConnTimeout: t.IdleConnTimeout,
+	})
+
+	h2 := *c
+	h2.Transport = t
+	return &h2
+}
+
+//withServerName returns a copy of the client presenting the server name in the TLS handshakes, and verifying the
+//certificates of the provider for it, the client is returned as it is when its transport is not a *http.Transport
+func withServerName(c *http.Client, name string) *http.Client {
+	if name == "" || c == nil {
+		return c
+	}
+	t, ok := cloneTransport(c)
+	if !ok {
+		return c
+	}
+
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.ServerName = name
+
+	named := *c
+	named.Transport = t
+	return &named
+}
+
+//dialer dials the connections to the provider, e.g. through an SSH tunnel
+type dialer struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+//withDialer returns a copy of the client whose connections are dialed by the dialer, the client is returned as it
+//is when its transport is not a *http.Transport
+func withDialer(c *http.Client, d *dialer) *http.Client {
+	if d == nil || c == nil {
+		return c
+	}
+	t, ok := cloneTransport(c)
+	if !ok {
+		return c
+	}
+	t.DialContext = d.dial
+
+	dialed := *c
+	dialed.Transport = t
+	return &dialed
+}
+
+//cloneTransport returns a copy of the transport of the client, false when it is not a *http.Transport
+func cloneTransport(c *http.Client) (*http.Transport, bool) {
+	if c.Transport == nil {
+		return http.DefaultTransport.(*http.Transport).Clone(), true
+	} else if ct, ok := c.Transport.(*http.Transport); ok {
+		return ct.Clone(), true
+	}
+	return nil, false
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,245 @@
+package pact
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func Test_TransportConfig_AppliesSettingsAndDefaults(t *testing.T) {
+	orig := &http.Transport{IdleConnTimeout: time.Minute}
+	c := &http.Client{Transport: orig}
+
+	tuned := (&TransportConfig{TLSHandshakeTimeout: time.Second, DisableKeepAlives: true}).apply(c)
+	tr, ok := tuned.Transport.(*http.Transport)
+	if !ok || tr == orig {
+		t.Fatalf("expected a copy of the transport, got %#v", tuned.Transport)
+	}
+	if tr.TLSHandshakeTimeout != time.Second || !tr.DisableKeepAlives {
+		t.Errorf("expected the configured settings, got %v and %v", tr.TLSHandshakeTimeout, tr.DisableKeepAlives)
+	}
+	if tr.IdleConnTimeout != DefaultTransportConfig.IdleConnTimeout || tr.ExpectContinueTimeout != DefaultTransportConfig.ExpectContinueTimeout {
+		t.Errorf("expected the default timeouts, got %v and %v", tr.IdleConnTimeout, tr.ExpectContinueTimeout)
+	}
+	if orig.IdleConnTimeout != time.Minute || orig.DisableKeepAlives {
+		t.Error("expected the transport of the client not to be modified")
+	}
+}
+
+func Test_TransportConfig_KeepsCustomRoundTripper(t *testing.T) {
+	c := &http.Client{Transport: &harTransport{}}
+	if tuned := (&TransportConfig{DisableKeepAlives: true}).apply(c); tuned != c {
+		t.Error("expected a client without a *http.Transport to be kept")
+	}
+}
+
+func Test_Verifier_TransportConfig_HonoursIdleTimeout(t *testing.T) {
+	var mu sync.Mutex
+	closed := 0
+	s := httptest.NewUnstartedServer(http.HandlerFunc(userHandlerWithValidData))
+	s.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			mu.Lock()
+			closed++
+			mu.Unlock()
+		}
+	}
+	s.Start()
+	defer s.Close()
+
+	u, _ := url.Parse(s.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		TransportConfig(&TransportConfig{IdleConnTimeout: 20 * time.Millisecond}).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := closed
+		mu.Unlock()
+		if n > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("expected the idle connection to the provider to be closed after the idle timeout")
+}
+
+func Test_Verifier_MaxConnsPerHost_BoundsTheConnections(t *testing.T) {
+	var mu sync.Mutex
+	open, max := 0, 0
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	s.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch state {
+		case http.StateNew:
+			if open++; open > max {
+				max = open
+			}
+		case http.StateClosed, http.StateHijacked:
+			open--
+		}
+	}
+	s.Start()
+	defer s.Close()
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		TransportConfig(&TransportConfig{IdleConnTimeout: time.Minute}).
+		MaxConnsPerHost(2).(*pactFileVerfier)
+	if tc := v.options.transport; tc.MaxConnsPerHost != 2 || tc.IdleConnTimeout != time.Minute {
+		t.Fatalf("expected the limit to be added to the transport config, got %+v", tc)
+	}
+
+	c := v.options.transport.apply(&http.Client{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(s.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if max == 0 || max > 2 {
+		t.Errorf("expected at most 2 simultaneous connections, got %d", max)
+	}
+}
+
+func Test_Verifier_TransportConfig_VerifiesOverHTTP2(t *testing.T) {
+	var mu sync.Mutex
+	var protos []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		protos = append(protos, r.Proto)
+		mu.Unlock()
+		userHandlerWithValidData(w, r)
+	})
+
+	cleartext := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer cleartext.Close()
+
+	h2 := httptest.NewUnstartedServer(handler)
+	h2.EnableHTTP2 = true
+	h2.StartTLS()
+	defer h2.Close()
+
+	for _, s := range []*httptest.Server{cleartext, h2} {
+		protos = nil
+		u, _ := url.Parse(s.URL)
+		v := NewPactFileVerifier(nil, nil, nil).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", s.Client(), u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			TransportConfig(&TransportConfig{HTTP2: true}).
+			SummaryWriter(ioutil.Discard)
+		if err := v.Verify(); err != nil {
+			t.Fatalf("expected %s to verify over HTTP/2: %s", s.URL, err)
+		}
+		if len(protos) != 2 || protos[0] != "HTTP/2.0" || protos[1] != "HTTP/2.0" {
+			t.Errorf("expected the requests to %s over HTTP/2, got %v", s.URL, protos)
+		}
+	}
+}
+
+func Test_Verifier_TLSServerName_VerifiesCertificateOfServerName(t *testing.T) {
+	var mu sync.Mutex
+	var names []string
+	s := httptest.NewUnstartedServer(http.HandlerFunc(userHandlerWithValidData))
+	s.TLS = &tls.Config{GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		mu.Lock()
+		names = append(names, hello.ServerName)
+		mu.Unlock()
+		return nil, nil
+	}}
+	s.StartTLS()
+	defer s.Close()
+	//the certificate of the test server is for example.com and the loopback ips, not localhost
+	u, _ := url.Parse(strings.Replace(s.URL, "127.0.0.1", "localhost", 1))
+
+	verify := func(name string) error {
+		v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", s.Client(), u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			TLSServerName(name).
+			SummaryWriter(ioutil.Discard)
+		return v.Verify()
+	}
+
+	if err := verify(""); err == nil {
+		t.Error("expected the certificate not to be valid for localhost")
+	}
+	names = nil
+	if err := verify("example.com"); err != nil {
+		t.Fatalf("expected the certificate to be verified for example.com: %s", err)
+	}
+	if len(names) == 0 || names[0] != "example.com" {
+		t.Errorf("expected the handshakes to present example.com, got %v", names)
+	}
+}
+
+func Test_Verifier_DialContext_DialsThroughDialer(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer s.Close()
+	//the provider is only reachable through the dialer, which tunnels every address to the test server
+	u, _ := url.Parse("http://provider.internal:8080")
+
+	var mu sync.Mutex
+	var dialed []string
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		TransportConfig(&TransportConfig{DisableKeepAlives: true}).
+		DialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			mu.Lock()
+			dialed = append(dialed, addr)
+			mu.Unlock()
+			var d net.Dialer
+			return d.DialContext(ctx, network, s.Listener.Addr().String())
+		}).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if len(dialed) != 2 || dialed[0] != "provider.internal:8080" {
+		t.Errorf("expected the dialer to dial provider.internal:8080 for both interactions, got %v", dialed)
+	}
+}
@@ -0,0 +1,39 @@
+package pact
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+const unixScheme = "unix"
+
+//unixSocketHost is the host of the requests sent over a unix socket
+var unixSocketHost = &url.URL{Scheme: "http", Host: "unix"}
+
+//targetProvider returns the client and base url used to send requests to the provider, a unix:// url
+//is dialled as a unix domain socket whilst the requests are still sent as http
+func targetProvider(c *http.Client, u *url.URL) (*http.Client, *url.URL) {
+	if c == nil || u == nil || u.Scheme != unixScheme {
+		return c, u
+	}
+
+	socket := u.Path
+	dial := func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socket)
+	}
+
+	var t *http.Transport
+	if ct, ok := c.Transport.(*http.Transport); ok {
+		t = ct.Clone()
+	} else {
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	t.DialContext = dial
+
+	sc := *c
+	sc.Transport = t
+	return &sc, unixSocketHost
+}
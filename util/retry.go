@@ -0,0 +1,147 @@
+package util
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//DefaultMaxRetryAfter is the longest delay requested by a Retry-After header which is honoured when no maximum is configured
+const DefaultMaxRetryAfter = 30 * time.Second
+
+//RetryPolicy configures how requests which fail, or are throttled with a 429 or 503 status code, are retried
+type RetryPolicy struct {
+	//Attempts is the maximum number of times a request is sent
+	Attempts int
+	//Backoff is the delay before the first retry, it doubles after each retry
+	Backoff time.Duration
+	//MaxBackoff caps the delay between retries, no cap is applied when it is not set
+	MaxBackoff time.Duration
+	//Jitter randomizes each backoff delay by up to 50% either way, so parallel clients do not retry in lockstep
+	Jitter bool
+	//Rand is the source of the jitter, a time seeded source is used when it is not set. Set a seeded source
+	//for deterministic delays
+	Rand *rand.Rand
+	//MaxRetryAfter caps the delay requested by a Retry-After header
+	MaxRetryAfter time.Duration
+}
+
+var (
+	//randMu guards the jitter sources, a rand.Rand is not safe for concurrent use
+	randMu      sync.Mutex
+	defaultRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+//sleep waits for the delay unless the request is cancelled first
+var sleep = func(req *http.Request, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+//Do sends the request created by newRequest using the client until it succeeds or the attempts are exhausted,
+//a nil policy sends the request once. The delay requested by a Retry-After header is used instead of the backoff.
+func (p *RetryPolicy) Do(c *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	attempts := 1
+	if p != nil && p.Attempts > 1 {
+		attempts = p.Attempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.Do(req)
+		if attempt == attempts || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt)
+		if resp != nil {
+			if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				delay = p.capRetryAfter(d)
+			}
+			resp.Body.Close()
+		}
+
+		if err := sleep(req, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+//backoff returns the delay after the attempt, Backoff * 2^(attempt-1) randomized by the jitter and capped at MaxBackoff
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && d > 0; i++ {
+		if (p.MaxBackoff > 0 && d >= p.MaxBackoff) || d > math.MaxInt64/4 {
+			break
+		}
+		d *= 2
+	}
+
+	if p.Jitter && d > 0 {
+		r := p.Rand
+		if r == nil {
+			r = defaultRand
+		}
+		randMu.Lock()
+		f := 0.5 + r.Float64()
+		randMu.Unlock()
+		d = time.Duration(float64(d) * f)
+	}
+
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
+
+func (p *RetryPolicy) capRetryAfter(d time.Duration) time.Duration {
+	max := p.MaxRetryAfter
+	if max <= 0 {
+		max = DefaultMaxRetryAfter
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+//parseRetryAfter parses the Retry-After header value which is either a number of seconds or a HTTP date
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
@@ -0,0 +1,205 @@
+package util
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+//stubSleep records the delays instead of waiting, the returned func restores sleep
+func stubSleep() (*[]time.Duration, func()) {
+	var delays []time.Duration
+	orig := sleep
+	sleep = func(req *http.Request, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return &delays, func() { sleep = orig }
+}
+
+func throttledServer(status int, retryAfter string, failures int) (*httptest.Server, *int) {
+	calls := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return s, &calls
+}
+
+func get(url string) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		return http.NewRequest("GET", url, nil)
+	}
+}
+
+func Test_Retry_HonoursRetryAfterSeconds(t *testing.T) {
+	delays, restore := stubSleep()
+	defer restore()
+	s, calls := throttledServer(http.StatusServiceUnavailable, "2", 1)
+	defer s.Close()
+
+	p := &RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+	resp, err := p.Do(&http.Client{}, get(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if *calls != 2 {
+		t.Errorf("expected 2 requests, got %d", *calls)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 2*time.Second {
+		t.Errorf("expected a single delay of 2s, got %v", *delays)
+	}
+}
+
+func Test_Retry_CapsRetryAfter(t *testing.T) {
+	delays, restore := stubSleep()
+	defer restore()
+	s, _ := throttledServer(http.StatusTooManyRequests, "120", 1)
+	defer s.Close()
+
+	p := &RetryPolicy{Attempts: 2, MaxRetryAfter: 5 * time.Second}
+	resp, err := p.Do(&http.Client{}, get(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(*delays) != 1 || (*delays)[0] != 5*time.Second {
+		t.Errorf("expected a single delay of 5s, got %v", *delays)
+	}
+}
+
+func Test_Retry_UsesBackoffWithoutRetryAfter(t *testing.T) {
+	delays, restore := stubSleep()
+	defer restore()
+	s, calls := throttledServer(http.StatusServiceUnavailable, "", 5)
+	defer s.Close()
+
+	p := &RetryPolicy{Attempts: 3, Backoff: time.Second}
+	resp, err := p.Do(&http.Client{}, get(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the last response to be returned, got %d", resp.StatusCode)
+	}
+	if *calls != 3 {
+		t.Errorf("expected 3 requests, got %d", *calls)
+	}
+	if len(*delays) != 2 || (*delays)[0] != time.Second || (*delays)[1] != 2*time.Second {
+		t.Errorf("expected delays of 1s and 2s, got %v", *delays)
+	}
+}
+
+func Test_Retry_NilPolicySendsOnce(t *testing.T) {
+	s, calls := throttledServer(http.StatusServiceUnavailable, "1", 1)
+	defer s.Close()
+
+	var p *RetryPolicy
+	resp, err := p.Do(&http.Client{}, get(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if *calls != 1 || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected a single throttled request, got %d requests with status %d", *calls, resp.StatusCode)
+	}
+}
+
+func Test_Retry_ParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 21 Oct 2015 07:28:10 GMT", 10 * time.Second, true},
+		{"Wed, 21 Oct 2015 07:27:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, c := range cases {
+		if d, ok := parseRetryAfter(c.value, now); d != c.delay || ok != c.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", c.value, d, ok, c.delay, c.ok)
+		}
+	}
+}
+
+func Test_Retry_CapsBackoff(t *testing.T) {
+	p := &RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, e := range expected {
+		if d := p.backoff(i + 1); d != e {
+			t.Errorf("expected the delay after attempt %d to be %v, got %v", i+1, e, d)
+		}
+	}
+
+	p.MaxBackoff = 0
+	if d := p.backoff(100); d <= 0 {
+		t.Errorf("expected the uncapped delay not to overflow, got %v", d)
+	}
+}
+
+func Test_Retry_JitterRandomizesBackoff(t *testing.T) {
+	p := &RetryPolicy{Backoff: time.Second, Jitter: true, MaxBackoff: 10 * time.Second, Rand: rand.New(rand.NewSource(1))}
+	q := &RetryPolicy{Backoff: time.Second, Jitter: true, MaxBackoff: 10 * time.Second, Rand: rand.New(rand.NewSource(1))}
+
+	distinct := map[time.Duration]bool{}
+	for attempt := 1; attempt <= 4; attempt++ {
+		base := time.Second << uint(attempt-1)
+		for i := 0; i < 20; i++ {
+			d := p.backoff(attempt)
+			if d < base/2 || d > base*3/2 || d > p.MaxBackoff {
+				t.Errorf("expected the delay after attempt %d within 50%% of %v and at most %v, got %v", attempt, base, p.MaxBackoff, d)
+			}
+			if e := q.backoff(attempt); d != e {
+				t.Errorf("expected the same seed to give the same delays, got %v and %v", d, e)
+			}
+			distinct[d] = true
+		}
+	}
+	if len(distinct) < 2 {
+		t.Errorf("expected the jitter to randomize the delays, got %v", distinct)
+	}
+}
+
+func Test_Retry_UsesJitteredBackoff(t *testing.T) {
+	delays, restore := stubSleep()
+	defer restore()
+	s, _ := throttledServer(http.StatusServiceUnavailable, "", 5)
+	defer s.Close()
+
+	p := &RetryPolicy{Attempts: 3, Backoff: time.Second, Jitter: true, Rand: rand.New(rand.NewSource(7))}
+	resp, err := p.Do(&http.Client{}, get(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	r := rand.New(rand.NewSource(7))
+	first := time.Duration(float64(time.Second) * (0.5 + r.Float64()))
+	second := time.Duration(float64(2*time.Second) * (0.5 + r.Float64()))
+	if len(*delays) != 2 || (*delays)[0] != first || (*delays)[1] != second {
+		t.Errorf("expected delays of %v and %v, got %v", first, second, *delays)
+	}
+}
@@ -0,0 +1,16 @@
+package util
+
+//Version is the version of pact-go
+const Version = "1.1.0"
+
+//DefaultUserAgent is the User-Agent header sent with the requests to the provider and the pact broker when
+//none is configured, it identifies the verification traffic
+const DefaultUserAgent = "pact-go/" + Version
+
+//UserAgent returns the user agent, or the default one when it is empty
+func UserAgent(ua string) string {
+	if ua == "" {
+		return DefaultUserAgent
+	}
+	return ua
+}
@@ -1,39 +1,226 @@
 package pact
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	goio "io"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
 
 	"github.com/SEEK-Jobs/pact-go/consumer"
 	"github.com/SEEK-Jobs/pact-go/io"
+	"github.com/SEEK-Jobs/pact-go/matchers"
+	"github.com/SEEK-Jobs/pact-go/schema"
+	"github.com/SEEK-Jobs/pact-go/util"
 )
 
 // Verifier verifies the consumer interactions with the provider
 type Verifier interface {
 	ProviderState(state string, setup, teardown Action) Verifier
+	ProviderStateContext(state string, setup, teardown ContextAction) Verifier
+	ProviderStateWithValues(state string, setup StateSetup, teardown StateTeardown) Verifier
+	ProviderStateWithParams(state string, setup ParamsAction, teardown Action) Verifier
+	DefaultProviderState(state string, setup, teardown Action) Verifier
+	StateChangeURL(u *url.URL) Verifier
+	StateNameMapper(f func(description string) string) Verifier
+	SetupOnly(setupOnly bool) Verifier
+	Configure(cfg VerifierConfig) Verifier
 	ServiceProvider(providerName string, c *http.Client, u *url.URL) Verifier
+	ServiceProviderFunc(providerName string, c *http.Client, resolve func() (*url.URL, error)) Verifier
+	ServiceProviderHAR(providerName, path string) Verifier
 	HonoursPactWith(consumerName string) Verifier
 	PactUri(uri string, config *PactUriConfig) Verifier
+	AddPact(uri string, config *PactUriConfig) Verifier
+	PactUris(uris ...string) Verifier
+	PactDir(dir string) Verifier
+	PactReader(r goio.Reader) Verifier
+	PactBroker(baseURL string, auth *BrokerAuth) Verifier
+	ConsumerVersion(version string) Verifier
+	ForEnvironment(name string) Verifier
+	ExpectedFailures(descriptions []string) Verifier
+	OnInteraction(f func(r *InteractionResult)) Verifier
+	MismatchFormatter(f func(r *InteractionResult) string) Verifier
+	AfterInteraction(f func(states []string) error) Verifier
+	AllowEmptyPact(allow bool) Verifier
+	RequireMinInteractions(n int) Verifier
+	WarningsAsErrors(strict bool) Verifier
+	PanicAsFailure(asFailure bool) Verifier
+	TraceWith(t Tracer) Verifier
+	PropagateTrace(propagate bool) Verifier
+	NoExtraBody(noExtraBody bool) Verifier
+	StrictArrayLength(strict bool) Verifier
+	StrictEmptyObjects(strict bool) Verifier
+	IgnoreGenerators(ignore bool) Verifier
+	ExplainMatches(explain bool) Verifier
+	DetailedTimings(detailed bool) Verifier
+	IgnoreResponseHeaders(headers []string) Verifier
+	ForbiddenResponseHeaders(headers []string) Verifier
+	ServerErrorBodyLimit(limit int) Verifier
+	SmokeMode(smoke bool) Verifier
+	SniffContentType(sniff bool) Verifier
+	SetFaultInjector(f FaultInjector) Verifier
+	ProviderBasicAuth(username, password string) Verifier
+	ProviderNameAliases(aliases []string) Verifier
+	CorrelationID(header string, generate func() string) Verifier
+	RequestBodyEncoder(e BodyEncoder) Verifier
+	SignRequests(s RequestSigner) Verifier
+	RequestFilter(f func(req *http.Request)) Verifier
+	Concurrency(n int) Verifier
+	Use(m RequestMiddleware) Verifier
+	ResponseSchema(description string, schema []byte) Verifier
+	OverrideMatchingRules(description string, rules matchers.MatchingRules) Verifier
+	Retry(p *util.RetryPolicy) Verifier
+	MaxLatency(d time.Duration) Verifier
+	RequestDelay(d time.Duration) Verifier
+	TransportConfig(c *TransportConfig) Verifier
+	MaxConnsPerHost(n int) Verifier
+	ExploreEndpoints(endpoints []EndpointSpec) Verifier
+	TLSServerName(name string) Verifier
+	DialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Verifier
+	ProviderAuthHook(hook ProviderAuth) Verifier
+	ProviderAuthTTL(ttl time.Duration) Verifier
+	BrokerRetry(p *util.RetryPolicy) Verifier
+	UserAgent(ua string) Verifier
+	TrailingSlash(p TrailingSlashPolicy) Verifier
+	RouteInteractions(f func(path string) (*url.URL, error)) Verifier
+	WaitForProvider(healthPath string, timeout, interval time.Duration) Verifier
+	Color(color bool) Verifier
+	ReportFile(path string, format ReportFormat) Verifier
+	SummaryWriter(w goio.Writer) Verifier
+	WriteVerificationResults(path, providerVersion, buildURL string) Verifier
+	ProviderVersionTags(tags []string) Verifier
+	ProviderBranch(branch string) Verifier
+	PublishResults(providerVersion string) Verifier
 	Verify() error
+	VerifyChangesSince(brokerURL, previousVersion string) error
+	VerifyScenario(name string) error
+	VerifyDescriptionPattern(re string) error
 	VerifyState(description string, state string) error
+	VerifyT(t *testing.T)
+	VerifyB(b *testing.B)
+	PreviewRequest(description string) (*http.Request, error)
+	VerifyContext(ctx context.Context) error
+	VerifyStateContext(ctx context.Context, description string, state string) error
+	Build() error
+	Validate() []error
+	DumpConfig(w goio.Writer) error
+	Result() *VerificationResult
 }
 
 type Action func() error
 
+//ParamsAction is a provider state setup receiving the params of the state recorded by a v3 or v4 pact, e.g.
+//{"id": 23}, the params are empty for the states of older pacts
+type ParamsAction func(params map[string]interface{}) error
+
+//BodyEncoder builds the request body sent to the provider, and its content type, from the recorded interaction
+type BodyEncoder func(interaction consumer.Interaction) (goio.Reader, string, error)
+
+//RequestSigner signs the request sent to the provider, e.g. with an HMAC header, once it is final. The body is the
+//exact bytes sent, after the body encoder and compression, the request body must not be read
+type RequestSigner func(req *http.Request, body []byte) error
+
+//ContextAction is an action which honours the cancellation and deadline of the verification context
+type ContextAction func(ctx context.Context) error
+
+//StateSetup is a provider state setup returning the values it created, e.g. a generated user id, which are
+//passed to the teardown of the state
+type StateSetup func(ctx context.Context) (map[string]interface{}, error)
+
+//StateTeardown is a provider state teardown receiving the values returned by the setup of the state,
+//the values are empty when the setup returned none
+type StateTeardown func(ctx context.Context, values map[string]interface{}) error
+
 type stateAction struct {
-	setup    Action
-	teardown Action
+	setup    StateSetup
+	teardown StateTeardown
+}
+
+//defaultState is the provider state applied to every interaction in addition to its own state
+type defaultState struct {
+	name   string
+	action *stateAction
+}
+
+func newStateAction(setup, teardown Action) *stateAction {
+	return newContextStateAction(withContext(setup), withContext(teardown))
+}
+
+//newContextStateAction adapts the context actions to a state action whose setup returns no values
+func newContextStateAction(setup, teardown ContextAction) *stateAction {
+	sa := &stateAction{}
+	if setup != nil {
+		sa.setup = func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, setup(ctx)
+		}
+	}
+	if teardown != nil {
+		sa.teardown = func(ctx context.Context, _ map[string]interface{}) error {
+			return teardown(ctx)
+		}
+	}
+	return sa
+}
+
+//withContext adapts an action to a context action, the context is ignored by the action
+func withContext(a Action) ContextAction {
+	if a == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		return a()
+	}
 }
 
 type pactFileVerfier struct {
 	stateActions  map[string]*stateAction
 	provider      string
 	consumer      string
+	aliases       []string
 	pactUri       string
 	pactUriConfig *PactUriConfig
+	pacts         []*pactSource
+	pactDir       string
+	pactReader    io.PactReader
+	brokerURL     string
+	brokerAuth    *BrokerAuth
+	consumerVer   string
+	environment   string
 	validator     consumerValidator
 	config        *VerfierConfig
+	options       *validationOptions
+	summary       goio.Writer
+	resultsFile   *resultsFile
+	reportFile    *reportFile
+	versionTags   []string
+	branch        string
+	publishVer    string
+	color         *bool
+	brokerRetry   *util.RetryPolicy
+	allowEmpty    bool
+	minVerified   int
+	onInteraction func(r *InteractionResult)
+	formatter     func(r *InteractionResult) string
+	strict        bool
+	schemaErrs    []error
+	ruleErrs      []error
+	harErr        error
+	configErrs    []*configErr
+	changesSince  string
+	scenario      string
+	descPattern   *regexp.Regexp
+	explore       []EndpointSpec
+	warnings      []string
+	result        *VerificationResult
 }
 
 //NewPactFileVerifier creates a new pact verifier. The setup & teardown actions
@@ -43,11 +230,15 @@ func NewPactFileVerifier(setup, teardown Action, config *VerfierConfig) Verifier
 		config = DefaultVerifierConfig
 	}
 
-	return &pactFileVerfier{
+	v := &pactFileVerfier{
 		validator:    newConsumerValidator(setup, teardown, config.Logger),
 		config:       config,
 		stateActions: make(map[string]*stateAction),
+		options:      &validationOptions{},
+		summary:      os.Stdout,
 	}
+	v.validator.SetOptions(v.options)
+	return v
 }
 
 var (
@@ -55,31 +246,279 @@ var (
 	errEmptyProvider               = errors.New("Provider name cannot be empty, please provide a valid value using ServiceProvider function.")
 	errEmptyConsumer               = errors.New("Consumer name cannot be empty, please provide a valid value using HonoursPactWith function.")
 	errVerficationFailed           = errors.New("Failed to verify the pact, please see the log for more details.")
+	errMismatchesMsg               = "Failed to verify the pact, %d interactions failed:\n%s"
+	errNoPactBroker                = errors.New("Consumer version can only be resolved from a pact broker, please provide one using PactBroker function.")
+	errConsumerVersionNotFoundMsg  = "No pact was found on the broker for consumer '%s' at version '%s'."
+	errNoBrokerForPactMsg          = "The pact '%s' can only be read from a pact broker, please provide one using PactBroker function."
+	errNoAliasPactsMsg             = "No pact was found on the broker for consumer '%s' and provider '%s' or its aliases '%s'."
+	errNoPacts                     = errors.New("There is no pact to verify, please provide one using PactUri, AddPact, PactDir, PactReader or PactBroker function.")
+	errPactReaderMixed             = errors.New("The pact read by PactReader cannot be verified with the pacts of PactUri, AddPact, PactDir or PactBroker function.")
+	errNoPactsInDirMsg             = "No pact files were found in the directory '%s'."
+	errNoBrokerForEnvironment      = errors.New("Environment can only be resolved from a pact broker, please provide one using PactBroker function.")
+	errNoDeployedConsumersMsg      = "No consumers of '%s' are currently deployed or released to the '%s' environment, there is nothing to verify."
+	errEmptyPactMsg                = "The pact '%s' has no interactions, please check it was published correctly or use AllowEmptyPact function."
+	errInvalidSchemaMsg            = "The response schema of interaction '%s' is invalid: %s"
+	errInvalidDescPatternMsg       = "The description pattern '%s' is not a valid regular expression: %s"
+	errNoInteractionMsg            = "No interaction with the description '%s' was found in the pacts."
+	errInvalidRuleOverrideMsg      = "The matching rules overriding interaction '%s' are invalid: %s"
+	errWarningsMsg                 = "The verification raised %d warnings, which are treated as errors:\n%s"
+	warningMsg                     = "WARNING: %s"
+	warnEmptyPactMsg               = "The pact '%s' has no interactions, nothing was verified for it."
+	errTooFewInteractionsMsg       = "Only %d interactions were verified, fewer than the minimum of %d set by RequireMinInteractions function."
+	errBrokerMetadataMsg           = "The verification metadata of the pacts could not be fetched from the pact broker: %s"
+	brokerNoticeMsg                = "NOTICE (%s): %s"
+	warnUnusedStateMsg             = "The provider state '%s' has a handler, however no interaction uses it."
+	warnExpectedFailureMsg         = "The interaction '%s' did not match, however it is an expected failure."
+	errNoResultsProviderVersion    = errors.New("The verification results need the provider version, please provide it using WriteVerificationResults function.")
+	errWriteResultsMsg             = "Failed to write the verification results to '%s': %s"
+	errWriteReportMsg              = "Failed to write the verification report to '%s': %s"
+	errNoVersionForTags            = errors.New("The provider version tags and branch need the provider version, please provide it using WriteVerificationResults or PublishResults function.")
+	errNoBrokerForResults          = errors.New("The verification results are published to a pact broker, please provide one using PactBroker function.")
+	errPublishResultsMsg           = "Failed to publish the verification results of the pact '%s' to the pact broker: %s"
+	warnNoPublishLinkMsg           = "The pact '%s' has no pb:publish-verification-results relation, its verification results were not published."
+	errNoBrokerForTags             = errors.New("The provider version tags and branch are created on a pact broker, please provide one using PactBroker function.")
+	errCreateProviderVersionMsg    = "Failed to create the provider version '%s' on the pact broker: %s"
+	warnRuleOverrideMsg            = "The matching rules of interaction '%s' were overridden, the pact was not verified as published."
+	smokeModeNote                  = "Smoke mode: only the status and headers of the responses were verified, the bodies were not."
+)
+
+//the settings reported by the Field of a ConfigError
+const (
+	FieldConsumer        = "consumer"
+	FieldProvider        = "provider"
+	FieldProviderClient  = "providerClient"
+	FieldProviderURL     = "providerURL"
+	FieldPactBroker      = "pactBroker"
+	FieldPactReader      = "pactReader"
+	FieldResponseSchema  = "responseSchema"
+	FieldProviderHAR     = "providerHAR"
+	FieldMatchingRules   = "matchingRules"
+	FieldProviderVersion = "providerVersion"
+)
+
+//pactSource is where a pact to verify is read from
+type pactSource struct {
+	uri    string
+	config *PactUriConfig
+	//optional sources are skipped when there is no pact
+	optional bool
+	//reader reads the pact rather than the uri
+	reader io.PactReader
+	//consumerVersion is the version of the consumer of a broker pact, empty for the latest pact
+	consumerVersion string
+	//broker sources are read from the pact broker, consumer is the consumer of their pact
+	broker   bool
+	consumer string
+}
+
+//origin returns where the pact of the source is read from
+func (s *pactSource) origin() string {
+	if s.broker {
+		return PactOriginBroker
+	} else if s.reader != nil {
+		return PactOriginReader
+	} else if io.IsWebUri(s.uri) {
+		return PactOriginURL
+	}
+	return PactOriginFile
+}
+
+//the origins of the pacts reported by the PactOrigin of an InteractionResult
+const (
+	PactOriginFile   = "file"
+	PactOriginURL    = "url"
+	PactOriginBroker = "broker"
+	PactOriginReader = "reader"
 )
 
-//ServiceProvider provides the information needed to verify the interactions with service provider
+//brokerPactPrefix is the prefix of the AddPact uris read from the pact broker, see BrokerPact
+const brokerPactPrefix = "broker:"
+
+//BrokerPact returns the uri of the pact of the consumer on the pact broker, which AddPact reads from the pact broker
+//of PactBroker along with the other pacts. An empty version is the latest pact of the consumer
+func BrokerPact(consumer, version string) string {
+	if version == "" {
+		return brokerPactPrefix + consumer
+	}
+	return brokerPactPrefix + consumer + "@" + version
+}
+
+//parseBrokerPact returns the consumer and version of a BrokerPact uri, false when the uri is not one
+func parseBrokerPact(uri string) (string, string, bool) {
+	if !strings.HasPrefix(uri, brokerPactPrefix) {
+		return "", "", false
+	}
+	selector := strings.TrimPrefix(uri, brokerPactPrefix)
+	if n := strings.LastIndex(selector, "@"); n >= 0 {
+		return selector[:n], selector[n+1:], true
+	}
+	return selector, "", true
+}
+
+//resultsFile is where the verification results are written for the broker
+type resultsFile struct {
+	path            string
+	providerVersion string
+	buildURL        string
+}
+
+//reportFile is where the report of every verification is written
+type reportFile struct {
+	path   string
+	format ReportFormat
+}
+
+//pactReaderUri names the pact read by PactReader in the results
+const pactReaderUri = "stdin"
+
+//loadedPact is a pact read from its source
+type loadedPact struct {
+	uri  string
+	file *io.PactFile
+	//providerURL overrides the provider url the pact is verified against when set
+	providerURL *url.URL
+	//consumerVersion is the version of the consumer of a broker pact, empty for the latest pact
+	consumerVersion string
+	//origin is where the pact was read from, e.g. PactOriginBroker
+	origin string
+	//metadata is the verification metadata the pact broker returned for the pact
+	metadata *io.BrokerPactMetadata
+}
+
+//ServiceProvider provides the information needed to verify the interactions with service provider, a unix:// url
+//(e.g. unix:///tmp/provider.sock) sends the requests over the unix domain socket
 func (v *pactFileVerfier) ServiceProvider(providerName string, c *http.Client, u *url.URL) Verifier {
 	v.provider = providerName
+	v.harErr = nil
 	v.validator.ProviderService(c, u)
 	return v
 }
 
-//ProviderState sets the setup and teardown action to be executed before a interaction with specific state gets verified
+//ServiceProviderFunc provides the information needed to verify the interactions with service provider, the provider url
+//is resolved when the verification starts
+func (v *pactFileVerfier) ServiceProviderFunc(providerName string, c *http.Client, resolve func() (*url.URL, error)) Verifier {
+	v.provider = providerName
+	v.harErr = nil
+	v.validator.ProviderServiceFunc(c, resolve)
+	return v
+}
+
+//ProviderState sets the setup and teardown action to be executed before a interaction with specific state gets verified,
+//either action can be nil when the state only needs a setup or a teardown
 func (v *pactFileVerfier) ProviderState(state string, setup, teardown Action) Verifier {
 	//sacrificed empty state validation in favor of chaining
+	if state != "" {
+		v.stateActions[state] = newStateAction(setup, teardown)
+	}
+	return v
+}
+
+//DefaultProviderState sets the provider state, e.g. the database is seeded, applied to every interaction in
+//addition to its own state. It is set up after the default setup and before the state of the interaction, and
+//torn down after the state of the interaction and before the AfterInteraction hook
+func (v *pactFileVerfier) DefaultProviderState(state string, setup, teardown Action) Verifier {
+	v.options.defaultState = &defaultState{name: state, action: newStateAction(setup, teardown)}
+	return v
+}
+
+//StateChangeURL sets the url the setup and teardown of a provider state without actions are posted to,
+//as {"state": "...", "action": "setup"} and {"state": "...", "action": "teardown"}
+func (v *pactFileVerfier) StateChangeURL(u *url.URL) Verifier {
+	v.options.stateChangeURL = u
+	return v
+}
+
+//StateNameMapper sets the function mapping the provider state recorded by the consumer, e.g. there is a user with
+//id {23}, to the key of its handler and the state posted to the state change url, e.g. user-23
+func (v *pactFileVerfier) StateNameMapper(f func(description string) string) Verifier {
+	v.options.stateNameMapper = f
+	return v
+}
+
+//SetupOnly sets whether only the setup of a provider state is posted to the state change url
+func (v *pactFileVerfier) SetupOnly(setupOnly bool) Verifier {
+	v.options.stateChangeSetupOnly = setupOnly
+	return v
+}
+
+//ProviderStateContext sets the setup and teardown action, which receive the verification context, to be executed
+//before a interaction with specific state gets verified
+func (v *pactFileVerfier) ProviderStateContext(state string, setup, teardown ContextAction) Verifier {
+	if state != "" {
+		v.stateActions[state] = newContextStateAction(setup, teardown)
+	}
+	return v
+}
+
+//ProviderStateWithValues sets the setup and teardown of a specific state, the values returned by the
+//setup are passed to the teardown so it can clean up exactly what the setup created
+func (v *pactFileVerfier) ProviderStateWithValues(state string, setup StateSetup, teardown StateTeardown) Verifier {
 	if state != "" {
 		v.stateActions[state] = &stateAction{setup: setup, teardown: teardown}
 	}
 	return v
 }
 
+//ProviderStateWithParams sets the setup and teardown of the state named by a v3 or v4 pact, the setup receives the
+//params of the state of the interaction rather than parsing them out of the state name
+func (v *pactFileVerfier) ProviderStateWithParams(state string, setup ParamsAction, teardown Action) Verifier {
+	if state == "" {
+		return v
+	}
+	sa := newStateAction(nil, teardown)
+	if setup != nil {
+		sa.setup = func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, setup(StateParams(ctx))
+		}
+	}
+	v.stateActions[state] = sa
+	return v
+}
+
+//ServiceProviderHAR verifies the interactions against the responses recorded in an HTTP Archive (HAR) file instead
+//of a live provider, the requests are matched to the recorded entries by method and path
+func (v *pactFileVerfier) ServiceProviderHAR(providerName, path string) Verifier {
+	c := &http.Client{}
+	t, err := newHARTransport(path)
+	if err == nil {
+		c.Transport = t
+	}
+	v.ServiceProvider(providerName, c, harURL)
+	v.harErr = err
+	return v
+}
+
 //HonoursPactWith consumer with which pact needs to be honoured
 func (v *pactFileVerfier) HonoursPactWith(consumerName string) Verifier {
 	v.consumer = consumerName
 	return v
 }
 
-//PactUri sets the uri to get the pact file
+//ProviderNameAliases sets the previous names of a renamed provider, the pacts published on the pact broker for
+//any of the names are verified along with the pacts of the provider name
+func (v *pactFileVerfier) ProviderNameAliases(aliases []string) Verifier {
+	v.aliases = aliases
+	return v
+}
+
+//providerNames returns the provider name followed by its aliases
+func (v *pactFileVerfier) providerNames() []string {
+	return append([]string{v.provider}, v.aliases...)
+}
+
+//brokerProvider returns the name the pact of the provider is published under on the pact broker, the alias when
+//the pact names one and the provider name otherwise
+func (v *pactFileVerfier) brokerProvider(f *io.PactFile) string {
+	for _, alias := range v.aliases {
+		if f.Provider != nil && f.Provider.Name == alias {
+			return alias
+		}
+	}
+	return v.provider
+}
+
+//PactUri sets the uri to get the pact file, the pact files (*.json) of a directory uri are all verified in file
+//name order, each with the consumer named in the file
 func (v *pactFileVerfier) PactUri(uri string, config *PactUriConfig) Verifier {
 	if config == nil {
 		config = DefaultPactUriConfig
@@ -89,84 +528,1325 @@ func (v *pactFileVerfier) PactUri(uri string, config *PactUriConfig) Verifier {
 	return v
 }
 
-//VerifyState verifies the consumer interactions for given state and/or description with the provider
-func (v *pactFileVerfier) VerifyState(description string, state string) error {
-	if err := v.verifyInternalState(); err != nil {
-		return err
+//AddPact adds a pact to verify, the interactions of every pact are verified and reported together.
+//The consumer of an added pact is the consumer named in the pact file. The config ProviderURL verifies
+//the pact against another deployment of the provider, sharing the client and provider states. A BrokerPact
+//uri is read from the pact broker, so local and broker pacts can be verified in the same run
+func (v *pactFileVerfier) AddPact(uri string, config *PactUriConfig) Verifier {
+	if config == nil {
+		config = DefaultPactUriConfig
 	}
+	v.pacts = append(v.pacts, &pactSource{uri: uri, config: config})
+	return v
+}
 
-	//get pact file
-	f, err := v.getPactFile()
-	if err != nil {
-		return err
+//PactUris adds the pacts to verify with the default config, see AddPact
+func (v *pactFileVerfier) PactUris(uris ...string) Verifier {
+	for _, uri := range uris {
+		v.AddPact(uri, nil)
 	}
+	return v
+}
 
-	//filter by description
-	if description != "" {
-		var filteredInteractions []*consumer.Interaction
-		for _, val := range f.Interactions {
-			if val.Description == description {
-				filteredInteractions = append(filteredInteractions, val)
-			}
-		}
-		f.Interactions = filteredInteractions
-	}
+//PactDir sets the directory whose pact files (*.json) are all verified, in file name order
+func (v *pactFileVerfier) PactDir(dir string) Verifier {
+	v.pactDir = dir
+	return v
+}
 
-	//filter by state
-	if state != "" {
-		var filteredInteractions []*consumer.Interaction
-		for _, val := range f.Interactions {
-			if val.State == state {
-				filteredInteractions = append(filteredInteractions, val)
-			}
-		}
-		f.Interactions = filteredInteractions
+//PactReader sets the stream, e.g. os.Stdin, the only pact to verify is read from. The stream is read until EOF
+//once, when the pact is first verified
+func (v *pactFileVerfier) PactReader(r goio.Reader) Verifier {
+	v.pactReader = io.NewPactStreamReader(r)
+	return v
+}
+
+//PactBroker sets the pact broker to get the pact file between the consumer and provider from,
+//the latest pact is used unless a consumer version is provided
+func (v *pactFileVerfier) PactBroker(baseURL string, auth *BrokerAuth) Verifier {
+	if auth == nil {
+		auth = &BrokerAuth{}
 	}
+	v.brokerURL = baseURL
+	v.brokerAuth = auth
+	return v
+}
 
-	if (description != "" || state != "") && len(f.Interactions) == 0 {
-		return errNoFilteredInteractionsFound
+//ConsumerVersion sets the consumer version (e.g. git sha) of the pact to get from the pact broker
+func (v *pactFileVerfier) ConsumerVersion(version string) Verifier {
+	v.consumerVer = version
+	return v
+}
+
+//ForEnvironment selects the pacts of the consumer versions currently deployed or released to the environment
+//(e.g. production) on the pact broker. All the consumers of the provider are verified unless HonoursPactWith is used
+func (v *pactFileVerfier) ForEnvironment(name string) Verifier {
+	v.environment = name
+	return v
+}
+
+//ExpectedFailures sets the descriptions of interactions which are known to mismatch, their
+//mismatches are logged as warnings and an interaction which unexpectedly passes fails the verification
+func (v *pactFileVerfier) ExpectedFailures(descriptions []string) Verifier {
+	v.options.expectedFailures = make(map[string]bool, len(descriptions))
+	for _, d := range descriptions {
+		v.options.expectedFailures[d] = true
 	}
-	//validate interactions
-	if ok, err := v.validator.Validate(f, v.stateActions); err != nil {
-		return err
-	} else if !ok {
-		return errVerficationFailed
+	return v
+}
+
+//OnInteraction sets the callback invoked with the result of every interaction once its pact is verified
+func (v *pactFileVerfier) OnInteraction(f func(r *InteractionResult)) Verifier {
+	v.onInteraction = f
+	return v
+}
+
+//MismatchFormatter sets how every failing interaction is rendered in the summary and in the error returned when
+//the verification fails, which then lists the failures instead of referring to the log. When nil the summary uses
+//DefaultMismatchFormatter
+func (v *pactFileVerfier) MismatchFormatter(f func(r *InteractionResult) string) Verifier {
+	v.formatter = f
+	return v
+}
+
+//AfterInteraction sets the hook run once after every verified interaction with its provider states, in the order
+//they were set up, so fixtures can be cleaned up in whichever order they need. It runs after the teardowns of the
+//states and before the default teardown, an error fails the verification like a teardown error
+func (v *pactFileVerfier) AfterInteraction(f func(states []string) error) Verifier {
+	v.options.afterInteraction = f
+	return v
+}
+
+//RequireMinInteractions fails the verification when fewer than n interactions are verified across the pacts, e.g.
+//when a truncated pact or a filter leaves almost nothing to verify. The skipped interactions are not counted
+func (v *pactFileVerfier) RequireMinInteractions(n int) Verifier {
+	v.minVerified = n
+	return v
+}
+
+//AllowEmptyPact sets whether a pact without interactions is verified with a warning, by default
+//it fails the verification since an empty pact is almost always a publishing mistake
+func (v *pactFileVerfier) AllowEmptyPact(allow bool) Verifier {
+	v.allowEmpty = allow
+	return v
+}
+
+//WarningsAsErrors sets whether the verification fails when it raises warnings, e.g. for an empty pact,
+//an unused provider state handler or an expected failure. The warnings are listed in the error
+func (v *pactFileVerfier) WarningsAsErrors(strict bool) Verifier {
+	v.strict = strict
+	return v
+}
+
+//PanicAsFailure sets whether a panic in a setup, teardown or the matching of an interaction fails the interaction,
+//with the panic and its stack trace as the mismatch, so the rest of the verification completes. On by default
+func (v *pactFileVerfier) PanicAsFailure(asFailure bool) Verifier {
+	v.options.propagatePanics = !asFailure
+	return v
+}
+
+//TraceWith sets the tracer used to create spans for the pact download, provider state setups and interactions
+func (v *pactFileVerfier) TraceWith(t Tracer) Verifier {
+	v.options.tracer = t
+	return v
+}
+
+//PropagateTrace sets whether the interaction span context is sent to the provider as W3C trace headers
+func (v *pactFileVerfier) PropagateTrace(propagate bool) Verifier {
+	v.options.propagateTrace = propagate
+	return v
+}
+
+//NoExtraBody sets whether an interaction without an expected body fails when the provider returns a body
+func (v *pactFileVerfier) NoExtraBody(noExtraBody bool) Verifier {
+	v.options.noExtraBody = noExtraBody
+	return v
+}
+
+//StrictArrayLength sets whether arrays in the provider response must have the same length as the
+//example arrays in the pact, which is the default. When false the provider may return additional
+//elements after the examples, e.g. for paginated endpoints, as long as the examples match positionally
+func (v *pactFileVerfier) StrictArrayLength(strict bool) Verifier {
+	v.options.lenientArrays = !strict
+	return v
+}
+
+//StrictEmptyObjects sets whether an empty object {} in the expected body only matches an empty object. By default
+//it matches any object, as the other expected objects match objects with additional keys. An empty array [] only
+//matches an empty array unless StrictArrayLength is false
+func (v *pactFileVerfier) StrictEmptyObjects(strict bool) Verifier {
+	v.options.strictObjects = strict
+	return v
+}
+
+//IgnoreGenerators sets whether the requests are sent with the values recorded in the pact instead of the values
+//produced by their generators, e.g. when the provider accepts the recorded ids and dates
+func (v *pactFileVerfier) IgnoreGenerators(ignore bool) Verifier {
+	v.options.ignoreGenerators = ignore
+	return v
+}
+
+//SignRequests sets the signer which signs every request sent to the provider, it runs last so the signature covers
+//the final headers and body bytes
+func (v *pactFileVerfier) SignRequests(s RequestSigner) Verifier {
+	v.options.signer = s
+	return v
+}
+
+//RequestFilter sets the function changing every request built from an interaction before it is sent to the
+//provider, e.g. to add a live bearer token, rewrite the path or add a header. It runs before the SignRequests
+//signer so the signature covers its changes
+func (v *pactFileVerfier) RequestFilter(f func(req *http.Request)) Verifier {
+	v.options.requestFilter = f
+	return v
+}
+
+//Concurrency sets the number of interactions verified at the same time, 1 by default. The setup, request and teardown
+//of an interaction run in order, while those of other interactions run simultaneously so their provider states must
+//not depend on each other. The results stay in the order of the interactions
+func (v *pactFileVerfier) Concurrency(n int) Verifier {
+	v.options.concurrency = n
+	return v
+}
+
+//ExplainMatches sets whether the result records the matching rule every body value matched by, e.g. $.id matched
+//by type, to confirm the rules are applied rather than the values being equal by coincidence. Off by default
+func (v *pactFileVerfier) ExplainMatches(explain bool) Verifier {
+	v.options.explainMatches = explain
+	return v
+}
+
+//DetailedTimings sets whether the result records the duration of the state setup, the request, the matching and
+//the teardown of every interaction, to find whether the provider, the fixtures or the matching are slow. Off by default
+func (v *pactFileVerfier) DetailedTimings(detailed bool) Verifier {
+	v.options.detailedTimings = detailed
+	return v
+}
+
+//IgnoreResponseHeaders sets the response headers, case-insensitive, which are excluded from the comparison.
+//They replace the default volatile headers (Date, Server, X-Request-Id ...), passing none ignores no headers
+func (v *pactFileVerfier) IgnoreResponseHeaders(headers []string) Verifier {
+	if headers == nil {
+		headers = []string{}
 	}
+	v.options.ignoreHeaders = headers
+	return v
+}
 
-	return nil
+//ForbiddenResponseHeaders sets the response headers, case-insensitive, which fail the verification of every
+//interaction whose response has them, e.g. to ensure the provider does not leak its X-Powered-By version
+func (v *pactFileVerfier) ForbiddenResponseHeaders(headers []string) Verifier {
+	v.options.forbiddenHeaders = headers
+	return v
 }
 
-//Verify verifies all the interactions of consumer with the provider
-func (v *pactFileVerfier) Verify() error {
-	return v.VerifyState("", "")
+//ServerErrorBodyLimit caps the bytes of the body reported when the provider unexpectedly responds with a server
+//error, 512 bytes are reported by default and a negative limit reports no body
+func (v *pactFileVerfier) ServerErrorBodyLimit(limit int) Verifier {
+	v.options.serverErrorBodyLimit = limit
+	return v
+}
+
+//SmokeMode verifies only the status and headers of the responses, their bodies and response schemas are not
+//verified and the result notes it
+func (v *pactFileVerfier) SmokeMode(smoke bool) Verifier {
+	v.options.smoke = smoke
+	return v
+}
+
+//SniffContentType sets whether the response bodies are read as json when json is expected but the provider returns
+//no content type or application/octet-stream, by default an application/octet-stream body is compared as bytes
+func (v *pactFileVerfier) SniffContentType(sniff bool) Verifier {
+	v.options.sniffContentType = sniff
+	return v
+}
+
+//SetFaultInjector sets the function forcing errors at the stages of the verification, e.g. FaultStageDownload.
+//It is for testing only and must not be set in a real verification, nil removes it
+func (v *pactFileVerfier) SetFaultInjector(f FaultInjector) Verifier {
+	v.options.faultInjector = f
+	return v
 }
 
-func (v *pactFileVerfier) getPactFile() (*io.PactFile, error) {
-	var r io.PactReader
-	if io.IsWebUri(v.pactUri) {
-		r = io.NewPactWebReader(v.pactUri, v.pactUriConfig.Username, v.pactUriConfig.Password)
-	} else {
-		r = io.NewPactFileReader(v.pactUri)
+//CorrelationID sends the requests of every interaction with a generated correlation id, recorded in its result
+//and reported with its mismatches to find the requests in the provider logs. The header is
+//DefaultCorrelationIDHeader when empty and the ids are random uuids when generate is nil
+func (v *pactFileVerfier) CorrelationID(header string, generate func() string) Verifier {
+	if header == "" {
+		header = DefaultCorrelationIDHeader
 	}
+	if generate == nil {
+		generate = newCorrelationID
+	}
+	v.options.correlation = &correlation{header: header, generate: generate}
+	return v
+}
 
-	f, err := r.Read()
+//RequestBodyEncoder sets the encoder building the request bodies sent to the provider, an empty content type keeps the
+//recorded one. When nil the body is encoded by the recorded content type
+func (v *pactFileVerfier) RequestBodyEncoder(e BodyEncoder) Verifier {
+	v.options.bodyEncoder = e
+	return v
+}
+
+//Use adds the middleware to the chain wrapping every request sent to the provider, e.g. for logging or metrics.
+//The middlewares run in the order they were added, the first added is the outermost
+func (v *pactFileVerfier) Use(m RequestMiddleware) Verifier {
+	v.options.middlewares = append(v.options.middlewares, m)
+	return v
+}
+
+//ResponseSchema sets the json schema the provider response body of the interaction with the description must
+//validate against, in addition to matching the pact. Each schema violation is reported as a mismatch
+func (v *pactFileVerfier) ResponseSchema(description string, s []byte) Verifier {
+	parsed, err := schema.Parse(s)
 	if err != nil {
-		return nil, err
+		v.schemaErrs = append(v.schemaErrs, fmt.Errorf(errInvalidSchemaMsg, description, err))
+		return v
+	}
+	if v.options.responseSchemas == nil {
+		v.options.responseSchemas = make(map[string]*schema.Schema)
+	}
+	v.options.responseSchemas[description] = parsed
+	return v
+}
+
+//OverrideMatchingRules merges the matching rules into the response rules of the interaction with the description
+//at verification time, the pact is not modified. A rule set replaces the pact rule set of the same category and path,
+//the others of the pact still apply, and as ever the most specific path matching a value takes precedence. Repeated
+//overrides of an interaction are merged in order. A warning is raised, and noted in the result, for every
+//interaction verified with overridden rules
+func (v *pactFileVerfier) OverrideMatchingRules(description string, rules matchers.MatchingRules) Verifier {
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			v.ruleErrs = append(v.ruleErrs, fmt.Errorf(errInvalidRuleOverrideMsg, description, err))
+			return v
+		}
+	}
+	if v.options.ruleOverrides == nil {
+		v.options.ruleOverrides = make(map[string]matchers.MatchingRules)
 	}
+	v.options.ruleOverrides[description] = v.options.ruleOverrides[description].Merge(rules)
+	return v
+}
 
-	if err := f.Validate(); err != nil {
-		return nil, err
+//Retry sets the policy used to retry requests to the provider which fail or are throttled
+func (v *pactFileVerfier) Retry(p *util.RetryPolicy) Verifier {
+	v.options.retry = p
+	return v
+}
+
+//MaxLatency sets the time the provider has to respond to an interaction, a slower response fails the interaction even
+//when it matches. The latency of every interaction is recorded in the result regardless
+func (v *pactFileVerfier) MaxLatency(d time.Duration) Verifier {
+	v.options.maxLatency = d
+	return v
+}
+
+//RequestDelay sets the minimum delay between the requests sent to the provider, including the retries, so a rate
+//limited provider is not overwhelmed. The delay limits the rate of all the requests, even when they are sent at once
+func (v *pactFileVerfier) RequestDelay(d time.Duration) Verifier {
+	v.options.limiter = newRequestLimiter(d)
+	return v
+}
+
+//TransportConfig tunes the keep-alive and timeouts of the connections to the provider without building a custom
+//client, the transport of the provider client is copied with the settings applied
+func (v *pactFileVerfier) TransportConfig(c *TransportConfig) Verifier {
+	v.options.transport = c
+	return v
+}
+
+//MaxConnsPerHost limits the connections opened to the provider, so the requests sent at once do not exhaust the
+//accept queue of the provider, it sets the MaxConnsPerHost of the TransportConfig
+func (v *pactFileVerfier) MaxConnsPerHost(n int) Verifier {
+	tc := TransportConfig{}
+	if v.options.transport != nil {
+		tc = *v.options.transport
 	}
-	return f, nil
+	tc.MaxConnsPerHost = n
+	v.options.transport = &tc
+	return v
 }
 
-func (v *pactFileVerfier) verifyInternalState() error {
-	if v.consumer == "" {
-		return errEmptyConsumer
+//ExploreEndpoints sets the candidate endpoints probed once the interactions are verified, to find the behaviour of
+//the provider the pacts do not cover. The endpoints requested by an interaction are skipped, the responses of the
+//others are reported as the Explorations of the result and do not fail the verification
+func (v *pactFileVerfier) ExploreEndpoints(endpoints []EndpointSpec) Verifier {
+	v.explore = endpoints
+	return v
+}
+
+//TLSServerName sets the server name (SNI) presented in the TLS handshakes with the provider, and which its
+//certificate is verified for, when the dialed host differs, e.g. an ip behind a shared ingress
+func (v *pactFileVerfier) TLSServerName(name string) Verifier {
+	v.options.tlsServerName = name
+	return v
+}
+
+//DialContext sets the function dialing the connections to the provider, e.g. through an SSH tunnel to a provider in
+//a locked-down network, the other settings of the transport of the provider client are kept. Nil dials directly
+func (v *pactFileVerfier) DialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Verifier {
+	v.options.dialer = nil
+	if dial != nil {
+		v.options.dialer = &dialer{dial: dial}
 	}
+	return v
+}
 
-	if v.provider == "" {
-		return errEmptyProvider
+//ProviderAuthHook sets the hook obtaining the headers attached to every request sent to the provider, replacing
+//the recorded ones. It runs before the first interaction and its headers are reused, see ProviderAuthTTL
+func (v *pactFileVerfier) ProviderAuthHook(hook ProviderAuth) Verifier {
+	v.options.auth = hook
+	return v
+}
+
+//ProviderBasicAuth sets the basic auth credentials of the requests sent to the provider, the interactions which
+//record an authorization header and the headers of the provider auth hook take precedence
+func (v *pactFileVerfier) ProviderBasicAuth(username, password string) Verifier {
+	v.options.basicAuth = url.UserPassword(username, password)
+	return v
+}
+
+//ProviderAuthTTL sets how long the headers of the provider auth hook are reused before the hook runs again,
+//by default they are reused for the whole verification
+func (v *pactFileVerfier) ProviderAuthTTL(ttl time.Duration) Verifier {
+	v.options.authTTL = ttl
+	return v
+}
+
+//BrokerRetry sets the policy used to retry requests for pacts from the pact broker or web uri which fail or are throttled
+func (v *pactFileVerfier) BrokerRetry(p *util.RetryPolicy) Verifier {
+	v.brokerRetry = p
+	return v
+}
+
+//UserAgent sets the User-Agent header sent to the provider, unless the interaction specifies one, and to the
+//pact broker. By default util.DefaultUserAgent identifies the requests of the verification
+func (v *pactFileVerfier) UserAgent(ua string) Verifier {
+	v.options.userAgent = ua
+	return v
+}
+
+//TrailingSlash sets how the trailing slash of the interaction paths is handled before they are sent, for providers
+//which route the paths with and without a trailing slash differently. The paths are preserved by default
+func (v *pactFileVerfier) TrailingSlash(p TrailingSlashPolicy) Verifier {
+	v.options.trailingSlash = p
+	return v
+}
+
+//RouteInteractions sets the function selecting the base url each interaction is sent to by its path, e.g. to
+//verify a provider split behind a gateway. When it returns nil the interaction is sent to the provider url. The
+//routed urls are requested with the provider client and cannot be unix socket urls
+func (v *pactFileVerfier) RouteInteractions(f func(path string) (*url.URL, error)) Verifier {
+	v.options.router = f
+	return v
+}
+
+//WaitForProvider polls the health path of the provider every interval until it responds with a 2xx status before
+//the interactions are verified, e.g. for a provider container which is slow to start. The verification fails when
+//the provider is not ready within the timeout
+func (v *pactFileVerfier) WaitForProvider(healthPath string, timeout, interval time.Duration) Verifier {
+	v.options.healthCheck = &healthCheck{path: healthPath, timeout: timeout, interval: interval}
+	return v
+}
+
+//webOptions configures the requests for pacts from the pact broker or a web uri
+func (v *pactFileVerfier) webOptions(username, password string) *io.WebOptions {
+	return &io.WebOptions{Username: username, Password: password, UserAgent: v.options.userAgent, Retry: v.brokerRetry}
+}
+
+//brokerOptions are the web options of the requests to the pact broker, authenticated by its basic auth or token
+func (v *pactFileVerfier) brokerOptions() *io.WebOptions {
+	opts := v.webOptions(v.brokerAuth.Username, v.brokerAuth.Password)
+	opts.Token = v.brokerAuth.Token
+	return opts
+}
+
+//Color sets whether the verification summary is colorized, by default colors are used only when
+//the summary is written to a terminal
+func (v *pactFileVerfier) Color(color bool) Verifier {
+	v.color = &color
+	return v
+}
+
+//SummaryWriter sets where the verification summary is written, defaults to stdout
+func (v *pactFileVerfier) SummaryWriter(w goio.Writer) Verifier {
+	v.summary = w
+	return v
+}
+
+//ReportFile sets the file the report of every verification is written to in the format, e.g. JUnitXML for the test
+//panel of a CI server. The report is written whether the interactions pass or fail
+func (v *pactFileVerfier) ReportFile(path string, format ReportFormat) Verifier {
+	v.reportFile = &reportFile{path: path, format: format}
+	return v
+}
+
+//WriteVerificationResults sets the file the results of every verification are written to in the shape the pact
+//broker publishes them, for the provider version and the url of the build, so they can be published separately
+func (v *pactFileVerfier) WriteVerificationResults(path, providerVersion, buildURL string) Verifier {
+	v.resultsFile = &resultsFile{path: path, providerVersion: providerVersion, buildURL: buildURL}
+	return v
+}
+
+//ProviderVersionTags sets the tags of the provider version of the verification results, e.g. ci or the branch name.
+//The provider version is created on the pact broker with the tags before the results are written, so the tag based
+//selection of the broker works for the provider versions too
+func (v *pactFileVerfier) ProviderVersionTags(tags []string) Verifier {
+	v.versionTags = tags
+	return v
+}
+
+//ProviderBranch sets the branch the provider version of the verification results is created on the pact broker
+//with, before its ProviderVersionTags are added
+func (v *pactFileVerfier) ProviderBranch(branch string) Verifier {
+	v.branch = branch
+	return v
+}
+
+//PublishResults publishes the verification results of the pacts read from the pact broker for the provider
+//version, e.g. the git sha of the provider, so the can-i-deploy matrix of the broker is kept current. The results
+//are published when every interaction reached the provider, whether they matched or not
+func (v *pactFileVerfier) PublishResults(providerVersion string) Verifier {
+	v.publishVer = providerVersion
+	return v
+}
+
+//providerVersion returns the provider version of the verification results, empty when they are neither
+//published nor written
+func (v *pactFileVerfier) providerVersion() string {
+	if v.publishVer != "" {
+		return v.publishVer
+	} else if v.resultsFile != nil {
+		return v.resultsFile.providerVersion
+	}
+	return ""
+}
+
+//publishResults posts the results of every pact read from the pact broker to its pb:publish-verification-results
+//relation
+func (v *pactFileVerfier) publishResults(pacts []*loadedPact) error {
+	if v.publishVer == "" || v.brokerURL == "" {
+		return nil
+	}
+	var buildURL string
+	if v.resultsFile != nil {
+		buildURL = v.resultsFile.buildURL
+	}
+	for _, p := range pacts {
+		if p.origin != PactOriginBroker {
+			continue
+		} else if p.file.PublishResultsURL == "" {
+			v.warn(warnNoPublishLinkMsg, p.uri)
+			continue
+		}
+
+		pr := &VerificationResult{Provider: v.result.Provider}
+		for _, i := range v.result.Interactions {
+			if i.PactUri == p.uri {
+				pr.Interactions = append(pr.Interactions, i)
+			}
+		}
+		err := v.options.injectFault(FaultStagePublish)
+		if err == nil {
+			err = io.PublishVerificationResults(p.file.PublishResultsURL, pr.brokerResults(v.publishVer, buildURL), v.brokerOptions())
+		}
+		if err != nil {
+			return fmt.Errorf(errPublishResultsMsg, p.uri, err)
+		}
+	}
+	return nil
+}
+
+//createProviderVersion creates the provider version of the verification results on the pact broker with its
+//branch and tags
+func (v *pactFileVerfier) createProviderVersion() error {
+	version := v.providerVersion()
+	if version == "" || v.brokerURL == "" || (v.branch == "" && len(v.versionTags) == 0) {
+		return nil
+	}
+	opts := v.brokerOptions()
+	if v.branch != "" {
+		if err := io.BranchPacticipantVersion(v.brokerURL, v.provider, v.branch, version, opts); err != nil {
+			return fmt.Errorf(errCreateProviderVersionMsg, version, err)
+		}
+	}
+	if err := io.TagPacticipantVersion(v.brokerURL, v.provider, version, v.versionTags, opts); err != nil {
+		return fmt.Errorf(errCreateProviderVersionMsg, version, err)
+	}
+	return nil
+}
+
+//writeResultsFile writes the result of the verification to the verification results file
+func (v *pactFileVerfier) writeResultsFile() error {
+	if v.resultsFile == nil {
+		return nil
+	}
+	if err := v.options.injectFault(FaultStagePublish); err != nil {
+		return fmt.Errorf(errWriteResultsMsg, v.resultsFile.path, err)
+	}
+	f, err := os.Create(v.resultsFile.path)
+	if err != nil {
+		return fmt.Errorf(errWriteResultsMsg, v.resultsFile.path, err)
+	}
+	err = v.result.WriteBrokerResults(f, v.resultsFile.providerVersion, v.resultsFile.buildURL)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf(errWriteResultsMsg, v.resultsFile.path, err)
+	}
+	return nil
+}
+
+//writeReportFile writes the report of the verification to the report file when one is set
+func (v *pactFileVerfier) writeReportFile() error {
+	if v.reportFile == nil {
+		return nil
+	}
+	f, err := os.Create(v.reportFile.path)
+	if err != nil {
+		return fmt.Errorf(errWriteReportMsg, v.reportFile.path, err)
+	}
+	err = v.result.writeReport(f, v.reportFile.format)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf(errWriteReportMsg, v.reportFile.path, err)
+	}
+	return nil
+}
+
+//VerifyState verifies the consumer interactions for given state and/or description with the provider, when both
+//are given an interaction must match the description and the state, an empty filter is not applied
+func (v *pactFileVerfier) VerifyState(description string, state string) error {
+	return v.VerifyStateContext(context.Background(), description, state)
+}
+
+//VerifyStateContext verifies the consumer interactions for given state and/or description with the provider,
+//the verification stops when the context is cancelled
+func (v *pactFileVerfier) VerifyStateContext(ctx context.Context, description string, state string) error {
+	if err := v.verifyInternalState(); err != nil {
+		return err
+	}
+
+	//get pact files
+	pacts, err := v.getPactFiles(ctx)
+	if err != nil {
+		return err
+	}
+	return v.verifyPacts(ctx, pacts, description, state)
+}
+
+//verifyPacts verifies the interactions of the loaded pacts matching the state and/or description, the state of
+//the previous verification is reset
+func (v *pactFileVerfier) verifyPacts(ctx context.Context, pacts []*loadedPact, description, state string) error {
+	var err error
+	v.warnings = nil
+	v.options.authCache = nil
+	if err := v.checkEmptyPacts(pacts); err != nil {
+		return err
+	}
+	v.checkUnusedStates(pacts)
+
+	var notes []string
+	if v.changesSince != "" {
+		if notes, err = v.filterUnchanged(pacts); err != nil {
+			return err
+		}
+	}
+
+	filtered := 0
+	for _, p := range pacts {
+		filterInteractions(p.file, description, state)
+		filterScenario(p.file, v.scenario)
+		filterDescriptionPattern(p.file, v.descPattern)
+		filtered += len(p.file.Interactions)
+	}
+	if (description != "" || state != "" || v.scenario != "" || v.descPattern != nil) && filtered == 0 {
+		return errNoFilteredInteractionsFound
+	}
+	notes = append(notes, v.checkRuleOverrides(pacts)...)
+	if v.options.smoke {
+		notes = append(notes, smokeModeNote)
+	}
+
+	//validate interactions, the failure of a pact does not stop the other pacts from being verified
+	valid := true
+	var pactErr error
+	v.result = newVerificationResult(v.provider, pacts)
+	v.result.Notes = notes
+	v.result.Smoke = v.options.smoke
+	for _, p := range pacts {
+		v.validator.OverrideProviderURL(p.providerURL)
+		ok, err := v.validator.ValidateContext(ctx, p.file, v.stateActions)
+		pr := v.validator.Result()
+		v.result.add(p, pr)
+		if v.onInteraction != nil && pr != nil {
+			for _, i := range pr.Interactions {
+				v.onInteraction(i)
+			}
+		}
+		//the errors before any interaction was verified, e.g. the provider is not ready, fail every pact
+		if err != nil && (ctx.Err() != nil || pr == nil || len(pr.Interactions) == 0) {
+			return err
+		} else if err != nil && pactErr == nil {
+			pactErr = err
+		}
+		valid = valid && ok
+	}
+	if endpoints := uncoveredEndpoints(v.explore, pacts); len(endpoints) > 0 {
+		v.validator.OverrideProviderURL(nil)
+		v.result.Explorations = v.validator.Explore(ctx, endpoints)
+	}
+
+	v.writeSummary()
+	//the report is written for the failed verifications too, its error does not replace the verification error
+	reportErr := v.writeReportFile()
+	if reportErr != nil {
+		v.config.Logger.Printf(warningMsg, reportErr)
+	}
+	if pactErr != nil {
+		return pactErr
+	}
+	if verified := len(v.result.Interactions) - len(v.result.Skipped()); verified < v.minVerified {
+		return fmt.Errorf(errTooFewInteractionsMsg, verified, v.minVerified)
+	}
+	if err := v.createProviderVersion(); err != nil {
+		return err
+	}
+	if err := v.writeResultsFile(); err != nil {
+		return err
+	}
+	if err := v.publishResults(pacts); err != nil {
+		return err
+	}
+	if !valid {
+		return v.verificationFailed()
+	}
+	if reportErr != nil {
+		return reportErr
+	}
+	for _, i := range v.result.Interactions {
+		if i.ExpectedFailure && !i.Matched() {
+			v.warnings = append(v.warnings, fmt.Sprintf(warnExpectedFailureMsg, i.Description))
+		}
+	}
+	if v.strict && len(v.warnings) > 0 {
+		return fmt.Errorf(errWarningsMsg, len(v.warnings), "  - "+strings.Join(v.warnings, "\n  - "))
+	}
+	return nil
+}
+
+//mismatchError is the failed verification listing the failing interactions rendered by the MismatchFormatter, it
+//unwraps to errVerficationFailed
+type mismatchError struct {
+	msg string
+}
+
+func (e *mismatchError) Error() string {
+	return e.msg
+}
+
+func (e *mismatchError) Unwrap() error {
+	return errVerficationFailed
+}
+
+//verificationFailed returns the error of a failed verification, errVerficationFailed unless a MismatchFormatter is set
+func (v *pactFileVerfier) verificationFailed() error {
+	if v.formatter == nil {
+		return errVerficationFailed
+	}
+	failures := v.result.Failures()
+	lines := make([]string, len(failures))
+	for n, f := range failures {
+		lines[n] = "  - " + v.formatter(f)
+	}
+	return &mismatchError{msg: fmt.Sprintf(errMismatchesMsg, len(failures), strings.Join(lines, "\n"))}
+}
+
+//warn logs the warning and records it for WarningsAsErrors
+func (v *pactFileVerfier) warn(format string, args ...interface{}) {
+	w := fmt.Sprintf(format, args...)
+	v.warnings = append(v.warnings, w)
+	v.config.Logger.Printf(warningMsg, w)
+}
+
+//checkUnusedStates warns of the provider state handlers which no interaction of the pacts uses
+func (v *pactFileVerfier) checkUnusedStates(pacts []*loadedPact) {
+	used := make(map[string]bool)
+	for _, p := range pacts {
+		for _, i := range p.file.Interactions {
+			used[v.options.stateName(i.State)] = true
+		}
+	}
+
+	var unused []string
+	for s := range v.stateActions {
+		if !used[s] {
+			unused = append(unused, s)
+		}
+	}
+	sort.Strings(unused)
+	for _, s := range unused {
+		v.warn(warnUnusedStateMsg, s)
+	}
+}
+
+//checkRuleOverrides warns of the interactions to verify whose matching rules are overridden, the warnings are
+//returned to be noted in the result
+func (v *pactFileVerfier) checkRuleOverrides(pacts []*loadedPact) []string {
+	var notes []string
+	warned := make(map[string]bool)
+	for _, p := range pacts {
+		for _, i := range p.file.Interactions {
+			if _, ok := v.options.ruleOverrides[i.Description]; ok && !warned[i.Description] {
+				warned[i.Description] = true
+				v.warn(warnRuleOverrideMsg, i.Description)
+				notes = append(notes, fmt.Sprintf(warnRuleOverrideMsg, i.Description))
+			}
+		}
+	}
+	return notes
+}
+
+//Result returns the outcome of the interactions verified by the last verification, the interactions
+//of every pact are consolidated into the one result
+func (v *pactFileVerfier) Result() *VerificationResult {
+	return v.result
+}
+
+//checkEmptyPacts fails the verification when a pact has no interactions, unless empty pacts are allowed
+func (v *pactFileVerfier) checkEmptyPacts(pacts []*loadedPact) error {
+	for _, p := range pacts {
+		if len(p.file.Interactions) != 0 {
+			continue
+		}
+		if !v.allowEmpty {
+			return fmt.Errorf(errEmptyPactMsg, p.uri)
+		}
+		v.warn(warnEmptyPactMsg, p.uri)
+	}
+	return nil
+}
+
+//filterInteractions keeps the interactions of the pact matching the description and the state, an empty filter matches all
+func filterInteractions(f *io.PactFile, description, state string) {
+	//filter by description
+	if description != "" {
+		var filteredInteractions []*consumer.Interaction
+		for _, val := range f.Interactions {
+			if val.Description == description {
+				filteredInteractions = append(filteredInteractions, val)
+			}
+		}
+		f.Interactions = filteredInteractions
+	}
+
+	//filter by state
+	if state != "" {
+		var filteredInteractions []*consumer.Interaction
+		for _, val := range f.Interactions {
+			if val.State == state {
+				filteredInteractions = append(filteredInteractions, val)
+			}
+		}
+		f.Interactions = filteredInteractions
+	}
+}
+
+//filterScenario keeps the interactions of the pact tagged with the scenario, an empty scenario matches all
+func filterScenario(f *io.PactFile, scenario string) {
+	if scenario == "" {
+		return
+	}
+	var filteredInteractions []*consumer.Interaction
+	for _, val := range f.Interactions {
+		if val.Scenario() == scenario {
+			filteredInteractions = append(filteredInteractions, val)
+		}
+	}
+	f.Interactions = filteredInteractions
+}
+
+//filterDescriptionPattern keeps the interactions of the pact whose description matches the pattern, a nil pattern
+//matches all
+func filterDescriptionPattern(f *io.PactFile, re *regexp.Regexp) {
+	if re == nil {
+		return
+	}
+	var filteredInteractions []*consumer.Interaction
+	for _, val := range f.Interactions {
+		if re.MatchString(val.Description) {
+			filteredInteractions = append(filteredInteractions, val)
+		}
+	}
+	f.Interactions = filteredInteractions
+}
+
+func (v *pactFileVerfier) writeSummary() {
+	if v.summary == nil {
+		return
+	}
+	color := isTerminal(v.summary)
+	if v.color != nil {
+		color = *v.color
+	}
+	writeSummary(v.summary, v.Result(), color, v.formatter)
+}
+
+//Verify verifies all the interactions of consumer with the provider
+func (v *pactFileVerfier) Verify() error {
+	return v.VerifyState("", "")
+}
+
+//VerifyScenario verifies the interactions tagged with the scenario in their metadata, e.g. the calls of a checkout
+//flow. The interactions of each pact are verified as a sequence in the order they are recorded
+func (v *pactFileVerfier) VerifyScenario(name string) error {
+	v.scenario, v.options.sequential = name, true
+	defer func() { v.scenario, v.options.sequential = "", false }()
+	return v.Verify()
+}
+
+//VerifyDescriptionPattern verifies the interactions whose description matches the regular expression, e.g.
+//^GET /users verifies every interaction whose description starts with GET /users
+func (v *pactFileVerfier) VerifyDescriptionPattern(re string) error {
+	pattern, err := regexp.Compile(re)
+	if err != nil {
+		return fmt.Errorf(errInvalidDescPatternMsg, re, err)
+	}
+	v.descPattern = pattern
+	defer func() { v.descPattern = nil }()
+	return v.Verify()
+}
+
+//VerifyT verifies all the interactions of consumer with the provider and reports each interaction as a
+//sub-test of t, named after its description and the consumer test which generated it when recorded
+func (v *pactFileVerfier) VerifyT(t *testing.T) {
+	if err := v.Verify(); err != nil && !errors.Is(err, errVerficationFailed) {
+		t.Fatal(err)
+	}
+	for _, r := range v.result.Interactions {
+		r := r
+		t.Run(subTestName(r), func(t *testing.T) {
+			if r.Skipped {
+				t.Skip(statusSkipped)
+			} else if r.Failed() {
+				t.Error(strings.Join(r.mismatches(), "\n"))
+			}
+		})
+	}
+}
+
+//VerifyB verifies all the interactions of consumer with the provider b.N times, so the verification can be run
+//as a benchmark. The pacts are loaded once before the timer starts and the connections to the provider are
+//reused, the summary is not written. The benchmark fails when an iteration does not verify
+func (v *pactFileVerfier) VerifyB(b *testing.B) {
+	if err := v.verifyInternalState(); err != nil {
+		b.Fatal(err)
+	}
+	pacts, err := v.getPactFiles(context.Background())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	summary := v.summary
+	v.summary = nil
+	defer func() { v.summary = summary }()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		err := v.verifyPacts(context.Background(), pacts, "", "")
+		if errors.Is(err, errVerficationFailed) {
+			b.StopTimer()
+			for _, r := range v.result.Failures() {
+				b.Error(r.Description + "\n" + strings.Join(r.mismatches(), "\n"))
+			}
+			b.FailNow()
+		} else if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+//PreviewRequest returns the request which the verification sends to the provider for the interaction with the
+//description, including the provider url, headers and encoded body, without sending it. The provider auth hook
+//runs to set the auth headers. The interaction of the first pact with the description is previewed
+func (v *pactFileVerfier) PreviewRequest(description string) (*http.Request, error) {
+	if err := v.verifyInternalState(); err != nil {
+		return nil, err
+	}
+	pacts, err := v.getPactFiles(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	v.options.authCache = nil
+	for _, p := range pacts {
+		for _, i := range p.file.Interactions {
+			if i.Description == description {
+				v.validator.OverrideProviderURL(p.providerURL)
+				return v.validator.PreviewRequest(i)
+			}
+		}
+	}
+	return nil, fmt.Errorf(errNoInteractionMsg, description)
+}
+
+//subTestName is the name of the sub-test verifying the interaction
+func subTestName(r *InteractionResult) string {
+	if r.TestName == "" {
+		return r.Description
+	}
+	return fmt.Sprintf("%s (%s)", r.Description, r.TestName)
+}
+
+//VerifyContext verifies all the interactions of consumer with the provider, the verification stops when the context is cancelled
+func (v *pactFileVerfier) VerifyContext(ctx context.Context) error {
+	return v.VerifyStateContext(ctx, "", "")
+}
+
+//Build checks the verifier is configured correctly, without fetching the pacts, so a misconfiguration is
+//caught before any expensive setup. The error is a *ConfigError
+func (v *pactFileVerfier) Build() error {
+	return v.verifyInternalState()
+}
+
+//Validate checks the configuration, that the pact can be loaded and that every provider state defined
+//by the consumer has been supplied, without executing any actions or sending requests to the provider
+func (v *pactFileVerfier) Validate() []error {
+	issues := v.configurationIssues()
+
+	pacts, err := v.getPactFiles(context.Background())
+	if err != nil {
+		return append(issues, err)
+	}
+
+	missing := make(map[string]bool)
+	for _, p := range pacts {
+		for _, i := range p.file.Interactions {
+			state := v.options.stateName(i.State)
+			if i.State != "" && v.stateActions[state] == nil && v.options.stateChangeURL == nil && !missing[state] {
+				missing[state] = true
+				issues = append(issues, fmt.Errorf(errNotFoundProviderStateMsg, state))
+			}
+		}
+	}
+	return issues
+}
+
+//pactSources returns the pacts to verify, the pact uri comes first followed by the added pacts and
+//the pacts of the pact directory in file name order
+func (v *pactFileVerfier) pactSources() ([]*pactSource, error) {
+	if v.pactReader != nil {
+		return []*pactSource{&pactSource{uri: pactReaderUri, reader: v.pactReader}}, nil
+	}
+
+	var sources []*pactSource
+	if v.brokerURL != "" && v.environment != "" {
+		es, err := v.environmentSources()
+		if err != nil {
+			return nil, err
+		}
+		sources = es
+	} else if v.brokerURL != "" && v.consumer != "" {
+		//with aliases the pact of any of the names is enough
+		for _, name := range v.providerNames() {
+			sources = append(sources, &pactSource{uri: io.BrokerPactUri(v.brokerURL, name, v.consumer, v.consumerVer),
+				consumerVersion: v.consumerVer, optional: len(v.aliases) > 0, broker: true, consumer: v.consumer})
+		}
+	}
+
+	if isDir(v.pactUri) {
+		files, err := pactFiles(v.pactUri)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			sources = append(sources, &pactSource{uri: f, config: v.pactUriConfig})
+		}
+	} else if v.pactUri != "" {
+		sources = append(sources, &pactSource{uri: v.pactUri, config: v.pactUriConfig})
+	}
+	for _, s := range v.pacts {
+		if consumer, version, ok := parseBrokerPact(s.uri); ok {
+			s = &pactSource{uri: io.BrokerPactUri(v.brokerURL, v.provider, consumer, version), config: s.config,
+				consumerVersion: version, broker: true, consumer: consumer}
+		}
+		sources = append(sources, s)
+	}
+
+	if v.pactDir != "" {
+		files, err := pactFiles(v.pactDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			sources = append(sources, &pactSource{uri: f, config: DefaultPactUriConfig})
+		}
+	}
+
+	if len(sources) == 0 && v.environment == "" {
+		return nil, errNoPacts
+	}
+	return sources, nil
+}
+
+//pactFiles returns the pact files (*.json) of the directory in file name order, an error when there are none
+func pactFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	} else if len(files) == 0 {
+		return nil, fmt.Errorf(errNoPactsInDirMsg, dir)
+	}
+	return files, nil
+}
+
+//isDir returns true when the path is a local directory
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+//environmentSources returns the pacts of the consumer versions in the environment, every pacticipant version
+//is a possible consumer so a missing pact is skipped
+func (v *pactFileVerfier) environmentSources() ([]*pactSource, error) {
+	versions, err := io.BrokerEnvironmentVersions(v.brokerURL, v.environment, v.brokerOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make(map[string]bool)
+	for _, name := range v.providerNames() {
+		providers[name] = true
+	}
+	var sources []*pactSource
+	for _, pv := range versions {
+		if providers[pv.Pacticipant] || (v.consumer != "" && pv.Pacticipant != v.consumer) {
+			continue
+		}
+		for _, name := range v.providerNames() {
+			uri := io.BrokerPactUri(v.brokerURL, name, pv.Pacticipant, pv.Version)
+			sources = append(sources, &pactSource{uri: uri, optional: true, consumerVersion: pv.Version, broker: true,
+				consumer: pv.Pacticipant})
+		}
+	}
+	return sources, nil
+}
+
+func (v *pactFileVerfier) getPactFiles(ctx context.Context) ([]*loadedPact, error) {
+	sources, err := v.pactSources()
+	if err != nil {
+		return nil, err
+	}
+
+	var pacts []*loadedPact
+	//optional counts the pacts of the environment or the provider aliases which were found
+	optional := 0
+	for _, s := range sources {
+		f, err := v.getPactFile(ctx, s)
+		if _, ok := err.(*io.NotFoundError); ok && s.optional {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		p := &loadedPact{uri: s.uri, file: f, consumerVersion: s.consumerVersion, origin: s.origin()}
+		if s.config != nil {
+			p.providerURL = s.config.ProviderURL
+		}
+		if s.optional {
+			optional++
+		}
+		pacts = append(pacts, p)
+	}
+
+	if v.environment != "" && optional == 0 {
+		return nil, fmt.Errorf(errNoDeployedConsumersMsg, v.provider, v.environment)
+	} else if v.brokerURL != "" && v.consumer != "" && len(v.aliases) > 0 && optional == 0 {
+		return nil, fmt.Errorf(errNoAliasPactsMsg, v.consumer, v.provider, strings.Join(v.aliases, "', '"))
+	}
+	if v.brokerURL != "" {
+		v.addBrokerMetadata(pacts)
+	}
+	return pacts, nil
+}
+
+//addBrokerMetadata adds the verification metadata of the pact broker to the pacts and logs its notices, the
+//pacts are verified without metadata when the broker cannot return it
+func (v *pactFileVerfier) addBrokerMetadata(pacts []*loadedPact) {
+	var consumers []string
+	seen := make(map[string]bool)
+	for _, p := range pacts {
+		if name := p.file.Consumer.Name; p.origin == PactOriginBroker && v.environment == "" && !seen[name] {
+			seen[name] = true
+			consumers = append(consumers, name)
+		}
+	}
+	if v.environment == "" && len(consumers) == 0 {
+		return
+	}
+	metadata, err := io.BrokerPactsForVerification(v.brokerURL, v.provider, consumers, v.environment,
+		v.brokerOptions())
+	if err != nil {
+		v.config.Logger.Printf(errBrokerMetadataMsg, err)
+		return
+	}
+
+	for _, p := range pacts {
+		if p.origin != PactOriginBroker {
+			continue
+		}
+		for _, m := range metadata {
+			if m.Consumer == p.file.Consumer.Name && (p.consumerVersion == "" || m.ConsumerVersion == p.consumerVersion) {
+				p.metadata = m
+				break
+			}
+		}
+		if p.metadata == nil {
+			continue
+		}
+		for _, n := range p.metadata.Notices {
+			v.config.Logger.Printf(brokerNoticeMsg, p.file.Consumer.Name, n.Text)
+		}
+	}
+}
+
+func (v *pactFileVerfier) getPactFile(ctx context.Context, s *pactSource) (*io.PactFile, error) {
+	_, span := v.options.getTracer().Start(ctx, spanPactDownload)
+	defer span.End()
+	span.SetAttribute(attrPactUri, s.uri)
+
+	if err := v.options.injectFault(FaultStageDownload); err != nil {
+		span.SetAttribute(attrOutcome, outcomeOf(true, err))
+		return nil, err
+	}
+	f, err := v.readPactFile(s)
+	span.SetAttribute(attrOutcome, outcomeOf(true, err))
+	return f, err
+}
+
+func (v *pactFileVerfier) readPactFile(s *pactSource) (*io.PactFile, error) {
+	if s.broker {
+		return v.readBrokerPactFile(s)
+	}
+
+	r := s.reader
+	if r == nil && io.IsWebUri(s.uri) {
+		r = io.NewPactWebReaderWithOptions(s.uri, v.webOptions(s.config.Username, s.config.Password))
+	} else if r == nil {
+		r = io.NewPactFileReader(s.uri)
+	}
+
+	f, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (v *pactFileVerfier) readBrokerPactFile(s *pactSource) (*io.PactFile, error) {
+	f, err := io.NewPactWebReaderWithOptions(s.uri, v.brokerOptions()).Read()
+	if _, ok := err.(*io.NotFoundError); ok && s.consumerVersion != "" && !s.optional {
+		return nil, fmt.Errorf(errConsumerVersionNotFoundMsg, s.consumer, s.consumerVersion)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (v *pactFileVerfier) verifyInternalState() error {
+	if issues := v.configurationIssues(); len(issues) > 0 {
+		return issues[0]
+	}
+	return nil
+}
+
+//requiresConsumer returns false when the consumers are taken from the added pact files or the broker environment
+func (v *pactFileVerfier) requiresConsumer() bool {
+	if v.pactReader != nil {
+		return false
+	} else if v.brokerURL != "" && v.environment != "" {
+		return false
+	}
+	return len(v.pacts) == 0 && v.pactDir == "" && !isDir(v.pactUri)
+}
+
+func (v *pactFileVerfier) configurationIssues() []error {
+	var issues []error
+	issue := func(field string, err error) {
+		issues = append(issues, &ConfigError{Field: field, Consumer: v.consumer, Provider: v.provider, Err: err})
+	}
+
+	if v.consumer == "" && v.requiresConsumer() {
+		issue(FieldConsumer, errEmptyConsumer)
+	}
+
+	if v.provider == "" {
+		issue(FieldProvider, errEmptyProvider)
+	}
+
+	if v.consumerVer != "" && v.brokerURL == "" {
+		issue(FieldPactBroker, errNoPactBroker)
+	}
+
+	if v.environment != "" && v.brokerURL == "" {
+		issue(FieldPactBroker, errNoBrokerForEnvironment)
+	}
+
+	for _, s := range v.pacts {
+		if _, _, ok := parseBrokerPact(s.uri); ok && v.brokerURL == "" {
+			issue(FieldPactBroker, fmt.Errorf(errNoBrokerForPactMsg, s.uri))
+		}
+	}
+
+	if v.resultsFile != nil && v.resultsFile.providerVersion == "" {
+		issue(FieldProviderVersion, errNoResultsProviderVersion)
+	}
+	if len(v.versionTags) > 0 || v.branch != "" {
+		if v.providerVersion() == "" {
+			issue(FieldProviderVersion, errNoVersionForTags)
+		}
+		if v.brokerURL == "" {
+			issue(FieldPactBroker, errNoBrokerForTags)
+		}
+	}
+	if v.publishVer != "" && v.brokerURL == "" {
+		issue(FieldPactBroker, errNoBrokerForResults)
+	}
+
+	if v.pactReader != nil && (v.pactUri != "" || len(v.pacts) > 0 || v.pactDir != "" || v.brokerURL != "") {
+		issue(FieldPactReader, errPactReaderMixed)
+	}
+
+	if err := v.validator.CanValidate(); err == errNilProviderClient {
+		issue(FieldProviderClient, err)
+	} else if err != nil {
+		issue(FieldProviderURL, err)
+	}
+
+	if v.harErr != nil {
+		issue(FieldProviderHAR, v.harErr)
+	}
+
+	for _, e := range v.configErrs {
+		issue(e.field, e.err)
+	}
+	for _, err := range v.schemaErrs {
+		issue(FieldResponseSchema, err)
+	}
+	for _, err := range v.ruleErrs {
+		issue(FieldMatchingRules, err)
 	}
-	return v.validator.CanValidate()
+	return issues
 }
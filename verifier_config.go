@@ -0,0 +1,155 @@
+package pact
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/util"
+)
+
+//FieldStateChangeURL is the setting reported by the ConfigError of an invalid state change url
+const FieldStateChangeURL = "stateChangeURL"
+
+//VerifierConfig holds the settings of a verification in a single struct, which can be unmarshalled from the json or
+//yaml settings of an environment and applied with Configure. Unlike the VerfierConfig of NewPactFileVerifier, its
+//fields mirror the builder functions, a zero field leaves the setting as it is
+type VerifierConfig struct {
+	Consumer string `json:"consumer"`
+	Provider string `json:"provider"`
+	//ProviderAliases are the previous names of the provider on the pact broker
+	ProviderAliases []string `json:"providerAliases"`
+	//ProviderURL is requested with a default http client, a unix:// url dials a unix socket
+	ProviderURL string   `json:"providerUrl"`
+	PactURIs    []string `json:"pactUris"`
+	PactDir     string   `json:"pactDir"`
+
+	BrokerURL       string `json:"brokerUrl"`
+	BrokerUsername  string `json:"brokerUsername"`
+	BrokerPassword  string `json:"brokerPassword"`
+	BrokerToken     string `json:"brokerToken"`
+	ConsumerVersion string `json:"consumerVersion"`
+	Environment     string `json:"environment"`
+	PublishResults  string `json:"publishResults"`
+
+	StateChangeURL           string   `json:"stateChangeUrl"`
+	ExpectedFailures         []string `json:"expectedFailures"`
+	IgnoreResponseHeaders    []string `json:"ignoreResponseHeaders"`
+	ForbiddenResponseHeaders []string `json:"forbiddenResponseHeaders"`
+	NoExtraBody              bool     `json:"noExtraBody"`
+	AllowEmptyPact           bool     `json:"allowEmptyPact"`
+	WarningsAsErrors         bool     `json:"warningsAsErrors"`
+	SmokeMode                bool     `json:"smokeMode"`
+	MinInteractions          int      `json:"minInteractions"`
+	Concurrency              int      `json:"concurrency"`
+
+	//the durations are nanoseconds in json
+	Retry        *util.RetryPolicy `json:"retry"`
+	BrokerRetry  *util.RetryPolicy `json:"brokerRetry"`
+	MaxLatency   time.Duration     `json:"maxLatency"`
+	RequestDelay time.Duration     `json:"requestDelay"`
+	Transport    *TransportConfig  `json:"transport"`
+	UserAgent    string            `json:"userAgent"`
+}
+
+//configErr is an invalid setting of a VerifierConfig, reported when the verification starts
+type configErr struct {
+	field string
+	err   error
+}
+
+//Configure applies all the settings of the config at once, the builder functions called afterwards override them.
+//An invalid url fails the verification with a ConfigError
+func (v *pactFileVerfier) Configure(cfg VerifierConfig) Verifier {
+	v.configErrs = nil
+	if cfg.Consumer != "" {
+		v.HonoursPactWith(cfg.Consumer)
+	}
+	if cfg.ProviderURL != "" {
+		if u, err := url.Parse(cfg.ProviderURL); err != nil {
+			v.configErrs = append(v.configErrs, &configErr{field: FieldProviderURL, err: err})
+		} else {
+			v.ServiceProvider(cfg.Provider, &http.Client{}, u)
+		}
+	}
+	if cfg.Provider != "" {
+		v.provider = cfg.Provider
+	}
+	if cfg.ProviderAliases != nil {
+		v.ProviderNameAliases(cfg.ProviderAliases)
+	}
+	for _, uri := range cfg.PactURIs {
+		v.AddPact(uri, nil)
+	}
+	if cfg.PactDir != "" {
+		v.PactDir(cfg.PactDir)
+	}
+
+	if cfg.BrokerURL != "" {
+		v.PactBroker(cfg.BrokerURL, &BrokerAuth{Username: cfg.BrokerUsername, Password: cfg.BrokerPassword, Token: cfg.BrokerToken})
+	}
+	if cfg.ConsumerVersion != "" {
+		v.ConsumerVersion(cfg.ConsumerVersion)
+	}
+	if cfg.Environment != "" {
+		v.ForEnvironment(cfg.Environment)
+	}
+	if cfg.PublishResults != "" {
+		v.PublishResults(cfg.PublishResults)
+	}
+
+	if cfg.StateChangeURL != "" {
+		if u, err := url.Parse(cfg.StateChangeURL); err != nil {
+			v.configErrs = append(v.configErrs, &configErr{field: FieldStateChangeURL, err: err})
+		} else {
+			v.StateChangeURL(u)
+		}
+	}
+	if cfg.ExpectedFailures != nil {
+		v.ExpectedFailures(cfg.ExpectedFailures)
+	}
+	if cfg.IgnoreResponseHeaders != nil {
+		v.IgnoreResponseHeaders(cfg.IgnoreResponseHeaders)
+	}
+	if cfg.ForbiddenResponseHeaders != nil {
+		v.ForbiddenResponseHeaders(cfg.ForbiddenResponseHeaders)
+	}
+	if cfg.NoExtraBody {
+		v.NoExtraBody(true)
+	}
+	if cfg.AllowEmptyPact {
+		v.AllowEmptyPact(true)
+	}
+	if cfg.WarningsAsErrors {
+		v.WarningsAsErrors(true)
+	}
+	if cfg.SmokeMode {
+		v.SmokeMode(true)
+	}
+	if cfg.MinInteractions > 0 {
+		v.RequireMinInteractions(cfg.MinInteractions)
+	}
+	if cfg.Concurrency > 0 {
+		v.Concurrency(cfg.Concurrency)
+	}
+
+	if cfg.Retry != nil {
+		v.Retry(cfg.Retry)
+	}
+	if cfg.BrokerRetry != nil {
+		v.BrokerRetry(cfg.BrokerRetry)
+	}
+	if cfg.MaxLatency > 0 {
+		v.MaxLatency(cfg.MaxLatency)
+	}
+	if cfg.RequestDelay > 0 {
+		v.RequestDelay(cfg.RequestDelay)
+	}
+	if cfg.Transport != nil {
+		v.TransportConfig(cfg.Transport)
+	}
+	if cfg.UserAgent != "" {
+		v.UserAgent(cfg.UserAgent)
+	}
+	return v
+}
@@ -0,0 +1,100 @@
+package pact
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_Verifier_Configure_VerifiesFromConfig(t *testing.T) {
+	var changes, agents []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		userHandlerWithValidData(w, r)
+	})
+	mux.HandleFunc("/provider-states", func(w http.ResponseWriter, r *http.Request) {
+		var change stateChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		changes = append(changes, change.Action+" "+change.State)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var cfg VerifierConfig
+	raw := fmt.Sprintf(`{
+		"consumer": "chrome browser",
+		"provider": "go api",
+		"providerUrl": %q,
+		"pactUris": ["./pact_examples/chrome_browser-go_api.json"],
+		"stateChangeUrl": %q,
+		"forbiddenResponseHeaders": ["X-Debug-Token"],
+		"retry": {"attempts": 2},
+		"userAgent": "config-test"
+	}`, server.URL, server.URL+"/provider-states")
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		Configure(cfg).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 4 {
+		t.Errorf("expected the states to be changed by the configured url, got %v", changes)
+	}
+	if len(agents) != 2 || agents[0] != "config-test" {
+		t.Errorf("expected the configured user agent, got %v", agents)
+	}
+	if r := v.Result(); len(r.Interactions) != 2 || r.Interactions[0].ProviderURL != server.URL {
+		t.Errorf("expected the interactions to be verified against %s, got %+v", server.URL, r.Interactions)
+	}
+}
+
+func Test_Verifier_Configure_BuildersOverrideConfig(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		Configure(VerifierConfig{
+			Consumer:    "chrome browser",
+			Provider:    "go api",
+			ProviderURL: "http://localhost:1",
+			PactURIs:    []string{"./pact_examples/chrome_browser-go_api.json"},
+		}).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Errorf("expected the provider url set by ServiceProvider to be verified, got %s", err)
+	}
+}
+
+func Test_Verifier_Configure_InvalidURLIsAConfigError(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		Configure(VerifierConfig{
+			Consumer:       "chrome browser",
+			Provider:       "go api",
+			ProviderURL:    "http://localhost",
+			PactURIs:       []string{"./pact_examples/chrome_browser-go_api.json"},
+			StateChangeURL: "http://local host:%zz",
+		})
+
+	var cerr *ConfigError
+	if err := v.Verify(); !errors.As(err, &cerr) || cerr.Field != FieldStateChangeURL {
+		t.Errorf("expected a state change url config error, got %v", err)
+	}
+}
@@ -1,14 +1,34 @@
 package pact
 
 import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	goio "io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+	"github.com/SEEK-Jobs/pact-go/matchers"
+	"github.com/SEEK-Jobs/pact-go/util"
 )
 
 var (
@@ -154,18 +174,42 @@ func Test_Verifier_ThrowsError_ConsumerNotSet(t *testing.T) {
 
 	if err := v.Verify(); err == nil {
 		t.Error("Expected empty conusmer name error")
-	} else if err != errEmptyConsumer {
+	} else if !errors.Is(err, errEmptyConsumer) {
 		t.Errorf("Expected %s, got %s", errEmptyConsumer, err)
 	}
 }
 
+func Test_Verifier_ConfigError_ExposesMissingField(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil)
+
+	var cerr *ConfigError
+	if err := v.Build(); !errors.As(err, &cerr) {
+		t.Fatalf("expected a config error, got %v", err)
+	}
+	if cerr.Field != FieldProvider || cerr.Consumer != "chrome browser" || cerr.Provider != "" || cerr.Error() != errEmptyProvider.Error() {
+		t.Errorf("unexpected config error %#v", cerr)
+	}
+
+	v.ServiceProvider("go api", &http.Client{}, nil)
+	if err := v.Build(); !errors.As(err, &cerr) || cerr.Field != FieldProviderURL || cerr.Provider != "go api" || !errors.Is(err, errNilProviderURL) {
+		t.Errorf("expected the missing provider url, got %v", err)
+	}
+
+	v.ServiceProvider("go api", &http.Client{}, &url.URL{})
+	if err := v.Build(); err != nil {
+		t.Errorf("expected the verifier to build, got %v", err)
+	}
+}
+
 func Test_Verifier_ThrowsError_ProviderNotSet(t *testing.T) {
 	v := NewPactFileVerifier(nil, nil, nil).
 		HonoursPactWith("consumer")
 
 	if err := v.Verify(); err == nil {
 		t.Error("Expected empty provider name error")
-	} else if err != errEmptyProvider {
+	} else if !errors.Is(err, errEmptyProvider) {
 		t.Errorf("Expected %s, got %s", errEmptyProvider, err)
 	}
 }
@@ -184,3 +228,3578 @@ func Test_Verifier_ThrowsError_NoFilteredInteractions(t *testing.T) {
 		t.Errorf("Expected %s, got %s", errNoFilteredInteractionsFound, err)
 	}
 }
+
+func Test_Verifier_ExpectedFailures_DowngradesMismatches(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ExpectedFailures([]string{"get request for user with id {23}"})
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Verifier_ExpectedFailures_FailsOnUnexpectedPass(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ExpectedFailures([]string{"get request for user with id {23}"})
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s, got %v", errVerficationFailed, err)
+	}
+}
+
+func Test_Verifier_CanVerifyConsumerVersionFromBroker(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/version/4f2a9c1", pactServer)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactBroker(server.URL, nil).
+		ConsumerVersion("4f2a9c1").
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil)
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Verifier_ProviderNameAliases_VerifiesPactsOfTheAliases(t *testing.T) {
+	legacyPact := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadFile("./pact_examples/chrome_browser-go_api.json")
+		w.Write(bytes.Replace(b, []byte(`"go api"`), []byte(`"legacy api"`), 1))
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/pacts/provider/legacy api/consumer/chrome browser/latest", legacyPact)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	verifier := func(aliases ...string) Verifier {
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactBroker(server.URL, nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderNameAliases(aliases).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			SummaryWriter(ioutil.Discard)
+	}
+
+	v := verifier("old api", "legacy api")
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	r := v.Result()
+	if len(r.Interactions) != 2 || !strings.Contains(r.Interactions[0].PactUri, "/pacts/provider/legacy%20api/") {
+		t.Errorf("expected the 2 interactions of the pact of the alias, got %d from %v", len(r.Interactions), r.Interactions)
+	}
+
+	if err := verifier().Verify(); err == nil {
+		t.Error("expected the pact of the alias to be skipped without the alias")
+	}
+	expected := fmt.Sprintf(errNoAliasPactsMsg, "chrome browser", "go api", "old api")
+	if err := verifier("old api").Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_AddPact_VerifiesLocalAndBrokerPactsTogether(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/version/4f2a9c1", pactServer)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		PactBroker(server.URL, nil).
+		AddPact("./pact_examples/go_api/android_app-go_api.json", nil).
+		AddPact(BrokerPact("chrome browser", "4f2a9c1"), nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	origins := make(map[string]string)
+	for _, i := range v.Result().Interactions {
+		origins[i.Consumer] = i.PactOrigin
+	}
+	if origins["android app"] != PactOriginFile || origins["chrome browser"] != PactOriginBroker {
+		t.Errorf("expected the local android app and the broker chrome browser pacts, got %v", origins)
+	}
+	var b bytes.Buffer
+	if err := v.Result().WriteJSONReport(&b); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(b.String(), `"pactOrigin": "file"`) || !strings.Contains(b.String(), `"pactOrigin": "broker"`) {
+		t.Errorf("expected the report to record the origins of the pacts, got %s", b.String())
+	}
+}
+
+func Test_Verifier_ThrowsError_BrokerPactWithoutBroker(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(BrokerPact("chrome browser", ""), nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{})
+
+	expected := fmt.Sprintf(errNoBrokerForPactMsg, "broker:chrome browser")
+	if err := v.Verify(); err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_ThrowsError_ConsumerVersionNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactBroker(server.URL, nil).
+		ConsumerVersion("4f2a9c1").
+		ServiceProvider("go api", &http.Client{}, u)
+
+	expErrMsg := fmt.Sprintf(errConsumerVersionNotFoundMsg, "chrome browser", "4f2a9c1")
+	if err := v.Verify(); err == nil {
+		t.Errorf("expected %s", expErrMsg)
+	} else if err.Error() != expErrMsg {
+		t.Errorf("expected %s, got %s", expErrMsg, err)
+	}
+}
+
+func Test_Verifier_ThrowsError_ConsumerVersionWithoutBroker(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		ConsumerVersion("4f2a9c1").
+		ServiceProvider("go api", &http.Client{}, &url.URL{})
+
+	if err := v.Verify(); !errors.Is(err, errNoPactBroker) {
+		t.Errorf("expected %s, got %v", errNoPactBroker, err)
+	}
+}
+
+func Test_Verifier_Validate_NoIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request to the provider, got %s", r.URL)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	fail := func() error {
+		t.Error("expected no actions to be executed")
+		return nil
+	}
+	v := NewPactFileVerifier(fail, fail, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", fail, fail).
+		ProviderState("there is no user with id {200}", fail, fail)
+
+	if issues := v.Validate(); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func Test_Verifier_Validate_ReportsMissingProviderStates(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{}).
+		ProviderState("there is a user with id {23}", nil, nil)
+
+	expErrMsg := fmt.Sprintf(errNotFoundProviderStateMsg, "there is no user with id {200}")
+	if issues := v.Validate(); len(issues) != 1 {
+		t.Errorf("expected 1 issue, got %v", issues)
+	} else if issues[0].Error() != expErrMsg {
+		t.Errorf("expected %s, got %s", expErrMsg, issues[0])
+	}
+}
+
+func Test_Verifier_Validate_ReportsConfigurationAndPactIssues(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		PactUri("badpath///", nil)
+
+	issues := v.Validate()
+	if len(issues) != 4 {
+		t.Fatalf("expected 4 issues, got %v", issues)
+	}
+	if !errors.Is(issues[0], errEmptyConsumer) || !errors.Is(issues[1], errEmptyProvider) || !errors.Is(issues[2], errNilProviderClient) {
+		t.Errorf("expected configuration issues, got %v", issues)
+	}
+	if !strings.Contains(issues[3].Error(), "badpath///") {
+		t.Errorf("expected unreachable pact issue, got %s", issues[3])
+	}
+}
+
+func Test_Verifier_Validate_ReportsUnsupportedSpecification(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("consumer").
+		PactUri("./io/pactWrongSpec.json", nil).
+		ServiceProvider("provider", &http.Client{}, &url.URL{})
+
+	if issues := v.Validate(); len(issues) != 1 {
+		t.Errorf("expected 1 issue, got %v", issues)
+	} else if !strings.Contains(issues[0].Error(), "Incompatible pact specification") {
+		t.Errorf("expected incompatible specification issue, got %s", issues[0])
+	}
+}
+
+func Test_Verifier_ProviderStateContext_ObservesCancellation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var setupErr error
+	teardownCalled := false
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderStateContext("there is a user with id {23}", func(ctx context.Context) error {
+			//simulate the verification being cancelled whilst seeding is in progress
+			cancel()
+			select {
+			case <-ctx.Done():
+				setupErr = ctx.Err()
+			case <-time.After(time.Second):
+			}
+			return setupErr
+		}, func(ctx context.Context) error {
+			teardownCalled = true
+			return nil
+		}).
+		ProviderState("there is no user with id {200}", nil, nil)
+
+	if err := v.VerifyContext(ctx); err != context.Canceled {
+		t.Errorf("expected %s, got %v", context.Canceled, err)
+	}
+	if setupErr != context.Canceled {
+		t.Errorf("expected setup to observe cancellation, got %v", setupErr)
+	}
+	if teardownCalled {
+		t.Error("expected teardown not to be called after a failed setup")
+	}
+}
+
+func Test_Verifier_VerifyContext_StopsWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request to the provider, got %s", r.URL)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil)
+
+	if err := v.VerifyContext(ctx); err != context.Canceled {
+		t.Errorf("expected %s, got %v", context.Canceled, err)
+	}
+}
+
+func Test_Verifier_WritesSummary(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	var buf bytes.Buffer
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(&buf)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	summary := buf.String()
+	if strings.Contains(summary, summaryRed) {
+		t.Error("expected no colors when the summary is not written to a terminal")
+	}
+	for _, s := range []string{
+		"Verified the pact between chrome browser and go api",
+		"2 interactions, 1 passed, 1 failed",
+		"- get request for user with id {23} given there is a user with id {23}",
+	} {
+		if !strings.Contains(summary, s) {
+			t.Errorf("expected summary to contain %q, got:\n%s", s, summary)
+		}
+	}
+
+	if r := v.Result(); r == nil || len(r.Interactions) != 2 || len(r.Failures()) != 1 {
+		t.Errorf("expected a result with 2 interactions and 1 failure, got %#v", r)
+	}
+}
+
+//throttleFirst responds with 503 and a Retry-After header to the first request
+func throttleFirst(h http.HandlerFunc) http.HandlerFunc {
+	throttled := false
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !throttled {
+			throttled = true
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func Test_Verifier_RetriesThrottledRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", throttleFirst(userHandlerWithValidData))
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/latest", throttleFirst(pactServer))
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	retry := &util.RetryPolicy{Attempts: 2, MaxRetryAfter: 10 * time.Millisecond}
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactBroker(server.URL, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		Retry(retry).
+		BrokerRetry(retry)
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Verifier_ServiceProviderFunc_ResolvesURLWhenVerifying(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	var server *httptest.Server
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProviderFunc("go api", &http.Client{}, func() (*url.URL, error) {
+			return url.Parse(server.URL)
+		}).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil)
+
+	//the provider starts after the verifier has been configured
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Verifier_ServiceProviderFunc_ThrowsError_ResolveFailed(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProviderFunc("go api", &http.Client{}, func() (*url.URL, error) {
+			return nil, errors.New("no healthy instances")
+		}).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil)
+
+	expected := "Failed to resolve the provider url: no healthy instances"
+	if err := v.Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func unixSocketServer(t *testing.T, h http.Handler) (*httptest.Server, *url.URL, func()) {
+	dir, err := ioutil.TempDir("", "pact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "provider.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(h)
+	server.Listener = l
+	server.Start()
+	return server, &url.URL{Scheme: "unix", Path: socket}, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func Test_Verifier_ServiceProvider_CanVerifyOverUnixSocket(t *testing.T) {
+	var paths []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		userHandlerWithValidData(w, r)
+	})
+	_, u, closeServer := unixSocketServer(t, mux)
+	defer closeServer()
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil)
+
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/user?id=23", "/user?id=200"}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected the requests %v, got %v", expected, paths)
+	}
+}
+
+func Test_Verifier_ServiceProviderFunc_CanResolveUnixSocket(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	_, u, closeServer := unixSocketServer(t, mux)
+	defer closeServer()
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProviderFunc("go api", &http.Client{}, func() (*url.URL, error) {
+			return u, nil
+		}).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil)
+
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Verifier_MaxLatency_FailsSlowResponses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "23" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		userHandlerWithValidData(w, r)
+	})
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		MaxLatency(25 * time.Millisecond).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions, got %d", len(r.Interactions))
+	}
+	slow, fast := r.Interactions[0], r.Interactions[1]
+	if slow.Latency < 50*time.Millisecond || len(slow.Differences) != 1 ||
+		!strings.Contains(slow.Differences.Error(), "longer than the maximum latency of 25ms") {
+		t.Errorf("expected the slow interaction to fail on latency, got %s after %s", slow.Differences.Error(), slow.Latency)
+	}
+	if fast.Latency <= 0 || !fast.Matched() {
+		t.Errorf("expected the fast interaction to pass with its latency recorded, got %s", fast.Latency)
+	}
+}
+
+func Test_Verifier_PactDir_ConsolidatesResult(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	var buf bytes.Buffer
+	v := NewPactFileVerifier(nil, nil, nil).
+		PactDir("./pact_examples/go_api").
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(&buf)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := v.Result()
+	if r.Consumer != "android app, chrome browser" {
+		t.Errorf("expected both consumers in file name order, got %s", r.Consumer)
+	}
+	expected := []struct{ consumer, pact string }{
+		{"android app", "pact_examples/go_api/android_app-go_api.json"},
+		{"chrome browser", "pact_examples/go_api/chrome_browser-go_api.json"},
+		{"chrome browser", "pact_examples/go_api/chrome_browser-go_api.json"},
+	}
+	if len(r.Interactions) != len(expected) {
+		t.Fatalf("expected %d interactions, got %d", len(expected), len(r.Interactions))
+	}
+	for n, e := range expected {
+		if i := r.Interactions[n]; i.Consumer != e.consumer || i.PactUri != e.pact {
+			t.Errorf("expected interaction %d from %s (%s), got %s (%s)", n, e.consumer, e.pact, i.Consumer, i.PactUri)
+		}
+	}
+	if !strings.Contains(buf.String(), "3 interactions, 3 passed, 0 failed") {
+		t.Errorf("expected a single consolidated summary, got:\n%s", buf.String())
+	}
+}
+
+func Test_Verifier_PactUri_VerifiesEveryPactOfDirectory(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	errSeed := errors.New("seeding failed")
+	setups := 0
+	var buf bytes.Buffer
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		PactUri("./pact_examples/go_api", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", func() error {
+			if setups++; setups == 1 {
+				return errSeed
+			}
+			return nil
+		}, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(&buf)
+	if err := v.Verify(); err != errSeed {
+		t.Fatalf("expected the error of the first pact once every pact is verified, got %v", err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 3 || len(r.Failures()) != 1 || r.Failures()[0].Consumer != "android app" {
+		t.Fatalf("expected the pact of the chrome browser to be verified after the failure of the android app")
+	}
+	if !strings.Contains(buf.String(), "[consumer: android app, pact: pact_examples/go_api/android_app-go_api.json]") {
+		t.Errorf("expected the failure to name its pact, got:\n%s", buf.String())
+	}
+
+	v = NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		PactUris("./pact_examples/go_api/android_app-go_api.json", "./pact_examples/go_api/chrome_browser-go_api.json").
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if r := v.Result(); r.Consumer != "android app, chrome browser" || len(r.Interactions) != 3 {
+		t.Errorf("expected the interactions of both pacts, got %s %d", r.Consumer, len(r.Interactions))
+	}
+}
+
+func Test_Verifier_AddPact_VerifiesEveryPact(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/go_api/chrome_browser-go_api.json", nil).
+		AddPact("./pact_examples/go_api/android_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	failures := v.Result().Failures()
+	if len(failures) != 2 || failures[0].Consumer != "chrome browser" || failures[1].Consumer != "android app" {
+		t.Errorf("expected a failure from each pact in the order they were added, got %d failures", len(failures))
+	}
+}
+
+func Test_Verifier_AddPact_VerifiesAgainstProviderURLOverride(t *testing.T) {
+	blue := http.NewServeMux()
+	blue.HandleFunc("/user", userHandlerWithMismatchedData)
+	blueServer := httptest.NewServer(blue)
+	defer blueServer.Close()
+	green := http.NewServeMux()
+	green.HandleFunc("/user", userHandlerWithValidData)
+	greenServer := httptest.NewServer(green)
+	defer greenServer.Close()
+
+	blueURL, _ := url.Parse(blueServer.URL)
+	greenURL, _ := url.Parse(greenServer.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/go_api/chrome_browser-go_api.json", &PactUriConfig{ProviderURL: greenURL}).
+		AddPact("./pact_examples/go_api/android_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, blueURL).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	for _, i := range v.Result().Interactions {
+		expected := blueServer.URL
+		if i.Consumer == "chrome browser" {
+			expected = greenServer.URL
+		}
+		if i.ProviderURL != expected {
+			t.Errorf("expected '%s' of %s to be verified against %s, got %s", i.Description, i.Consumer, expected, i.ProviderURL)
+		}
+	}
+	failures := v.Result().Failures()
+	if len(failures) != 1 || failures[0].Consumer != "android app" {
+		t.Errorf("expected only the pact verified against the default provider url to fail, got %d failures", len(failures))
+	}
+}
+
+func Test_Verifier_ThrowsError_NoPactsInDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		PactDir(dir).
+		ServiceProvider("go api", &http.Client{}, &url.URL{})
+	expected := fmt.Sprintf(errNoPactsInDirMsg, dir)
+	if err := v.Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_IgnoreResponseHeaders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [{
+			"description": "get health",
+			"request": {"method": "GET", "path": "/health"},
+			"response": {"status": 200, "headers": {"Date": "Wed, 21 Oct 2015 07:28:00 GMT"}}
+		}],
+		"metaData": {"pactSpecificationVersion": "1.1.0"}
+	}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "pact.json"), []byte(pact), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri(filepath.Join(dir, "pact.json"), nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+
+	//the date header is ignored by default
+	if err := v.Verify(); err != nil {
+		t.Errorf("expected the default ignored headers to exclude the date header, got %s", err)
+	}
+
+	if err := v.IgnoreResponseHeaders(nil).Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s when no headers are ignored, got %v", errVerficationFailed, err)
+	}
+
+	if err := v.IgnoreResponseHeaders([]string{"DATE"}).Verify(); err != nil {
+		t.Errorf("expected the date header to be ignored case-insensitively, got %s", err)
+	}
+}
+
+func Test_Verifier_ProviderState_SetupOrTeardownOnly(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var calls []string
+	record := func(call string) Action {
+		return func() error {
+			calls = append(calls, call)
+			return nil
+		}
+	}
+	tests := []struct {
+		setup, teardown Action
+		expected        []string
+	}{
+		{record("setup"), record("teardown"), []string{"setup", "teardown"}},
+		{record("setup"), nil, []string{"setup"}},
+		{nil, record("teardown"), []string{"teardown"}},
+		{nil, nil, nil},
+	}
+
+	for _, test := range tests {
+		calls = nil
+		v := NewPactFileVerifier(nil, nil, nil).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", test.setup, test.teardown).
+			ProviderState("there is no user with id {200}", nil, nil).
+			SummaryWriter(ioutil.Discard)
+		if err := v.Verify(); err != nil {
+			t.Error(err)
+		}
+		if fmt.Sprint(calls) != fmt.Sprint(test.expected) {
+			t.Errorf("expected %v actions, got %v", test.expected, calls)
+		}
+	}
+}
+
+func Test_Verifier_StateChangeURL(t *testing.T) {
+	var changes []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/provider-states", func(w http.ResponseWriter, r *http.Request) {
+		var change stateChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil || r.Method != "POST" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		changes = append(changes, change.Action+" "+change.State)
+	})
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	stateURL, _ := url.Parse(server.URL + "/provider-states")
+
+	for _, setupOnly := range []bool{false, true} {
+		changes = nil
+		v := NewPactFileVerifier(nil, nil, nil).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is no user with id {200}", nil, nil).
+			StateChangeURL(stateURL).
+			SetupOnly(setupOnly).
+			SummaryWriter(ioutil.Discard)
+		if err := v.Verify(); err != nil {
+			t.Error(err)
+		}
+
+		expected := []string{"setup there is a user with id {23}", "teardown there is a user with id {23}"}
+		if setupOnly {
+			expected = expected[:1]
+		}
+		if fmt.Sprint(changes) != fmt.Sprint(expected) {
+			t.Errorf("expected state changes %v with setup only %v, got %v", expected, setupOnly, changes)
+		}
+	}
+}
+
+func Test_Verifier_StateChangeURL_ThrowsError_WhenStateChangeFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		StateChangeURL(u).
+		SummaryWriter(ioutil.Discard)
+
+	expected := fmt.Sprintf(errStateChangeFailedMsg, "setup", "there is a user with id {23}", http.StatusInternalServerError)
+	if err := v.Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+	if issues := v.Validate(); len(issues) != 0 {
+		t.Errorf("expected no missing provider states with a state change url, got %v", issues)
+	}
+}
+
+func Test_Verifier_ForEnvironment_VerifiesDeployedConsumers(t *testing.T) {
+	var deployed string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/environments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"environments": [{"uuid": "3c4d", "name": "production"}]}}`)
+	})
+	mux.HandleFunc("/environments/3c4d/deployed-versions/currently-deployed", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"_embedded": {"deployedVersions": [%s]}}`, deployed)
+	})
+	mux.HandleFunc("/environments/3c4d/released-versions/currently-supported", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"releasedVersions": []}}`)
+	})
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/version/4f2a9c1", pactServer)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	newVerifier := func() Verifier {
+		return NewPactFileVerifier(nil, nil, nil).
+			PactBroker(server.URL, nil).
+			ForEnvironment("production").
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			SummaryWriter(ioutil.Discard)
+	}
+
+	//the provider and pacticipants which are not consumers of the provider are skipped
+	deployed = `{"_embedded": {"pacticipant": {"name": "chrome browser"}, "version": {"number": "4f2a9c1"}}},
+		{"_embedded": {"pacticipant": {"name": "go api"}, "version": {"number": "9d8e7f6"}}},
+		{"_embedded": {"pacticipant": {"name": "batch job"}, "version": {"number": "1.0.0"}}}`
+	v := newVerifier()
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if r := v.Result(); r.Consumer != "chrome browser" || len(r.Interactions) != 2 {
+		t.Errorf("expected the 2 interactions of the deployed chrome browser, got %d from %s", len(r.Interactions), r.Consumer)
+	}
+
+	deployed = `{"_embedded": {"pacticipant": {"name": "go api"}, "version": {"number": "9d8e7f6"}}}`
+	expected := fmt.Sprintf(errNoDeployedConsumersMsg, "go api", "production")
+	if err := newVerifier().Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_ThrowsError_EnvironmentWithoutBroker(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ForEnvironment("production").
+		ServiceProvider("go api", &http.Client{}, &url.URL{})
+	if err := v.Verify(); !errors.Is(err, errNoBrokerForEnvironment) {
+		t.Errorf("expected %s, got %v", errNoBrokerForEnvironment, err)
+	}
+}
+
+func Test_Verifier_RequestBodyEncoder_BuildsRequestBody(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [{
+			"description": "login",
+			"request": {"method": "POST", "path": "/login", "headers": {"Content-Type": "application/json"}, "body": {"username": "john"}},
+			"response": {"status": 200}
+		}],
+		"metaData": {"pactSpecificationVersion": "1.1.0"}
+	}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "pact.json"), []byte(pact), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" || r.FormValue("username") != "john" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	newVerifier := func() Verifier {
+		return NewPactFileVerifier(nil, nil, nil).
+			HonoursPactWith("chrome browser").
+			PactUri(filepath.Join(dir, "pact.json"), nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			SummaryWriter(ioutil.Discard)
+	}
+
+	//the recorded json body is rejected by the legacy provider
+	if err := newVerifier().Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	v := newVerifier().RequestBodyEncoder(func(i consumer.Interaction) (goio.Reader, string, error) {
+		form := url.Values{}
+		for k, val := range i.Request.GetBody().(map[string]interface{}) {
+			form.Set(k, fmt.Sprint(val))
+		}
+		return strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil
+	})
+	if err := v.Verify(); err != nil {
+		t.Errorf("expected the encoded form body to be accepted, got %s", err)
+	}
+
+	encodeErr := errors.New("cannot encode")
+	v = newVerifier().RequestBodyEncoder(func(i consumer.Interaction) (goio.Reader, string, error) {
+		return nil, "", encodeErr
+	})
+	if err := v.Verify(); err != encodeErr {
+		t.Errorf("expected %s, got %v", encodeErr, err)
+	}
+}
+
+func Test_Verifier_ReportsInteractionLocation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	var buf bytes.Buffer
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(&buf)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Errorf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	expected := "- get request for user with id {23} given there is a user with id {23} (interaction #1 at interactions[0], line 9)"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("expected summary to contain %q, got:\n%s", expected, buf.String())
+	}
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Println(args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(args...))
+}
+
+func (l *recordingLogger) Printf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func writeEmptyPact(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [],
+		"metaData": {"pactSpecificationVersion": "1.1.0"}
+	}`
+	path := filepath.Join(dir, "pact.json")
+	if err := ioutil.WriteFile(path, []byte(pact), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func Test_Verifier_ThrowsError_EmptyPact(t *testing.T) {
+	path, cleanup := writeEmptyPact(t)
+	defer cleanup()
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{}).
+		SummaryWriter(ioutil.Discard)
+	expected := fmt.Sprintf(errEmptyPactMsg, path)
+	if err := v.Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_AllowEmptyPact_WarnsAndPasses(t *testing.T) {
+	path, cleanup := writeEmptyPact(t)
+	defer cleanup()
+
+	l := &recordingLogger{}
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: l}).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{}).
+		AllowEmptyPact(true).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := fmt.Sprintf(warningMsg, fmt.Sprintf(warnEmptyPactMsg, path))
+	if len(l.lines) != 1 || l.lines[0] != expected {
+		t.Errorf("expected the warning %q, got %v", expected, l.lines)
+	}
+}
+
+func writeCommentedPact(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [{
+			"description": "get health",
+			"comments": {"testname": "TestHealthCheck", "text": ["polled by the load balancer"]},
+			"request": {"method": "GET", "path": "/health"},
+			"response": {"status": 200}
+		}],
+		"metaData": {"pactSpecificationVersion": "1.1.0"}
+	}`
+	path := filepath.Join(dir, "pact.json")
+	if err := ioutil.WriteFile(path, []byte(pact), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func Test_Verifier_OnInteraction_ReceivesComments(t *testing.T) {
+	path, cleanup := writeCommentedPact(t)
+	defer cleanup()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var results []*InteractionResult
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		OnInteraction(func(r *InteractionResult) { results = append(results, r) }).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected a callback for the interaction, got %d", len(results))
+	}
+	r := results[0]
+	if r.Consumer != "chrome browser" || r.PactUri != path || r.TestName != "TestHealthCheck" ||
+		len(r.Comments) != 1 || r.Comments[0] != "polled by the load balancer" {
+		t.Errorf("unexpected interaction result %#v", r)
+	}
+}
+
+func Test_Verifier_MismatchFormatter_RendersTheFailures(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var summary bytes.Buffer
+	err := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		MismatchFormatter(func(r *InteractionResult) string {
+			return fmt.Sprintf("BROKEN %s: %d differences", r.Description, len(r.Differences))
+		}).
+		SummaryWriter(&summary).
+		Verify()
+
+	if !errors.Is(err, errVerficationFailed) {
+		t.Fatalf("expected the verification to fail, got %v", err)
+	} else if !strings.Contains(err.Error(), "BROKEN") {
+		t.Errorf("expected the formatted failures in the error, got %s", err)
+	}
+	if !strings.Contains(summary.String(), "    - BROKEN") {
+		t.Errorf("expected the formatted failures in the summary, got %s", summary.String())
+	}
+}
+
+func Test_Verifier_VerifyT_RunsSubTestPerInteraction(t *testing.T) {
+	path, cleanup := writeCommentedPact(t)
+	defer cleanup()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	NewPactFileVerifier(nil, nil, nil).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard).
+		VerifyT(t)
+}
+
+func Test_Verifier_SubTestName_IncludesConsumerTest(t *testing.T) {
+	if n := subTestName(&InteractionResult{Description: "get health"}); n != "get health" {
+		t.Errorf("expected the description, got %q", n)
+	}
+	if n := subTestName(&InteractionResult{Description: "get health", TestName: "TestHealthCheck"}); n != "get health (TestHealthCheck)" {
+		t.Errorf("expected the description and consumer test, got %q", n)
+	}
+}
+
+func Test_Verifier_WarningsAsErrors_FailsOnUnusedStateHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	verifier := func(strict bool) Verifier {
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			ProviderState("there is an admin user", nil, nil).
+			WarningsAsErrors(strict).
+			SummaryWriter(ioutil.Discard)
+	}
+
+	if err := verifier(false).Verify(); err != nil {
+		t.Errorf("expected the warning not to fail the verification, got %v", err)
+	}
+
+	expected := fmt.Sprintf(errWarningsMsg, 1, "  - "+fmt.Sprintf(warnUnusedStateMsg, "there is an admin user"))
+	if err := verifier(true).Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_WarningsAsErrors_ListsEveryWarning(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	path, cleanup := writeEmptyPact(t)
+	defer cleanup()
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ExpectedFailures([]string{"get request for user with id {23}"}).
+		AllowEmptyPact(true).
+		WarningsAsErrors(true).
+		SummaryWriter(ioutil.Discard)
+
+	err := v.Verify()
+	if err == nil || !strings.HasPrefix(err.Error(), "The verification raised 2 warnings") ||
+		!strings.Contains(err.Error(), fmt.Sprintf(warnEmptyPactMsg, path)) ||
+		!strings.Contains(err.Error(), fmt.Sprintf(warnExpectedFailureMsg, "get request for user with id {23}")) {
+		t.Errorf("expected the empty pact and expected failure warnings, got %v", err)
+	}
+}
+
+func Test_Verifier_ResponseSchema_ReportsViolations(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	schema := []byte(`{
+		"type": "object",
+		"required": ["id", "email"],
+		"properties": {"id": {"type": "integer", "minimum": 100}}
+	}`)
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ResponseSchema("get request for user with id {23}", schema).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	failures := v.Result().Failures()
+	if len(failures) != 1 || failures[0].Description != "get request for user with id {23}" {
+		t.Fatalf("expected only the interaction with a schema to fail, got %d failures", len(failures))
+	}
+	expected := []string{
+		`mismatch at ["body"]: schema violation, required property email is missing`,
+		`mismatch at ["body"]["id"]: schema violation, 23 is less than the minimum of 100`,
+	}
+	if d := failures[0].Differences; len(d) != 2 || d[0].String() != expected[0] || d[1].String() != expected[1] {
+		t.Errorf("expected the schema violations %v, got %v", expected, d)
+	}
+}
+
+func Test_Verifier_ResponseSchema_ThrowsError_InvalidSchema(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{}).
+		ResponseSchema("get request for user with id {23}", []byte(`[]`))
+
+	expected := "The response schema of interaction 'get request for user with id {23}' is invalid: The schema must be a json object or boolean."
+	if err := v.Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func writeNegotiationPact(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [{
+			"description": "get user as json",
+			"request": {"method": "GET", "path": "/user", "query": "id=23", "headers": {"Accept": "application/json"}},
+			"response": {"status": 200, "body": {"id": 23, "firstName": "John", "lastName": "Doe"}}
+		}, {
+			"description": "get user as xml",
+			"request": {"method": "GET", "path": "/user", "query": "id=23", "headers": {"Accept": "application/xml, text/xml;q=0.9"}},
+			"response": {"status": 200}
+		}],
+		"metaData": {"pactSpecificationVersion": "1.1.0"}
+	}`
+	path := filepath.Join(dir, "pact.json")
+	if err := ioutil.WriteFile(path, []byte(pact), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func negotiatingUserHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get("Accept"), "application/xml") {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<user><id>23</id><firstName>John</firstName><lastName>Doe</lastName></user>`)
+		return
+	}
+	userHandlerWithValidData(w, r)
+}
+
+func Test_Verifier_NegotiatesContentTypeWithAcceptHeader(t *testing.T) {
+	path, cleanup := writeNegotiationPact(t)
+	defer cleanup()
+	server := httptest.NewServer(http.HandlerFunc(negotiatingUserHandler))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Verifier_FailsWhenContentTypeIsNotAccepted(t *testing.T) {
+	path, cleanup := writeNegotiationPact(t)
+	defer cleanup()
+	//the provider ignores the accept header
+	server := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	failures := v.Result().Failures()
+	if len(failures) != 1 || failures[0].Description != "get user as xml" {
+		t.Fatalf("expected only the xml interaction to fail, got %d failures", len(failures))
+	}
+	expected := `mismatch at ["header"]["content-type"]: content type application/json is not acceptable for the request accept header application/xml, text/xml;q=0.9`
+	if d := failures[0].Differences; len(d) != 1 || d[0].String() != expected {
+		t.Errorf("expected %q, got %v", expected, d)
+	}
+}
+
+func authenticatedProvider(logins *int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		*logins++
+		fmt.Fprint(w, "token")
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		userHandlerWithValidData(w, r)
+	})
+	return httptest.NewServer(mux)
+}
+
+func loginHook(server *httptest.Server) ProviderAuth {
+	return func(c *http.Client) (http.Header, error) {
+		resp, err := c.Post(server.URL+"/login", "text/plain", nil)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		token, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return http.Header{"Authorization": {"Bearer " + string(token)}}, nil
+	}
+}
+
+func Test_Verifier_ProviderAuthHook_CachesHeadersForTheRun(t *testing.T) {
+	logins := 0
+	server := authenticatedProvider(&logins)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ProviderAuthHook(loginHook(server)).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 1 {
+		t.Errorf("expected a single login, got %d", logins)
+	}
+
+	//every run logs in again
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 {
+		t.Errorf("expected a login per run, got %d", logins)
+	}
+}
+
+func Test_Verifier_ProviderAuthTTL_RefreshesExpiredHeaders(t *testing.T) {
+	logins := 0
+	server := authenticatedProvider(&logins)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ProviderAuthHook(loginHook(server)).
+		ProviderAuthTTL(time.Nanosecond).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 {
+		t.Errorf("expected a login per interaction, got %d", logins)
+	}
+}
+
+func Test_Verifier_ProviderBasicAuth_AuthorizesTheRequests(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "pact" || password != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		userHandlerWithValidData(w, r)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	verifier := func() Verifier {
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			SummaryWriter(ioutil.Discard)
+	}
+	if err := verifier().Verify(); err != errVerficationFailed {
+		t.Errorf("expected the unauthorized requests to fail, got %v", err)
+	}
+	if err := verifier().ProviderBasicAuth("pact", "s3cret").Verify(); err != nil {
+		t.Errorf("expected the authorized requests to pass, got %s", err)
+	}
+}
+
+func Test_Verifier_ProviderBasicAuth_KeepsRecordedAuthorization(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "admin app"},
+		"provider": {"name": "go api"},
+		"interactions": [
+			{
+				"description": "get the user as an admin",
+				"request": {"method": "GET", "path": "/user", "headers": {"Authorization": "Bearer admin"}},
+				"response": {"status": 200}
+			},
+			{
+				"description": "get the user",
+				"request": {"method": "GET", "path": "/user"},
+				"response": {"status": 200}
+			}
+		],
+		"metadata": {"pactSpecification": {"version": "2.0.0"}}
+	}`
+	preview := func(description string) string {
+		req, err := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			PactReader(strings.NewReader(pact)).
+			ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+			ProviderBasicAuth("pact", "s3cret").
+			PreviewRequest(description)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req.Header.Get("Authorization")
+	}
+	if auth := preview("get the user as an admin"); auth != "Bearer admin" {
+		t.Errorf("expected the recorded authorization, got %s", auth)
+	}
+	if auth := preview("get the user"); auth != "Basic cGFjdDpzM2NyZXQ=" {
+		t.Errorf("expected the basic authorization, got %s", auth)
+	}
+}
+
+func Test_Verifier_PreservesThePrecisionOfLargeIntegers(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "billing app"},
+		"provider": {"name": "go api"},
+		"interactions": [
+			{
+				"description": "get the account",
+				"request": {"method": "POST", "path": "/account", "headers": {"Content-Type": "application/json"}, "body": {"id": 9007199254740993}},
+				"response": {"status": 200, "headers": {"Content-Type": "application/json"}, "body": {"id": 9007199254740993}}
+			}
+		],
+		"metadata": {"pactSpecification": {"version": "2.0.0"}}
+	}`
+	verify := func(id string) error {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			if string(b) != `{"id":9007199254740993}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id": %s}`, id)
+		}))
+		defer server.Close()
+		u, _ := url.Parse(server.URL)
+
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			PactReader(strings.NewReader(pact)).
+			ServiceProvider("go api", &http.Client{}, u).
+			SummaryWriter(ioutil.Discard).
+			Verify()
+	}
+	if err := verify("9007199254740993"); err != nil {
+		t.Errorf("expected the same id to match, got %s", err)
+	}
+	if err := verify("9007199254740992"); err != errVerficationFailed {
+		t.Errorf("expected the id differing beyond the float64 precision to mismatch, got %v", err)
+	}
+}
+
+func Test_Verifier_ProviderAuthHook_ThrowsError(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		ProviderAuthHook(func(c *http.Client) (http.Header, error) {
+			return nil, errors.New("invalid credentials")
+		})
+
+	expected := "The provider auth hook failed: invalid credentials"
+	if err := v.Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_FiltersInteractionsByDescriptionAndState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [{
+			"description": "get user",
+			"provider_state": "there is a user with id {23}",
+			"request": {"method": "GET", "path": "/user", "query": "id=23"},
+			"response": {"status": 200}
+		}, {
+			"description": "get user",
+			"provider_state": "there is no user with id {23}",
+			"request": {"method": "GET", "path": "/user", "query": "id=23"},
+			"response": {"status": 404}
+		}, {
+			"description": "get other user",
+			"provider_state": "there is a user with id {23}",
+			"request": {"method": "GET", "path": "/user", "query": "id=24"},
+			"response": {"status": 200}
+		}],
+		"metaData": {"pactSpecificationVersion": "1.1.0"}
+	}`
+	path := filepath.Join(dir, "pact.json")
+	if err := ioutil.WriteFile(path, []byte(pact), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {23}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+
+	if err := v.VerifyState("get user", "there is a user with id {23}"); err != nil {
+		t.Fatal(err)
+	}
+	if i := v.Result().Interactions; len(i) != 1 || i[0].Description != "get user" || i[0].State != "there is a user with id {23}" {
+		t.Errorf("expected only the interaction matching the description and state, got %d interactions", len(i))
+	}
+
+	//the description matches, the state does not
+	if err := v.VerifyState("get other user", "there is no user with id {23}"); err != errNoFilteredInteractionsFound {
+		t.Errorf("expected %s, got %v", errNoFilteredInteractionsFound, err)
+	}
+}
+
+func Test_Verifier_SkipsPendingAndSkipFlaggedInteractions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [{
+			"description": "get health",
+			"request": {"method": "GET", "path": "/health"},
+			"response": {"status": 200}
+		}, {
+			"description": "get metrics",
+			"metadata": {"pending": true},
+			"request": {"method": "GET", "path": "/metrics"},
+			"response": {"status": 200}
+		}, {
+			"description": "get version",
+			"provider_state": "there is a release",
+			"metadata": {"skip": true},
+			"request": {"method": "GET", "path": "/version"},
+			"response": {"status": 200}
+		}],
+		"metaData": {"pactSpecificationVersion": "1.1.0"}
+	}`
+	path := filepath.Join(dir, "pact.json")
+	if err := ioutil.WriteFile(path, []byte(pact), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var summary bytes.Buffer
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(path, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(&summary)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requested) != 1 || requested[0] != "/health" {
+		t.Errorf("expected only the request of the interaction which is not skipped, got %v", requested)
+	}
+	skipped := v.Result().Skipped()
+	if len(skipped) != 2 || skipped[0].Description != "get metrics" || skipped[1].Description != "get version" {
+		t.Errorf("expected the pending and skip flagged interactions to be skipped, got %d", len(skipped))
+	}
+	if len(v.Result().Failures()) != 0 {
+		t.Error("expected skipped interactions not to fail")
+	}
+	if !strings.Contains(summary.String(), "3 interactions, 1 passed, 0 failed, 2 skipped") {
+		t.Errorf("expected the skipped interactions in the summary, got %q", summary.String())
+	}
+
+	var report bytes.Buffer
+	if err := v.Result().WriteJSONReport(&report); err != nil {
+		t.Fatal(err)
+	}
+	var r jsonReport
+	if err := json.Unmarshal(report.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Passed != 1 || r.Skipped != 2 || r.Interactions[1].Status != statusSkipped {
+		t.Errorf("expected the skipped interactions in the report, got %#v", r)
+	}
+}
+
+func Test_Verifier_ProviderStateWithValues_PassesSetupValuesToTeardown(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	torndown := make(map[string]map[string]interface{})
+	teardown := func(state string) StateTeardown {
+		return func(ctx context.Context, values map[string]interface{}) error {
+			torndown[state] = values
+			return nil
+		}
+	}
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderStateWithValues("there is a user with id {23}", func(ctx context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"userId": 23}, nil
+		}, teardown("user")).
+		ProviderStateWithValues("there is no user with id {200}", func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, nil
+		}, teardown("no user")).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if values := torndown["user"]; len(values) != 1 || values["userId"] != 23 {
+		t.Errorf("expected the teardown to receive the setup values, got %v", values)
+	}
+	if values, ok := torndown["no user"]; !ok || values == nil || len(values) != 0 {
+		t.Errorf("expected the teardown to receive empty values, got %v", values)
+	}
+}
+
+func Test_Verifier_ServiceProviderHAR_ReplaysRecordedResponses(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProviderHAR("go api", "./pact_examples/har/go_api.har").
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Verifier_ServiceProviderHAR_ThrowsError_NoRecordedEntry(t *testing.T) {
+	path, cleanup := writeCommentedPact(t)
+	defer cleanup()
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(path, nil).
+		ServiceProviderHAR("go api", "./pact_examples/har/go_api.har").
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err == nil || !strings.Contains(err.Error(), fmt.Sprintf(errHARNoEntryMsg, "GET", "/health")) {
+		t.Errorf("expected the missing entry error, got %v", err)
+	}
+}
+
+func Test_Verifier_ServiceProviderHAR_ThrowsError_InvalidFile(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProviderHAR("go api", "./pact_examples/har/missing.har")
+
+	var cerr *ConfigError
+	if err := v.Build(); !errors.As(err, &cerr) || cerr.Field != FieldProviderHAR {
+		t.Errorf("expected the HAR file error, got %v", err)
+	}
+}
+
+//previousPactServer serves the chrome browser pact without its last interaction
+func previousPactServer(w http.ResponseWriter, r *http.Request) {
+	b, _ := ioutil.ReadFile("./pact_examples/chrome_browser-go_api.json")
+	var pact map[string]interface{}
+	if err := json.Unmarshal(b, &pact); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	interactions := pact["interactions"].([]interface{})
+	pact["interactions"] = interactions[:len(interactions)-1]
+	json.NewEncoder(w).Encode(pact)
+}
+
+func Test_Verifier_VerifyChangesSince_VerifiesChangedInteractions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/latest", pactServer)
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/version/4f2a9c1", previousPactServer)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var summary bytes.Buffer
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(&summary)
+	if err := v.VerifyChangesSince(server.URL, "4f2a9c1"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 1 || r.Interactions[0].Description != "get request for user with id {200}" {
+		t.Errorf("expected only the changed interaction to be verified, got %v", r.Interactions)
+	}
+	note := fmt.Sprintf(noteChangesSinceMsg, 1, 2, "chrome browser", "4f2a9c1")
+	if len(r.Notes) != 1 || r.Notes[0] != note {
+		t.Errorf("expected the note %q, got %v", note, r.Notes)
+	}
+	if !strings.Contains(summary.String(), note) {
+		t.Errorf("expected the summary to contain the note, got %s", summary.String())
+	}
+}
+
+func Test_Verifier_VerifyChangesSince_VerifiesAllWithoutPreviousVersion(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/latest", pactServer)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.VerifyChangesSince(server.URL, "4f2a9c1"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 2 {
+		t.Errorf("expected all the interactions to be verified, got %d", len(r.Interactions))
+	}
+	note := fmt.Sprintf(noteNoPreviousVersionMsg, "chrome browser", "4f2a9c1")
+	if len(r.Notes) != 1 || r.Notes[0] != note {
+		t.Errorf("expected the note %q, got %v", note, r.Notes)
+	}
+}
+
+func Test_Verifier_SendsUserAgent(t *testing.T) {
+	var agents []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		userHandlerWithValidData(w, r)
+	})
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/latest", func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		pactServer(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactBroker(server.URL, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range agents {
+		if a != util.DefaultUserAgent {
+			t.Errorf("expected the default user agent %s, got %s", util.DefaultUserAgent, a)
+		}
+	}
+
+	agents = nil
+	if err := v.UserAgent("ci-verifier/2").Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if len(agents) != 3 {
+		t.Fatalf("expected the broker and provider requests, got %v", agents)
+	}
+	for _, a := range agents {
+		if a != "ci-verifier/2" {
+			t.Errorf("expected the configured user agent, got %s", a)
+		}
+	}
+}
+
+func Test_Verifier_KeepsUserAgentOfInteraction(t *testing.T) {
+	var agent string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent = r.Header.Get("User-Agent")
+	}))
+	defer s.Close()
+
+	pact := `{"consumer": {"name": "mobile"}, "provider": {"name": "go api"}, "interactions": [{"description": "ping",
+		"request": {"method": "GET", "path": "/ping", "headers": {"User-Agent": "mobile/5"}}, "response": {"status": 200}}],
+		"metadata": {"pactSpecificationVersion": "1.1.0"}}`
+	f, err := ioutil.TempFile("", "pact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(pact)
+	f.Close()
+
+	u, _ := url.Parse(s.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(f.Name(), nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		UserAgent("ci-verifier/2").
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if agent != "mobile/5" {
+		t.Errorf("expected the user agent of the interaction, got %s", agent)
+	}
+}
+
+func Test_Verifier_VerifiesStructuredQuery(t *testing.T) {
+	var queries []url.Values
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+	}))
+	defer s.Close()
+
+	u, _ := url.Parse(s.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/query/search_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := url.Values{"name": {"John", "Jane"}, "sort": {"surname"}}
+	if len(queries) != 2 {
+		t.Fatalf("expected a request per interaction, got %d", len(queries))
+	}
+	for _, q := range queries {
+		if !reflect.DeepEqual(q, expected) {
+			t.Errorf("expected the query %v, got %v", expected, q)
+		}
+	}
+}
+
+func Test_Verifier_RequestDelay_SpacesProviderRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	//the requests are timed as they leave the client, the exact spacing is covered by the limiter tests
+	var mu sync.Mutex
+	var sent []time.Time
+	sendTimes := func(next RequestHandler) RequestHandler {
+		return func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			sent = append(sent, time.Now())
+			mu.Unlock()
+			return next(req)
+		}
+	}
+
+	delay := 50 * time.Millisecond
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		RequestDelay(delay).
+		Use(sendTimes).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if min := delay - 10*time.Millisecond; len(sent) != 2 || sent[1].Sub(sent[0]) < min {
+		t.Errorf("expected the requests to be at least %v apart, got %v", min, sent)
+	}
+}
+
+func Test_Verifier_OverrideMatchingRules_RelaxesInteraction(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	typ := &matchers.RuleSet{Matchers: []*matchers.Rule{{Match: "type"}}}
+	logger := &recordingLogger{}
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: logger}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected the pact to fail without the override, got %v", err)
+	}
+
+	v.OverrideMatchingRules("get request for user with id {23}", matchers.MatchingRules{
+		matchers.BodyCategory: matchers.Rules{"$.firstName": typ, "$.lastName": typ},
+	})
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	warning := fmt.Sprintf(warnRuleOverrideMsg, "get request for user with id {23}")
+	if r := v.Result(); len(r.Notes) != 1 || r.Notes[0] != warning {
+		t.Errorf("expected the override to be noted in the result, got %v", r.Notes)
+	}
+	if !strings.Contains(strings.Join(logger.lines, "\n"), warning) {
+		t.Errorf("expected the override warning to be logged, got %v", logger.lines)
+	}
+}
+
+func Test_Verifier_OverrideMatchingRules_ThrowsError_InvalidRules(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		OverrideMatchingRules("get request for user with id {23}", matchers.MatchingRules{
+			matchers.BodyCategory: matchers.Rules{"$.firstName": &matchers.RuleSet{}},
+		})
+
+	var cerr *ConfigError
+	if err := v.Build(); !errors.As(err, &cerr) || cerr.Field != FieldMatchingRules {
+		t.Errorf("expected the invalid override error, got %v", err)
+	}
+}
+
+func Test_Verifier_VerifiesPactsOfDifferentSpecVersions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/spec/android_app-go_api-v2.json", nil).
+		AddPact("./pact_examples/spec/chrome_browser-go_api-v3.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 2 || r.Interactions[0].PactSpecVersion != "2.0.0" || r.Interactions[1].PactSpecVersion != "3.0.0" {
+		t.Errorf("expected the spec version of each pact in the result, got %v", r.Interactions)
+	}
+}
+
+func Test_Verifier_PreviewRequest_BuildsRequestWithoutSending(t *testing.T) {
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderAuthHook(func(c *http.Client) (http.Header, error) {
+			return http.Header{"Authorization": {"Bearer token"}}, nil
+		})
+
+	req, err := v.PreviewRequest("get request for user with id {23}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "GET" || req.URL.String() != server.URL+"/user?id=23" {
+		t.Errorf("expected the request to the provider, got %s %s", req.Method, req.URL)
+	}
+	if req.Header.Get("Authorization") != "Bearer token" || req.Header.Get("User-Agent") != util.DefaultUserAgent {
+		t.Errorf("expected the auth and user agent headers, got %v", req.Header)
+	}
+	if sent != 0 {
+		t.Errorf("expected no request to be sent, got %d", sent)
+	}
+}
+
+func Test_Verifier_PreviewRequest_ThrowsError_NoInteraction(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"})
+
+	expected := fmt.Sprintf(errNoInteractionMsg, "delete user")
+	if _, err := v.PreviewRequest("delete user"); err == nil || err.Error() != expected {
+		t.Errorf("expected %s, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_VerifyScenario_VerifiesScenarioInOrder(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/basket" {
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/scenario/shop-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.VerifyScenario("checkout"); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || paths[0] != "/basket" || paths[1] != "/basket/payment" {
+		t.Errorf("expected the checkout interactions in recorded order, got %v", paths)
+	}
+
+	paths = nil
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 3 {
+		t.Errorf("expected every interaction to be verified after the scenario, got %v", paths)
+	}
+}
+
+func Test_Verifier_VerifyScenario_VerifiesInOrderWhateverTheConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/basket" {
+			//the first step is slow, a concurrent payment would be received before it
+			time.Sleep(30 * time.Millisecond)
+			w.WriteHeader(http.StatusCreated)
+		}
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/scenario/shop-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		Concurrency(4).
+		SummaryWriter(ioutil.Discard)
+	if err := v.VerifyScenario("checkout"); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 2 || paths[0] != "/basket" || paths[1] != "/basket/payment" {
+		t.Errorf("expected the checkout interactions in recorded order, got %v", paths)
+	}
+}
+
+func Test_Verifier_VerifyScenario_ThrowsError_UnknownScenario(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/scenario/shop-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		SummaryWriter(ioutil.Discard)
+	if err := v.VerifyScenario("returns"); err != errNoFilteredInteractionsFound {
+		t.Errorf("expected %s, got %v", errNoFilteredInteractionsFound, err)
+	}
+}
+
+func Test_Verifier_ExplainMatches_RecordsMatchingRules(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/spec/chrome_browser-go_api-v3.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if matches := v.Result().Interactions[0].RuleMatches; len(matches) != 0 {
+		t.Errorf("expected no rule matches without ExplainMatches, got %v", matches)
+	}
+
+	if err := v.ExplainMatches(true).Verify(); err != nil {
+		t.Fatal(err)
+	}
+	var matches []string
+	for _, m := range v.Result().Interactions[0].RuleMatches {
+		matches = append(matches, m.String())
+	}
+	sort.Strings(matches)
+	expected := []string{"$.firstName matched by type", "$.lastName matched by regex ^[A-Z][a-z]+$"}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("expected the rule matches %v, got %v", expected, matches)
+	}
+}
+
+func Test_Verifier_SignRequests_SignsFinalBody(t *testing.T) {
+	secret := []byte("s3cret")
+	sign := func(timestamp string, body []byte) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(timestamp))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		ts := r.Header.Get("X-Timestamp")
+		if ts == "" || !hmac.Equal([]byte(r.Header.Get("X-Signature")), []byte(sign(ts, body))) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	pact := `{"consumer": {"name": "shop"}, "provider": {"name": "orders api"}, "interactions": [{"description": "create an order",
+		"request": {"method": "POST", "path": "/orders", "headers": {"Content-Type": "application/json"}, "body": {"item": "book"}},
+		"response": {"status": 201}}], "metadata": {"pactSpecificationVersion": "1.1.0"}}`
+	f, err := ioutil.TempFile("", "pact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(pact)
+	f.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact(f.Name(), nil).
+		ServiceProvider("orders api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected the unsigned request to be rejected, got %v", err)
+	}
+
+	var signed []byte
+	v.SignRequests(func(req *http.Request, body []byte) error {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Timestamp", ts)
+		req.Header.Set("X-Signature", sign(ts, body))
+		signed = body
+		return nil
+	})
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if string(signed) != `{"item":"book"}` {
+		t.Errorf("expected the signer to receive the serialized body, got %s", signed)
+	}
+}
+
+func Test_Verifier_SignRequests_ThrowsError_SignerFails(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SignRequests(func(req *http.Request, body []byte) error {
+			return errors.New("no key")
+		}).
+		SummaryWriter(ioutil.Discard)
+
+	expected := fmt.Sprintf(errRequestSignerMsg, "no key")
+	if err := v.Verify(); err == nil || err.Error() != expected {
+		t.Errorf("expected %s, got %v", expected, err)
+	}
+}
+
+func Test_Verifier_PanicAsFailure_FailsPanickingInteraction(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", func() error {
+			var users map[int]string
+			users[23] = "John"
+			return nil
+		}, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 2 {
+		t.Fatalf("expected every interaction to be verified, got %d", len(r.Interactions))
+	}
+	panicked, other := r.Interactions[0], r.Interactions[1]
+	if !panicked.Failed() || len(panicked.Differences) != 1 || !strings.Contains(panicked.Differences[0].String(), "assignment to entry in nil map") {
+		t.Errorf("expected the panic to fail the interaction, got %v", panicked.Differences)
+	} else if !strings.Contains(panicked.Differences[0].String(), "goroutine") {
+		t.Errorf("expected the stack trace of the panic, got %s", panicked.Differences[0])
+	}
+	if other.Failed() {
+		t.Errorf("expected the other interaction to pass, got %v", other.Differences)
+	}
+}
+
+func Test_Verifier_PanicAsFailure_FailsPanickingTeardown(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, func() error {
+			panic("teardown failed")
+		}).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected %s, got %v", errVerficationFailed, err)
+	}
+	if r := v.Result(); len(r.Interactions) != 2 || !r.Interactions[0].Failed() || r.Interactions[1].Failed() {
+		t.Errorf("expected only the interaction whose teardown panicked to fail, got %v", r.Interactions)
+	}
+}
+
+func Test_Verifier_PanicAsFailure_PropagatesPanicWhenDisabled(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", func() error {
+			panic("setup failed")
+		}, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		PanicAsFailure(false).
+		SummaryWriter(ioutil.Discard)
+
+	defer func() {
+		if p := recover(); p != "setup failed" {
+			t.Errorf("expected the setup panic, got %v", p)
+		}
+	}()
+	v.Verify()
+	t.Error("expected the verification to panic")
+}
+
+func Test_Verifier_PanicAsFailure_PropagatesPanicOfConcurrentWorker(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", func() error {
+			panic("setup failed")
+		}, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		PanicAsFailure(false).
+		Concurrency(2).
+		SummaryWriter(ioutil.Discard)
+
+	defer func() {
+		if p := recover(); p != "setup failed" {
+			t.Errorf("expected the setup panic on the calling goroutine, got %v", p)
+		}
+	}()
+	v.Verify()
+	t.Error("expected the verification to panic")
+}
+
+func Test_Verifier_VerifyB_RepeatsVerificationWithFreshResult(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var summary bytes.Buffer
+	verified := 0
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		OnInteraction(func(r *InteractionResult) { verified++ }).
+		SummaryWriter(&summary)
+
+	r := testing.Benchmark(v.VerifyB)
+	if r.N == 0 {
+		t.Fatal("expected the benchmark to verify the pact")
+	}
+	if len(v.Result().Interactions) != 2 {
+		t.Errorf("expected the result of the last iteration only, got %d interactions", len(v.Result().Interactions))
+	}
+	if verified < 2*r.N {
+		t.Errorf("expected every iteration to verify both interactions, got %d verified in %d iterations", verified, r.N)
+	}
+	if summary.Len() != 0 {
+		t.Errorf("expected no summary to be written, got %s", summary.String())
+	}
+}
+
+func Test_Verifier_VerifyB_FailsWhenVerificationFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+
+	if r := testing.Benchmark(v.VerifyB); r.N != 0 {
+		t.Errorf("expected the benchmark to fail, got %d iterations", r.N)
+	}
+}
+
+func Benchmark_Verifier_Verify(b *testing.B) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		VerifyB(b)
+}
+
+func Test_Verifier_SendsAndMatchesBase64BinaryBodies(t *testing.T) {
+	png := []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x01, 0x02, 0x03}
+	pdf := []byte("%PDF-\x00\xff\xfe")
+	var received []byte
+	respond := pdf
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(respond)
+	}))
+	defer s.Close()
+
+	u, _ := url.Parse(s.URL)
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		AddPact("./pact_examples/binary/scanner_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(received, png) {
+		t.Errorf("expected the provider to receive the raw bytes %v, got %v", png, received)
+	}
+
+	respond = []byte("%PDF-\x00")
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Errorf("expected a different binary response to fail, got %v", err)
+	}
+}
+
+func Test_Verifier_AfterInteraction_RunsAfterStateTeardown(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var calls []string
+	record := func(call string) Action {
+		return func() error {
+			calls = append(calls, call)
+			return nil
+		}
+	}
+	v := NewPactFileVerifier(nil, record("default teardown"), nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", record("setup"), record("teardown")).
+		ProviderState("there is no user with id {200}", nil, nil).
+		AfterInteraction(func(states []string) error {
+			calls = append(calls, fmt.Sprintf("after %v", states))
+			return nil
+		}).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"setup", "teardown", "after [there is a user with id {23}]", "default teardown",
+		"after [there is no user with id {200}]", "default teardown"}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func Test_Verifier_AfterInteraction_ErrorFailsVerification(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	cleanupErr := errors.New("cleanup failed")
+	err := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		AfterInteraction(func(states []string) error { return cleanupErr }).
+		SummaryWriter(ioutil.Discard).
+		Verify()
+	if err != cleanupErr {
+		t.Errorf("expected the hook error, got %v", err)
+	}
+}
+
+func Test_Verifier_PactReader_VerifiesPactReadFromStream(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	f, err := os.Open("./pact_examples/chrome_browser-go_api.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		PactReader(f).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	for n := 0; n < 2; n++ {
+		if err := v.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if r := v.Result(); len(r.Interactions) != 2 || r.Interactions[0].Consumer != "chrome browser" {
+			t.Errorf("expected the interactions of the piped pact, got %v", r.Interactions)
+		}
+	}
+}
+
+func Test_Verifier_PactReader_FailsOnEmptyStream(t *testing.T) {
+	err := NewPactFileVerifier(nil, nil, nil).
+		PactReader(strings.NewReader(" \n")).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		SummaryWriter(ioutil.Discard).
+		Verify()
+	if err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("expected the empty stream error, got %v", err)
+	}
+}
+
+func Test_Verifier_PactReader_CannotBeMixedWithOtherPacts(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		PactReader(strings.NewReader("{}")).
+		PactDir("./pact_examples/go_api").
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"})
+
+	var cerr *ConfigError
+	if err := v.Build(); !errors.As(err, &cerr) || cerr.Field != FieldPactReader {
+		t.Errorf("expected the mixed pacts error, got %v", err)
+	}
+}
+
+func Test_Verifier_WriteVerificationResults_WritesBrokerResults(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	dir, err := ioutil.TempDir("", "pact-results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "results.json")
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		WriteVerificationResults(path, "1.2.3", "https://ci.example.com/builds/42").
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results brokerResults
+	if err := json.Unmarshal(b, &results); err != nil {
+		t.Fatal(err)
+	}
+	if !results.Success || results.ProviderVersion != "1.2.3" || results.BuildURL != "https://ci.example.com/builds/42" || len(results.TestResults) != 2 {
+		t.Errorf("unexpected verification results %s", b)
+	}
+}
+
+func Test_Verifier_WriteVerificationResults_RequiresProviderVersion(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		WriteVerificationResults("results.json", "", "")
+
+	var cerr *ConfigError
+	if err := v.Build(); !errors.As(err, &cerr) || cerr.Field != FieldProviderVersion {
+		t.Errorf("expected the missing provider version error, got %v", err)
+	}
+}
+
+func Test_Verifier_ProviderVersionTags_CreatesTheTaggedVersionOnItsBranch(t *testing.T) {
+	var created []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/latest", pactServer)
+	mux.HandleFunc("/pacticipants/", func(w http.ResponseWriter, r *http.Request) {
+		created = append(created, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	dir, err := ioutil.TempDir("", "pact-results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "results.json")
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactBroker(server.URL, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		WriteVerificationResults(path, "1.2.3", "").
+		ProviderBranch("feat/login").
+		ProviderVersionTags([]string{"ci", "feat/login"}).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /pacticipants/go api/branches/feat/login/versions/1.2.3",
+		"PUT /pacticipants/go api/versions/1.2.3/tags/ci",
+		"PUT /pacticipants/go api/versions/1.2.3/tags/feat/login",
+	}
+	if !reflect.DeepEqual(created, expected) {
+		t.Errorf("expected the version to be created on its branch with its tags, got %v", created)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the results to be written after the version was created, got %s", err)
+	}
+}
+
+func Test_Verifier_ProviderVersionTags_RequireTheBrokerAndTheProviderVersion(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		ProviderVersionTags([]string{"ci"})
+
+	issues := v.Validate()
+	if len(issues) < 2 || !errors.Is(issues[0], errNoVersionForTags) || !errors.Is(issues[1], errNoBrokerForTags) {
+		t.Errorf("expected the missing provider version and broker errors, got %v", issues)
+	}
+}
+
+func Test_Verifier_DefaultProviderState_WrapsInteractionStates(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var calls []string
+	record := func(call string) Action {
+		return func() error {
+			calls = append(calls, call)
+			return nil
+		}
+	}
+	v := NewPactFileVerifier(record("default setup"), record("default teardown"), nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		DefaultProviderState("the database is seeded", record("seed"), record("unseed")).
+		ProviderState("there is a user with id {23}", record("setup"), record("teardown")).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"default setup", "seed", "setup", "teardown", "unseed", "default teardown",
+		"default setup", "seed", "unseed", "default teardown"}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func Test_Verifier_DefaultProviderState_SetupErrorStopsVerification(t *testing.T) {
+	seedErr := errors.New("seeding failed")
+	err := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		DefaultProviderState("the database is seeded", func() error { return seedErr }, nil).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard).
+		Verify()
+	if err != seedErr {
+		t.Errorf("expected the setup error, got %v", err)
+	}
+}
+
+func Test_Verifier_VerifyDescriptionPattern_VerifiesMatchingInteractions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.VerifyDescriptionPattern(`^get request .* \{2\d\}$`); err != nil {
+		t.Fatal(err)
+	}
+	if r := v.Result(); len(r.Interactions) != 1 || r.Interactions[0].Description != "get request for user with id {23}" {
+		t.Errorf("expected only the matching interaction, got %v", r.Interactions)
+	}
+
+	if err := v.VerifyDescriptionPattern("^post "); err != errNoFilteredInteractionsFound {
+		t.Errorf("expected %s, got %v", errNoFilteredInteractionsFound, err)
+	}
+}
+
+func Test_Verifier_VerifyDescriptionPattern_ThrowsError_InvalidPattern(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		SummaryWriter(ioutil.Discard)
+	if err := v.VerifyDescriptionPattern("get (user"); err == nil || !strings.Contains(err.Error(), "not a valid regular expression") {
+		t.Errorf("expected the invalid pattern error, got %v", err)
+	}
+}
+
+func Test_Verifier_SendsRecordedRequestBodyRegardlessOfRequestRules(t *testing.T) {
+	var body string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+
+	u, _ := url.Parse(s.URL)
+	v := NewPactFileVerifier(nil, nil, nil).
+		AddPact("./pact_examples/request_rules/orders_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if body != `{"id":1,"item":"book"}` {
+		t.Errorf("expected the recorded request body, got %s", body)
+	}
+}
+
+func Test_Verifier_SendsRecordedRequestHeadersRegardlessOfHeaderRules(t *testing.T) {
+	var header http.Header
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer s.Close()
+
+	u, _ := url.Parse(s.URL)
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		AddPact("./pact_examples/request_rules/tracing_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if id := header.Get("X-Correlation-Id"); id != "5a1c9e2f-3b7d-4c8a-9f01-6e2d4b8a7c35" {
+		t.Errorf("expected the recorded correlation id, got %s", id)
+	}
+	if version := header.Get("X-Api-Version"); version != "2" {
+		t.Errorf("expected the recorded api version, got %s", version)
+	}
+}
+
+func Test_Verifier_DetailedTimings_RecordsStageDurations(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	sleep := func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+	verifier := func(detailed bool) Verifier {
+		return NewPactFileVerifier(nil, nil, nil).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", sleep, sleep).
+			ProviderState("there is no user with id {200}", nil, nil).
+			DetailedTimings(detailed).
+			SummaryWriter(ioutil.Discard)
+	}
+
+	v := verifier(true)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	timings := v.Result().Interactions[0].Timings
+	if timings == nil {
+		t.Fatal("expected the timings to be recorded")
+	}
+	if timings.Setup < 10*time.Millisecond || timings.Teardown < 10*time.Millisecond {
+		t.Errorf("expected the setup and teardown durations to include the state actions, got %v and %v", timings.Setup, timings.Teardown)
+	}
+	if timings.Request <= 0 || timings.Request != v.Result().Interactions[0].Latency || timings.Matching <= 0 {
+		t.Errorf("expected the request and matching durations, got %v and %v", timings.Request, timings.Matching)
+	}
+
+	v = verifier(false)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if timings := v.Result().Interactions[0].Timings; timings != nil {
+		t.Errorf("expected no timings by default, got %v", timings)
+	}
+}
+
+func Test_Verifier_StateNameMapper_MapsHandlerAndStateChange(t *testing.T) {
+	var changes []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/provider-states", func(w http.ResponseWriter, r *http.Request) {
+		var change stateChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		changes = append(changes, change.Action+" "+change.State)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	stateURL, _ := url.Parse(server.URL + "/provider-states")
+
+	keys := map[string]string{"there is a user with id {23}": "user-23", "there is no user with id {200}": "no-user-200"}
+	handled := false
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("no-user-200", func() error {
+			handled = true
+			return nil
+		}, nil).
+		StateChangeURL(stateURL).
+		StateNameMapper(func(description string) string { return keys[description] }).
+		WarningsAsErrors(true).
+		SummaryWriter(ioutil.Discard)
+	if errs := v.Validate(); len(errs) != 0 {
+		t.Errorf("expected the mapped states to be valid, got %v", errs)
+	}
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !handled {
+		t.Error("expected the handler of the mapped state to be called")
+	}
+	if expected := []string{"setup user-23", "teardown user-23"}; fmt.Sprint(changes) != fmt.Sprint(expected) {
+		t.Errorf("expected the state changes %v, got %v", expected, changes)
+	}
+	if r := v.Result(); r.Interactions[0].State != "there is a user with id {23}" {
+		t.Errorf("expected the result to keep the recorded state, got %s", r.Interactions[0].State)
+	}
+}
+
+func Test_Verifier_RouteInteractions_SendsInteractionsToRoutedServers(t *testing.T) {
+	server := func(version int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"version": %d}`, version)
+		}))
+	}
+	v1, v2 := server(1), server(2)
+	defer v1.Close()
+	defer v2.Close()
+	u1, _ := url.Parse(v1.URL)
+	u2, _ := url.Parse(v2.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("gateway app").
+		PactUri("./pact_examples/routing/gateway_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u1).
+		RouteInteractions(func(path string) (*url.URL, error) {
+			if strings.HasPrefix(path, "/v2/") {
+				return u2, nil
+			}
+			return nil, nil
+		}).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := v.Result()
+	if len(r.Interactions) != 2 || r.Interactions[0].ProviderURL != u1.String() || r.Interactions[1].ProviderURL != u2.String() {
+		t.Errorf("expected the interactions to be verified against %s and %s, got %+v", u1, u2, r.Interactions)
+	}
+}
+
+func Test_Verifier_RouteInteractions_FailsWhenRouterFails(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("gateway app").
+		PactUri("./pact_examples/routing/gateway_app-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		RouteInteractions(func(path string) (*url.URL, error) {
+			return nil, errors.New("no route")
+		}).
+		SummaryWriter(ioutil.Discard)
+	err := v.Verify()
+	if err == nil || !strings.Contains(err.Error(), "no route") {
+		t.Errorf("expected the router error, got %v", err)
+	}
+}
+
+func Test_Verifier_ForbiddenResponseHeaders_FailsWhenPresent(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Debug-Token", "a1b2")
+		userHandlerWithValidData(w, r)
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	verify := func(forbidden ...string) (*VerificationResult, error) {
+		v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			ForbiddenResponseHeaders(forbidden).
+			SummaryWriter(ioutil.Discard)
+		err := v.Verify()
+		return v.Result(), err
+	}
+
+	if _, err := verify("X-Powered-By"); err != nil {
+		t.Errorf("expected the absent forbidden header to pass, got %s", err)
+	}
+	r, err := verify("x-debug-token")
+	if err != errVerficationFailed {
+		t.Fatalf("expected the verification to fail, got %v", err)
+	}
+	for _, i := range r.Interactions {
+		if len(i.Differences) != 1 || !strings.Contains(i.Differences.Error(), "forbidden header X-Debug-Token is present") {
+			t.Errorf("expected a forbidden header mismatch for %s, got %v", i.Description, i.Differences)
+		}
+	}
+}
+
+func Test_Verifier_SmokeMode_VerifiesOnlyStatusAndHeaders(t *testing.T) {
+	verify := func(handler http.HandlerFunc) (*VerificationResult, error) {
+		s := httptest.NewServer(handler)
+		defer s.Close()
+		u, _ := url.Parse(s.URL)
+		v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			SmokeMode(true).
+			SummaryWriter(ioutil.Discard)
+		err := v.Verify()
+		return v.Result(), err
+	}
+
+	r, err := verify(userHandlerWithMismatchedData)
+	if err != nil {
+		t.Fatalf("expected the body mismatch to pass in smoke mode, got %s", err)
+	}
+	if !r.Smoke || len(r.Notes) != 1 || r.Notes[0] != smokeModeNote {
+		t.Errorf("expected the result to note the bodies were not verified, got %v", r.Notes)
+	}
+	var b bytes.Buffer
+	if err := r.WriteJSONReport(&b); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(b.String(), `"smoke": true`) {
+		t.Errorf("expected the report to mark the smoke verification, got %s", b.String())
+	}
+
+	_, err = verify(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	if err != errVerficationFailed {
+		t.Errorf("expected the status mismatch to fail in smoke mode, got %v", err)
+	}
+}
+
+func Test_Verifier_SniffContentType_ReadsJSONWithoutContentType(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "chrome browser"},
+		"provider": {"name": "go api"},
+		"interactions": [
+			{
+				"description": "get the user",
+				"request": {"method": "GET", "path": "/user"},
+				"response": {"status": 200, "body": {"id": 23, "name": "alice"}}
+			}
+		],
+		"metadata": {"pactSpecification": {"version": "2.0.0"}}
+	}`
+	verify := func(contentType []string, sniff bool) error {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			//a nil content type stops the server from detecting one
+			w.Header()["Content-Type"] = contentType
+			fmt.Fprint(w, `{"id": 23, "name": "alice"}`)
+		}))
+		defer s.Close()
+		u, _ := url.Parse(s.URL)
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			PactReader(strings.NewReader(pact)).
+			ServiceProvider("go api", &http.Client{}, u).
+			SniffContentType(sniff).
+			SummaryWriter(ioutil.Discard).
+			Verify()
+	}
+
+	if err := verify(nil, true); err != nil {
+		t.Errorf("expected the body without a content type to match, got %s", err)
+	}
+	octetStream := []string{"application/octet-stream"}
+	if err := verify(octetStream, false); err != errVerficationFailed {
+		t.Errorf("expected the octet stream body to be compared as bytes by default, got %v", err)
+	}
+	if err := verify(octetStream, true); err != nil {
+		t.Errorf("expected the sniffed octet stream body to match, got %s", err)
+	}
+}
+
+func Test_Verifier_UnexpectedServerError_ReportsErrorBody(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error": "NullPointerException in UserRepository"}`))
+	}))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	l := &recordingLogger{}
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: l}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected the verification to fail, got %v", err)
+	}
+
+	i := v.Result().Interactions[0]
+	if i.ServerError != `{"error":"NullPointerException in UserRepository"}` || i.Reason != StatusMismatch {
+		t.Errorf("expected the server error body in the result, got %q %s", i.ServerError, i.Reason)
+	}
+	if !strings.Contains(i.Differences.Error(), "provider returned the server error 500, expected status 200") {
+		t.Errorf("expected a server error mismatch, got %s", i.Differences.Error())
+	}
+}
+
+func Test_Verifier_PactBroker_AddsBrokerMetadataToResult(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/latest", pactServer)
+	mux.HandleFunc("/pacts/provider/go api/for-verification", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_embedded": {"pacts": [{
+			"shortDescription": "latest from chrome browser",
+			"verificationProperties": {"pending": true, "notices": [{"when": "before_verification", "text": "This pact is pending"}]},
+			"_links": {"self": {"name": "Pact between chrome browser (1.0.1) and go api"}}
+		}]}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	l := &recordingLogger{}
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: l}).
+		HonoursPactWith("chrome browser").
+		PactBroker(server.URL, nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := v.Result().BrokerMetadata
+	if len(metadata) != 1 || !metadata[0].Pending || metadata[0].ShortDescription != "latest from chrome browser" ||
+		!strings.HasSuffix(metadata[0].PactUri, "/latest") {
+		t.Fatalf("expected the broker metadata of the pact, got %+v", metadata)
+	}
+	if !strings.Contains(strings.Join(l.lines, "\n"), "NOTICE (chrome browser): This pact is pending") {
+		t.Errorf("expected the notices to be logged, got %v", l.lines)
+	}
+}
+
+func Test_Verifier_Generators_SendsTheGeneratedValues(t *testing.T) {
+	pact := `{
+		"consumer": {"name": "billing app"},
+		"provider": {"name": "go api"},
+		"interactions": [
+			{
+				"description": "create an invoice",
+				"provider_state": "there is a user",
+				"request": {
+					"method": "POST", "path": "/users/1/invoices", "query": "page=1",
+					"headers": {"Content-Type": "application/json", "X-Request-Id": "recorded"},
+					"body": {"createdAt": "2000-01-01"},
+					"generators": {
+						"path": {"type": "ProviderState", "expression": "/users/${id}/invoices"},
+						"header": {"X-Request-Id": {"type": "RandomUuid"}},
+						"query": {"page": {"type": "ProviderState", "expression": "${page}"}},
+						"body": {"$.createdAt": {"type": "DateTime", "format": "yyyy-MM-dd"}}
+					}
+				},
+				"response": {"status": 201}
+			}
+		],
+		"metadata": {"pactSpecification": {"version": "3.0.0"}}
+	}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/users/42/invoices" || r.URL.Query().Get("page") != "3" ||
+			len(r.Header.Get("X-Request-Id")) != 36 || body["createdAt"] != time.Now().Format("2006-01-02") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	verify := func(ignore bool) error {
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			PactReader(strings.NewReader(pact)).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderStateWithValues("there is a user", func(ctx context.Context) (map[string]interface{}, error) {
+				return map[string]interface{}{"id": 42, "page": 3}, nil
+			}, nil).
+			IgnoreGenerators(ignore).
+			SummaryWriter(ioutil.Discard).
+			Verify()
+	}
+	if err := verify(false); err != nil {
+		t.Errorf("expected the generated values to be sent, got %s", err)
+	}
+	if err := verify(true); err == nil {
+		t.Error("expected the recorded values to be sent when the generators are ignored")
+	}
+}
+
+func Test_Verifier_RequireMinInteractions_FailsWhenFewerAreVerified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	verify := func(min int) error {
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			RequireMinInteractions(min).
+			SummaryWriter(ioutil.Discard).
+			Verify()
+	}
+	if err := verify(2); err != nil {
+		t.Errorf("expected the minimum to be met, got %s", err)
+	}
+	expected := fmt.Sprintf(errTooFewInteractionsMsg, 2, 3)
+	if err := verify(3); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+//publishingBroker serves the pact of chrome browser with its publish relation to the bearer token s3cret, the
+//published results are recorded
+func publishingBroker(status int, published *[]map[string]interface{}) *httptest.Server {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/latest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b, _ := ioutil.ReadFile("./pact_examples/chrome_browser-go_api.json")
+		var pact map[string]interface{}
+		json.Unmarshal(b, &pact)
+		pact["_links"] = map[string]interface{}{"pb:publish-verification-results": map[string]string{
+			"href": server.URL + "/pacts/provider/go%20api/consumer/chrome%20browser/pact-version/1a2b/verification-results"}}
+		json.NewEncoder(w).Encode(pact)
+	})
+	mux.HandleFunc("/pacts/provider/go api/consumer/chrome browser/pact-version/1a2b/verification-results", func(w http.ResponseWriter, r *http.Request) {
+		var results map[string]interface{}
+		if r.Method != "POST" || r.Header.Get("Authorization") != "Bearer s3cret" || json.NewDecoder(r.Body).Decode(&results) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*published = append(*published, results)
+		w.WriteHeader(status)
+	})
+	server = httptest.NewServer(mux)
+	return server
+}
+
+func verifyAndPublish(broker *httptest.Server, providerURL *url.URL) error {
+	return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactBroker(broker.URL, &BrokerAuth{Token: "s3cret"}).
+		ServiceProvider("go api", &http.Client{}, providerURL).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		PublishResults("1.0.0").
+		SummaryWriter(ioutil.Discard).
+		Verify()
+}
+
+func Test_Verifier_PublishResults_PostsTheResultsOfThePact(t *testing.T) {
+	for _, c := range []struct {
+		handler http.HandlerFunc
+		success bool
+	}{{userHandlerWithValidData, true}, {userHandlerWithMismatchedData, false}} {
+		var published []map[string]interface{}
+		broker := publishingBroker(http.StatusCreated, &published)
+		provider := httptest.NewServer(c.handler)
+		u, _ := url.Parse(provider.URL)
+
+		err := verifyAndPublish(broker, u)
+		broker.Close()
+		provider.Close()
+		if c.success && err != nil || !c.success && !errors.Is(err, errVerficationFailed) {
+			t.Fatalf("expected the verification to succeed %v, got %v", c.success, err)
+		}
+
+		if len(published) != 1 {
+			t.Fatalf("expected the results to be published once, got %v", published)
+		}
+		r := published[0]
+		if r["success"] != c.success || r["providerApplicationVersion"] != "1.0.0" {
+			t.Errorf("expected the success %v of the provider version, got %v", c.success, r)
+		}
+		if tests, _ := r["testResults"].([]interface{}); len(tests) != 2 {
+			t.Errorf("expected the results of the 2 interactions, got %v", r["testResults"])
+		}
+	}
+}
+
+func Test_Verifier_PublishResults_ReportsTheBrokerErrorsDistinctly(t *testing.T) {
+	var published []map[string]interface{}
+	broker := publishingBroker(http.StatusInternalServerError, &published)
+	defer broker.Close()
+	provider := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	defer provider.Close()
+	u, _ := url.Parse(provider.URL)
+
+	err := verifyAndPublish(broker, u)
+	if err == nil || errors.Is(err, errVerficationFailed) || !strings.Contains(err.Error(), "Failed to publish the verification results") {
+		t.Errorf("expected the publish error, got %v", err)
+	}
+}
+
+func Test_Verifier_PublishResults_NothingIsPublishedWhenTheProviderIsUnreachable(t *testing.T) {
+	var published []map[string]interface{}
+	broker := publishingBroker(http.StatusCreated, &published)
+	defer broker.Close()
+	provider := httptest.NewServer(http.HandlerFunc(userHandlerWithValidData))
+	u, _ := url.Parse(provider.URL)
+	provider.Close()
+
+	if err := verifyAndPublish(broker, u); err == nil || errors.Is(err, errVerficationFailed) {
+		t.Errorf("expected the request error, got %v", err)
+	}
+	if len(published) != 0 {
+		t.Errorf("expected nothing to be published, got %v", published)
+	}
+}
+
+func Test_Verifier_PublishResults_RequiresTheBroker(t *testing.T) {
+	v := NewPactFileVerifier(nil, nil, nil).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, &url.URL{Scheme: "http", Host: "localhost"}).
+		PublishResults("1.0.0")
+
+	if issues := v.Validate(); len(issues) == 0 || !errors.Is(issues[0], errNoBrokerForResults) {
+		t.Errorf("expected the broker to be required, got %v", issues)
+	}
+}
+
+func Test_Verifier_RequestFilter_ChangesEveryRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer live-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		userHandlerWithValidData(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var filtered []string
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		HonoursPactWith("chrome browser").
+		PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+		ServiceProvider("go api", &http.Client{}, u).
+		ProviderState("there is a user with id {23}", nil, nil).
+		ProviderState("there is no user with id {200}", nil, nil).
+		SummaryWriter(ioutil.Discard)
+	if err := v.Verify(); err != errVerficationFailed {
+		t.Fatalf("expected the unfiltered requests to fail, got %v", err)
+	}
+
+	v.RequestFilter(func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer live-token")
+		req.URL.Path = "/v1" + req.URL.Path
+		filtered = append(filtered, req.URL.RequestURI())
+	})
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifyState("", "there is a user with id {23}"); err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 3 || filtered[2] != "/v1/user?id=23" {
+		t.Errorf("expected every request to be filtered, got %v", filtered)
+	}
+}
+
+func Test_Verifier_ProviderStateWithParams_ReceivesTheStateParams(t *testing.T) {
+	var posted []map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithValidData)
+	mux.HandleFunc("/greetings", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		posted = append(posted, body)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var params map[string]interface{}
+	tornDown := false
+	verifier := func() Verifier {
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("web app").
+			PactUri("./pact_examples/spec/web_app-go_api-v4.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			SummaryWriter(ioutil.Discard)
+	}
+	v := verifier().ProviderStateWithParams("there is a user with id {23}", func(p map[string]interface{}) error {
+		params = p
+		return nil
+	}, func() error {
+		tornDown = true
+		return nil
+	})
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(params["id"]) != "23" || !tornDown {
+		t.Errorf("expected the setup to receive the params of the state, got %v", params)
+	}
+
+	stateURL, _ := url.Parse(server.URL + "/state")
+	if err := verifier().StateChangeURL(stateURL).Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 2 || fmt.Sprint(posted[0]["params"]) != "map[id:23]" {
+		t.Errorf("expected the params to be posted to the state change url, got %v", posted)
+	}
+}
+
+func Test_Verifier_Concurrency_VerifiesInteractionsSimultaneously(t *testing.T) {
+	var mu sync.Mutex
+	ready := make(map[string]bool)
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		setUp := ready[r.URL.Query().Get("id")]
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if !setUp {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var interactions []string
+	for n := 0; n < 8; n++ {
+		interactions = append(interactions, fmt.Sprintf(`{"description": "get item %d", "provider_state": "item %d exists",
+			"request": {"method": "GET", "path": "/items", "query": "id=%d"}, "response": {"status": 200}}`, n, n, n))
+	}
+	f, err := ioutil.TempFile("", "pact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	fmt.Fprintf(f, `{"consumer": {"name": "shop"}, "provider": {"name": "items api"}, "interactions": [%s],
+		"metadata": {"pactSpecificationVersion": "2.0.0"}}`, strings.Join(interactions, ","))
+	f.Close()
+
+	u, _ := url.Parse(server.URL)
+	v := NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+		AddPact(f.Name(), nil).
+		ServiceProvider("items api", &http.Client{}, u).
+		Concurrency(4).
+		SummaryWriter(ioutil.Discard)
+	for n := 0; n < 8; n++ {
+		id := strconv.Itoa(n)
+		v.ProviderState("item "+id+" exists", func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			ready[id] = true
+			return nil
+		}, nil)
+	}
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if maxInFlight < 2 || maxInFlight > 4 {
+		t.Errorf("expected up to 4 interactions to be verified at the same time, got %d", maxInFlight)
+	}
+	for n, i := range v.Result().Interactions {
+		if i.Description != fmt.Sprintf("get item %d", n) {
+			t.Errorf("expected the results in the order of the interactions, got %s at %d", i.Description, n)
+		}
+	}
+
+	errFirst, errLater := errors.New("item 2 failed"), errors.New("item 6 failed")
+	v.ProviderState("item 2 exists", func() error {
+		time.Sleep(30 * time.Millisecond)
+		return errFirst
+	}, nil).ProviderState("item 6 exists", func() error { return errLater }, nil)
+	if err := v.Verify(); err != errFirst {
+		t.Errorf("expected the error of the first failed interaction whatever the completion order, got %v", err)
+	}
+}
+
+func Test_Verifier_ReportFile_IsWrittenWhenVerificationFails(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", userHandlerWithMismatchedData)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "pact.xml")
+	verifier := func(path string) Verifier {
+		return NewPactFileVerifier(nil, nil, &VerfierConfig{Logger: &recordingLogger{}}).
+			HonoursPactWith("chrome browser").
+			PactUri("./pact_examples/chrome_browser-go_api.json", nil).
+			ServiceProvider("go api", &http.Client{}, u).
+			ProviderState("there is a user with id {23}", nil, nil).
+			ProviderState("there is no user with id {200}", nil, nil).
+			ReportFile(path, JUnitXML).
+			SummaryWriter(ioutil.Discard)
+	}
+	if err := verifier(path).Verify(); err != errVerficationFailed {
+		t.Fatalf("expected %s, got %v", errVerficationFailed, err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(b, &report); err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suites[0].Cases
+	if len(cases) != 2 || cases[0].Name != "get request for user with id {23} given there is a user with id {23}" {
+		t.Fatalf("expected a test case per interaction, got %s", b)
+	}
+	if cases[0].Failure == nil || !strings.Contains(cases[0].Failure.Content, `body path $.firstName expected "John" got "Jane"`) {
+		t.Errorf("expected the mismatches in the failure, got %s", b)
+	}
+
+	if err := verifier(filepath.Join(dir, "missing", "pact.xml")).Verify(); err != errVerficationFailed {
+		t.Errorf("expected the report error not to replace %s, got %v", errVerficationFailed, err)
+	}
+}